@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/sirupsen/logrus"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func runAPIKey(command string, args []string) {
+	apiKeyService := services.NewAPIKeyService(connectStore())
+	ctx := context.Background()
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	switch command {
+	case "create":
+		name := flags.String("name", "", "human readable key name")
+		scopes := flags.String("scopes", "", "comma separated list of scopes")
+		guilds := flags.String("guilds", "", "comma separated list of guild IDs, every guild if omitted")
+		duration := flags.Duration("duration", 0, "key lifetime, never expires if omitted")
+		_ = flags.Parse(args)
+		if *name == "" {
+			logrus.Fatalf("Key name is required")
+		}
+
+		var guildDiscordIDs []string
+		if *guilds != "" {
+			guildDiscordIDs = strings.Split(*guilds, ",")
+		}
+
+		key, plainKey, err := apiKeyService.Create(ctx, *name, strings.Split(*scopes, ","), guildDiscordIDs, *duration)
+		if err != nil {
+			logrus.Fatalf("Failed to create API key: %v", err.Error())
+		}
+		printAPIKey(key, plainKey)
+	case "list":
+		_ = flags.Parse(args)
+
+		keys, err := apiKeyService.List(ctx)
+		if err != nil {
+			logrus.Fatalf("Failed to list API keys: %v", err.Error())
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "PREFIX\tNAME\tSCOPES\tGUILDS\tEXPIRES\tREVOKED\tLAST USED")
+		for _, key := range keys {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", key.Prefix, key.Name, strings.Join(key.Scopes, ","), formatGuilds(key.GuildDiscordIDs),
+				formatTime(key.ExpiresAt.Time, key.ExpiresAt.Valid),
+				formatTime(key.RevokedAt.Time, key.RevokedAt.Valid),
+				formatTime(key.LastUsedAt.Time, key.LastUsedAt.Valid))
+		}
+		_ = w.Flush()
+	case "rotate":
+		prefix := flags.String("prefix", "", "prefix of the key")
+		_ = flags.Parse(args)
+
+		key, plainKey, err := apiKeyService.Rotate(ctx, *prefix)
+		if err != nil {
+			logrus.Fatalf("Failed to rotate API key: %v", err.Error())
+		}
+		printAPIKey(key, plainKey)
+	case "revoke":
+		prefix := flags.String("prefix", "", "prefix of the key")
+		_ = flags.Parse(args)
+
+		key, err := apiKeyService.Revoke(ctx, *prefix)
+		if err != nil {
+			logrus.Fatalf("Failed to revoke API key: %v", err.Error())
+		}
+		fmt.Printf("Revoked key %s (%s)\n", key.Prefix, key.Name)
+	default:
+		printUsage()
+	}
+}
+
+func printAPIKey(key db.ApiKey, plainKey string) {
+	fmt.Printf("Name:    %s\n", key.Name)
+	fmt.Printf("Scopes:  %s\n", strings.Join(key.Scopes, ", "))
+	fmt.Printf("Guilds:  %s\n", formatGuilds(key.GuildDiscordIDs))
+	fmt.Printf("Expires: %s\n", formatTime(key.ExpiresAt.Time, key.ExpiresAt.Valid))
+	fmt.Printf("Key:     %s\n", plainKey)
+	fmt.Println("Store the key securely, it will not be shown again")
+}
+
+func formatGuilds(guildDiscordIDs []string) string {
+	if len(guildDiscordIDs) == 0 {
+		return "*"
+	}
+	return strings.Join(guildDiscordIDs, ",")
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/sirupsen/logrus"
+)
+
+func runJWT(command string, args []string) {
+	switch command {
+	case "keygen":
+		publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			logrus.Fatalf("Failed to generate key: %v", err.Error())
+		}
+		fmt.Printf("JWT_EDDSA_SECRET_KEY=%s\n", base64.RawURLEncoding.EncodeToString(secretKey))
+		fmt.Printf("Public key: %s\n", base64.RawURLEncoding.EncodeToString(publicKey))
+		fmt.Printf("Key ID:     %s\n", token.JWKThumbprint(publicKey))
+		fmt.Println("On rotation append public key of the previous secret to JWT_EDDSA_PUBLIC_KEYS")
+	default:
+		printUsage()
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/apikey"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"os"
+	"strings"
+	"time"
+)
+
+const usage = `Sentinel admin tool
+
+Usage:
+  admin apikey create -name <name> -scopes <scope,...> [-guilds <guild_id,...>] [-duration <duration>]
+  admin apikey list
+  admin apikey rotate -prefix <prefix>
+  admin apikey revoke -prefix <prefix>
+  admin paseto keygen
+  admin jwt keygen
+
+Available scopes: %s
+`
+
+func main() {
+	if len(os.Args) < 3 {
+		printUsage()
+	}
+
+	switch os.Args[1] {
+	case "apikey":
+		runAPIKey(os.Args[2], os.Args[3:])
+	case "paseto":
+		runPaseto(os.Args[2], os.Args[3:])
+	case "jwt":
+		runJWT(os.Args[2], os.Args[3:])
+	default:
+		printUsage()
+	}
+}
+
+func printUsage() {
+	fmt.Printf(usage, strings.Join(apikey.Scopes, ", "))
+	os.Exit(2)
+}
+
+func connectStore() db.Store {
+	config, err := utils.LoadConfig()
+	if err != nil {
+		logrus.Fatalf("Failed to initialize config: %v", err.Error())
+	}
+
+	store, err := db.NewSQLStore(db.ConnectionConfig{
+		Driver:   config.DBDriver,
+		Protocol: config.DBProtocol,
+		Username: config.DBUsername,
+		Password: config.DBPassword,
+		Host:     config.DBHost,
+		Port:     config.DBPort,
+		Name:     config.DBName,
+		SSLMode:  config.DBSSLMode,
+	})
+	if err != nil {
+		logrus.Fatalf("Failed to connect to DB: %v", err.Error())
+	}
+	return store
+}
+
+func formatTime(t time.Time, valid bool) string {
+	if !valid {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/sirupsen/logrus"
+)
+
+func runPaseto(command string, args []string) {
+	switch command {
+	case "keygen":
+		publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			logrus.Fatalf("Failed to generate key: %v", err.Error())
+		}
+		fmt.Printf("PASETO_V4_SECRET_KEY=%s\n", token.EncodePaserkSecret(secretKey))
+		fmt.Printf("Public key: %s\n", token.EncodePaserkPublic(publicKey))
+		fmt.Printf("Key ID:     %s\n", token.PaserkPublicID(publicKey))
+		fmt.Println("On rotation append public key of the previous secret to PASETO_V4_PUBLIC_KEYS")
+	default:
+		printUsage()
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/controllers"
 	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
@@ -48,31 +49,76 @@ func main() {
 		logrus.Fatalf("Failed to connect to Memomry DB: %s", err.Error())
 	}
 
-	tokenMaker, err := token.NewPasetoMaker(config.PasetoSymmetricKey)
+	var tokenMaker token.Maker
+	switch config.TokenMaker {
+	case token.MakerPasetoV4Public:
+		tokenMaker, err = token.NewPasetoV4PublicMaker(config.PasetoV4SecretKey, config.PasetoV4PublicKeys)
+	case token.MakerJWTHS256:
+		tokenMaker, err = token.NewHS256JWTMaker(config.JWTSymmetricKey)
+	case token.MakerJWTEdDSA:
+		tokenMaker, err = token.NewEdDSAJWTMaker(config.JWTEdDSASecretKey, config.JWTEdDSAPublicKeys)
+	case token.MakerPasetoV2Local, "":
+		tokenMaker, err = token.NewPasetoMaker(config.PasetoSymmetricKey)
+	default:
+		logrus.Fatalf("Unsupported token maker: %s", config.TokenMaker)
+	}
 	if err != nil {
-		logrus.Fatalf("Failed to create PASeTo token maker: %v", err.Error())
+		logrus.Fatalf("Failed to create token maker: %v", err.Error())
 	}
 
+	if len(config.DiscordRedirectURLs) == 0 {
+		logrus.Fatalf("At least one redirect URL must be set in DISCORD_REDIRECT_URLS")
+	}
 	discordOauth2Service := services.NewDiscordOauth2Service(&oauth2.Config{
 		Endpoint:     discord.Endpoint,
 		Scopes:       []string{discord.ScopeIdentify, discord.ScopeEmail, discord.ScopeGuilds},
-		RedirectURL:  "http://localhost:5173/oauth2/discord_callback",
+		RedirectURL:  config.DiscordRedirectURLs[0],
 		ClientID:     config.DiscordClientID,
 		ClientSecret: config.DiscordClientSecret,
-	})
+	}, config.DiscordInviteBotRedirectURL)
+
+	userDataService := services.NewUserDataService(store, memStore)
+	if config.UserPurgeAfter > 0 && config.UserPurgeInterval > 0 {
+		go userDataService.RunPurge(context.Background(), config.UserPurgeInterval, config.UserPurgeAfter)
+	}
+	if config.GuildSweepInterval > 0 {
+		guildArchiveService := services.NewGuildArchiveService(store)
+		go guildArchiveService.RunSweep(context.Background(), config.GuildSweepInterval, config.GuildArchiveAfter, config.GuildDeleteAfter)
+	}
+	lockdownService := services.NewLockdownService(store, memStore)
+	if config.SchedulerInterval > 0 {
+		actionScheduler := services.NewActionScheduler(store, memStore)
+		go actionScheduler.Run(context.Background(), config.SchedulerInterval)
+		go lockdownService.RunLift(context.Background(), config.SchedulerInterval)
+		memberEventService := services.NewMemberEventService(store)
+		go memberEventService.RunPurge(context.Background(), config.SchedulerInterval)
+	}
+
+	escalationService := services.NewEscalationService(store)
 
 	controllersV1 := controllers.Controllers{
-		User:        controllers.NewUserController(store),
-		Auth:        controllers.NewAuthController(store, memStore, config, tokenMaker),
-		Guild:       controllers.NewGuildController(store),
-		GuildConfig: controllers.NewGuildConfigController(store),
-		Oauth2:      controllers.NewOauth2Controller(store, memStore, config, tokenMaker, discordOauth2Service),
+		User:         controllers.NewUserController(store, userDataService),
+		Auth:         controllers.NewAuthController(store, memStore, config, tokenMaker),
+		Guild:        controllers.NewGuildController(store),
+		GuildConfig:  controllers.NewGuildConfigController(store, memStore),
+		Oauth2:       controllers.NewOauth2Controller(store, memStore, config, tokenMaker, discordOauth2Service),
+		Events:       controllers.NewEventsController(store, memStore, config),
+		Bot:          controllers.NewBotController(store, memStore),
+		Case:         controllers.NewCaseController(store, memStore),
+		Automod:      controllers.NewAutomodController(store, memStore),
+		Escalation:   controllers.NewEscalationController(escalationService),
+		Member:       controllers.NewMemberController(store, escalationService),
+		Appeal:       controllers.NewAppealController(store, memStore),
+		Verification: controllers.NewVerificationController(store, memStore),
+		Raid:         controllers.NewRaidController(store, memStore, lockdownService),
+		Lockdown:     controllers.NewLockdownController(store, lockdownService),
+		WellKnown:    controllers.NewWellKnownController(tokenMaker),
 	}
 	middlewaresV1 := middlewares.Middlewares{
 		CORS: cors.New(cors.Config{
-			AllowAllOrigins:        true,
+			AllowOrigins:           config.AllowedOrigins(),
 			AllowMethods:           []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-			AllowHeaders:           []string{"Content-Type", "Origin", "Access-Control-Allow-Origin", "Authorization", "Accept", "Accept-Encoding"},
+			AllowHeaders:           []string{"Content-Type", "Origin", "Access-Control-Allow-Origin", "Authorization", "Accept", "Accept-Encoding", middlewares.CSRFTokenHeaderKey},
 			AllowCredentials:       true,
 			ExposeHeaders:          []string{"Content-Length"},
 			MaxAge:                 12 * time.Hour,
@@ -80,9 +126,14 @@ func main() {
 			AllowWebSockets:        true,
 			AllowFiles:             true,
 		}),
-		Auth: middlewares.NewAuthMiddleware(tokenMaker),
+		Auth:       middlewares.NewAuthMiddleware(tokenMaker),
+		StreamAuth: middlewares.NewStreamAuthMiddleware(memStore, tokenMaker),
+		UserOnly:   middlewares.NewUserOnlyMiddleware(),
+		APIKey:     middlewares.NewAPIKeyMiddleware(store),
+		Scope:      middlewares.NewScopeMiddleware,
 		Permissions: middlewares.Permissions{
 			GuildConfig: permissions.NewGuildConfigPermissions(store),
+			Cases:       permissions.NewCasePermissions(store),
 		},
 	}
 
@@ -0,0 +1,582 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/appeal"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+const defaultAppealsLimit = 25
+
+var (
+	errNotBanned       = errors.New("you are not banned in the guild")
+	errAppealsDisabled = errors.New("guild does not accept appeals")
+	errAppealPending   = errors.New("appeal of the ban is already pending")
+	errAppealAccepted  = errors.New("appeal of the ban has already been accepted")
+	errAppealCooldown  = errors.New("denied appeal can not be submitted again yet")
+	errAppealDecided   = errors.New("appeal has already been decided")
+)
+
+type AppealController struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewAppealController(store db.Store, memStore memdb.Store) *AppealController {
+	return &AppealController{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+type ResponseAppeal struct {
+	ID             int64             `json:"id"`
+	GuildDiscordID string            `json:"guild_discord_id"`
+	UserDiscordID  string            `json:"user_discord_id"`
+	Answers        map[string]string `json:"answers"`
+	Status         string            `json:"status"`
+	// ReviewerDiscordID is hidden from the appealing user
+	ReviewerDiscordID *string    `json:"reviewer_discord_id,omitempty"`
+	DecisionReason    string     `json:"decision_reason"`
+	DecidedAt         *time.Time `json:"decided_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func newResponseAppeal(a db.Appeal) ResponseAppeal {
+	var answers map[string]string
+	_ = json.Unmarshal(a.Answers, &answers)
+
+	var reviewer *string
+	if a.ReviewerDiscordID.Valid {
+		reviewer = &a.ReviewerDiscordID.String
+	}
+	return ResponseAppeal{
+		ID:                a.ID,
+		GuildDiscordID:    a.GuildDiscordID,
+		UserDiscordID:     a.UserDiscordID,
+		Answers:           answers,
+		Status:            a.Status,
+		ReviewerDiscordID: reviewer,
+		DecisionReason:    a.DecisionReason,
+		DecidedAt:         nullTimeToPtr(a.DecidedAt),
+		CreatedAt:         a.CreatedAt,
+	}
+}
+
+type ResponseAppealComment struct {
+	ID       int64 `json:"id"`
+	AppealID int64 `json:"appeal_id"`
+	// AuthorDiscordID is null if the author deleted the account
+	AuthorDiscordID *string   `json:"author_discord_id"`
+	Content         string    `json:"content"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func newResponseAppealComment(comment db.AppealComment) ResponseAppealComment {
+	return ResponseAppealComment{
+		ID:              comment.ID,
+		AppealID:        comment.AppealID,
+		AuthorDiscordID: nullStringToPtr(comment.AuthorDiscordID),
+		Content:         comment.Content,
+		CreatedAt:       comment.CreatedAt,
+	}
+}
+
+func newUserResponseAppeal(a db.Appeal) ResponseAppeal {
+	res := newResponseAppeal(a)
+	res.ReviewerDiscordID = nil
+	return res
+}
+
+type ResponseBan struct {
+	GuildDiscordID string     `json:"guild_discord_id"`
+	GuildName      string     `json:"guild_name"`
+	GuildIcon      string     `json:"guild_icon"`
+	Reason         string     `json:"reason"`
+	BannedAt       time.Time  `json:"banned_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	AppealsEnabled bool       `json:"appeals_enabled"`
+	// Questions of the appeal form, empty when the guild does not accept appeals
+	Questions []objects.AppealQuestion `json:"questions"`
+	// Appeal is the latest appeal of the ban
+	Appeal    *ResponseAppeal `json:"appeal"`
+	CanAppeal bool            `json:"can_appeal"`
+	// CanAppealAt is set while a denied appeal is in cooldown
+	CanAppealAt *time.Time `json:"can_appeal_at,omitempty"`
+}
+
+// GetUserBans lists guilds where the user is banned, together with appeal forms of those guilds
+func (ctrl *AppealController) GetUserBans(c *gin.Context) {
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	banCases, err := ctrl.store.GetTargetLatestBanCases(c, payload.UserDiscordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+	bans := make([]ResponseBan, 0, len(banCases))
+	for _, banCase := range banCases {
+		if !isActiveBan(banCase, now) {
+			continue
+		}
+
+		guild, err := ctrl.store.GetGuild(c, banCase.GuildDiscordID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		config, err := ctrl.appealsConfig(c, banCase.GuildDiscordID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		latest, err := ctrl.latestAppeal(c, banCase.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		ban := ResponseBan{
+			GuildDiscordID: guild.DiscordID,
+			GuildName:      guild.Name,
+			GuildIcon:      guild.Icon,
+			Reason:         banCase.Reason,
+			BannedAt:       banCase.CreatedAt,
+			ExpiresAt:      nullTimeToPtr(banCase.ExpiresAt),
+			AppealsEnabled: config.Enabled,
+			Questions:      []objects.AppealQuestion{},
+		}
+		if config.Enabled {
+			ban.Questions = config.Questions
+		}
+		if latest != nil {
+			res := newUserResponseAppeal(*latest)
+			ban.Appeal = &res
+		}
+		switch err := checkAppealable(config, latest, now); {
+		case err == nil:
+			ban.CanAppeal = true
+		case errors.Is(err, errAppealCooldown):
+			canAppealAt := appeal.CanResubmitAt(config, latest.DecidedAt.Time)
+			ban.CanAppealAt = &canAppealAt
+		}
+		bans = append(bans, ban)
+	}
+
+	c.JSON(http.StatusOK, bans)
+}
+
+// SubmitAppeal files an appeal of the active ban of the user in the guild
+func (ctrl *AppealController) SubmitAppeal(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.SubmitAppealJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	banCases, err := ctrl.store.GetTargetLatestBanCases(c, payload.UserDiscordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	now := time.Now()
+	var banCase *db.ModerationCase
+	for i := range banCases {
+		if banCases[i].GuildDiscordID == uri.DiscordID && isActiveBan(banCases[i], now) {
+			banCase = &banCases[i]
+		}
+	}
+	if banCase == nil {
+		c.JSON(http.StatusNotFound, errorResponse(errNotBanned))
+		return
+	}
+
+	config, err := ctrl.appealsConfig(c, uri.DiscordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	latest, err := ctrl.latestAppeal(c, banCase.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err := checkAppealable(config, latest, now); err != nil {
+		if errors.Is(err, errAppealsDisabled) {
+			c.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	answers, err := appeal.NormalizeAnswers(config, form.Answers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	answersJSON, _ := json.Marshal(answers)
+
+	created, err := ctrl.store.CreateAppeal(c, db.CreateAppealParams{
+		GuildDiscordID: uri.DiscordID,
+		CaseID:         banCase.ID,
+		UserDiscordID:  payload.UserDiscordID,
+		Answers:        answersJSON,
+	})
+	if err != nil {
+		// only one appeal of a ban may be pending, a concurrent submission won
+		if db.IsUniqueViolation(err) {
+			c.JSON(http.StatusConflict, errorResponse(errAppealPending))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctrl.publishAppealEvent(c, memdb.GuildEventAppealCreated, uri.DiscordID, newResponseAppeal(created))
+	c.JSON(http.StatusCreated, newUserResponseAppeal(created))
+}
+
+// GetUserAppeals lists appeals submitted by the user from the newest
+func (ctrl *AppealController) GetUserAppeals(c *gin.Context) {
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	appeals, err := ctrl.store.GetUserAppeals(c, payload.UserDiscordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := make([]ResponseAppeal, 0, len(appeals))
+	for _, a := range appeals {
+		res = append(res, newUserResponseAppeal(a))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetAppeals lists appeals of the guild from the newest, next page starts before the id returned in next_before
+func (ctrl *AppealController) GetAppeals(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	_ = c.ShouldBindUri(&uri)
+	var query forms.GetAppealsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultAppealsLimit
+	}
+
+	appeals, err := ctrl.store.GetGuildAppeals(c, db.GetGuildAppealsParams{
+		GuildDiscordID: uri.DiscordID,
+		Status:         query.Status,
+		BeforeID:       query.Before,
+		MaxResults:     query.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := make([]ResponseAppeal, 0, len(appeals))
+	for _, a := range appeals {
+		res = append(res, newResponseAppeal(a))
+	}
+
+	var nextBefore *int64
+	if len(res) == int(query.Limit) {
+		nextBefore = &res[len(res)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"appeals":     res,
+		"next_before": nextBefore,
+	})
+}
+
+// GetAppeal returns the appeal with the appealed ban case and moderator comments
+func (ctrl *AppealController) GetAppeal(c *gin.Context) {
+	var uri forms.AppealURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	a, err := ctrl.store.GetAppeal(c, db.GetAppealParams{
+		ID:             uri.AppealID,
+		GuildDiscordID: uri.DiscordID,
+	})
+	if err != nil {
+		ctrl.appealError(c, err)
+		return
+	}
+
+	banCase, err := ctrl.store.GetModerationCaseByID(c, a.CaseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	comments, err := ctrl.store.GetAppealComments(c, a.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	res := make([]ResponseAppealComment, 0, len(comments))
+	for _, comment := range comments {
+		res = append(res, newResponseAppealComment(comment))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"appeal":   newResponseAppeal(a),
+		"case":     newResponseCase(banCase),
+		"comments": res,
+	})
+}
+
+// CreateAppealComment adds a moderator comment to the appeal, the appealing user does not see comments
+func (ctrl *AppealController) CreateAppealComment(c *gin.Context) {
+	var uri forms.AppealURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.AppealCommentJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	a, err := ctrl.store.GetAppeal(c, db.GetAppealParams{
+		ID:             uri.AppealID,
+		GuildDiscordID: uri.DiscordID,
+	})
+	if err != nil {
+		ctrl.appealError(c, err)
+		return
+	}
+
+	comment, err := ctrl.store.CreateAppealComment(c, db.CreateAppealCommentParams{
+		AppealID:        a.ID,
+		AuthorDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+		Content:         form.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, newResponseAppealComment(comment))
+}
+
+// DecideAppeal accepts or denies the pending appeal.
+// Accepting records an unban case and schedules the unban for the bot right away.
+func (ctrl *AppealController) DecideAppeal(c *gin.Context) {
+	var uri forms.AppealURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.DecideAppealJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	status := db.AppealStatusDenied
+	if form.Decision == "accept" {
+		status = db.AppealStatusAccepted
+	}
+
+	var decided db.Appeal
+	var unbanCase *db.ModerationCase
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		decided, err = q.DecideAppeal(c, db.DecideAppealParams{
+			ID:                uri.AppealID,
+			GuildDiscordID:    uri.DiscordID,
+			Status:            status,
+			ReviewerDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+			DecisionReason:    form.Reason,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// tell a missing appeal apart from an already decided one
+			if _, err := q.GetAppeal(c, db.GetAppealParams{ID: uri.AppealID, GuildDiscordID: uri.DiscordID}); err != nil {
+				return err
+			}
+			return errAppealDecided
+		}
+		if err != nil {
+			return err
+		}
+
+		if status == db.AppealStatusAccepted {
+			unbanCase, err = ctrl.unban(c, q, decided, payload.UserDiscordID, form.Reason)
+			if err != nil {
+				return err
+			}
+		}
+
+		data, err := json.Marshal(newResponseAppeal(decided))
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			ActorDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+			GuildDiscordID: uri.DiscordID,
+			Action:         db.AuditActionAppealDecide,
+			Data:           data,
+		})
+		return err
+	})
+	if err != nil {
+		ctrl.appealError(c, err)
+		return
+	}
+
+	res := newResponseAppeal(decided)
+	ctrl.publishAppealEvent(c, memdb.GuildEventAppealDecided, uri.DiscordID, res)
+	if unbanCase != nil {
+		ctrl.publishAppealEvent(c, memdb.GuildEventCaseCreated, uri.DiscordID, newResponseCase(*unbanCase))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// unban records unban case of the accepted appeal and schedules its delivery to the bot
+func (ctrl *AppealController) unban(c *gin.Context, q *db.Queries, accepted db.Appeal, moderatorDiscordID, reason string) (*db.ModerationCase, error) {
+	caseReason := fmt.Sprintf("Appeal #%d accepted", accepted.ID)
+	if reason != "" {
+		caseReason += ": " + reason
+	}
+
+	unbanCase, err := q.CreateModerationCase(c, db.CreateModerationCaseParams{
+		GuildDiscordID:     accepted.GuildDiscordID,
+		Action:             db.CaseActionUnban,
+		TargetDiscordID:    accepted.UserDiscordID,
+		ModeratorDiscordID: moderatorDiscordID,
+		Reason:             caseReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// expiry of a temporary ban is superseded by the immediate unban
+	_, err = q.CancelTargetScheduledActions(c, db.CancelTargetScheduledActionsParams{
+		GuildDiscordID:  accepted.GuildDiscordID,
+		TargetDiscordID: accepted.UserDiscordID,
+		Action:          db.ScheduledActionUnban,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.ScheduleCaseAction(c, db.ScheduleCaseActionParams{
+		GuildDiscordID:  accepted.GuildDiscordID,
+		CaseID:          unbanCase.ID,
+		Action:          db.ScheduledActionUnban,
+		TargetDiscordID: accepted.UserDiscordID,
+		RunAt:           time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &unbanCase, nil
+}
+
+func (ctrl *AppealController) appealsConfig(c *gin.Context, guildDiscordID string) (objects.AppealsConfig, error) {
+	guildConfig, err := ctrl.store.GetGuildConfig(c, guildDiscordID)
+	if err != nil {
+		return objects.AppealsConfig{}, err
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return objects.AppealsConfig{}, err
+	}
+	return guildConfigObj.Data.Appeals, nil
+}
+
+// latestAppeal returns nil if the ban case was never appealed
+func (ctrl *AppealController) latestAppeal(c *gin.Context, caseID int64) (*db.Appeal, error) {
+	latest, err := ctrl.store.GetLatestCaseAppeal(c, caseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &latest, nil
+}
+
+func (ctrl *AppealController) appealError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, errAppealDecided):
+		c.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// publishAppealEvent shows the appeal or its unban case to subscribers who can read cases,
+// answers of banned users are not visible to everyone who can read guild config
+func (ctrl *AppealController) publishAppealEvent(c *gin.Context, eventType, guildDiscordID string, res interface{}) {
+	data, _ := json.Marshal(res)
+	err := ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           eventType,
+		GuildDiscordID: guildDiscordID,
+		Capability:     token.CapabilityCasesRead,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish appeal event: %v", err.Error())
+	}
+}
+
+// isActiveBan reports whether the newest ban or unban case of the target still bans them
+func isActiveBan(banCase db.ModerationCase, now time.Time) bool {
+	if banCase.Action != db.CaseActionBan {
+		return false
+	}
+	return !banCase.ExpiresAt.Valid || banCase.ExpiresAt.Time.After(now)
+}
+
+// checkAppealable reports why the ban with the given latest appeal can not be appealed now
+func checkAppealable(config objects.AppealsConfig, latest *db.Appeal, now time.Time) error {
+	switch {
+	case !config.Enabled:
+		return errAppealsDisabled
+	case latest == nil:
+		return nil
+	case latest.Status == db.AppealStatusPending:
+		return errAppealPending
+	case latest.Status == db.AppealStatusAccepted:
+		return errAppealAccepted
+	case now.Before(appeal.CanResubmitAt(config, latest.DecidedAt.Time)):
+		return errAppealCooldown
+	}
+	return nil
+}
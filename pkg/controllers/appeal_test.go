@@ -0,0 +1,785 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func generateBanCase(guildDiscordID, targetDiscordID string) db.ModerationCase {
+	banCase := generateRandomCase(guildDiscordID, int64(utils.RandomInt(1, 100)))
+	banCase.Action = db.CaseActionBan
+	banCase.TargetDiscordID = targetDiscordID
+	return banCase
+}
+
+func generateRandomAppeal(banCase db.ModerationCase) db.Appeal {
+	return db.Appeal{
+		ID:             int64(utils.RandomInt(1, 1000)),
+		GuildDiscordID: banCase.GuildDiscordID,
+		CaseID:         banCase.ID,
+		UserDiscordID:  banCase.TargetDiscordID,
+		Answers:        json.RawMessage(`{"reason":"sorry"}`),
+		Status:         db.AppealStatusPending,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// newAppealRouter routes appeal endpoints of banned users and moderators
+func newAppealRouter(store *mockdb.MockStore, memStore *mockmemdb.MockStore) *gin.Engine {
+	appealController := NewAppealController(store, memStore)
+	router := newAuthorizedRouter()
+	router.GET("/api/v1/users/me/bans", appealController.GetUserBans)
+	router.POST("/api/v1/users/me/bans/:discord_id/appeals", appealController.SubmitAppeal)
+	router.GET("/api/v1/users/me/appeals", appealController.GetUserAppeals)
+	router.GET("/api/v1/guilds/:discord_id/appeals", appealController.GetAppeals)
+	router.GET("/api/v1/guilds/:discord_id/appeals/:appeal_id", appealController.GetAppeal)
+	router.POST("/api/v1/guilds/:discord_id/appeals/:appeal_id/comments", appealController.CreateAppealComment)
+	router.POST("/api/v1/guilds/:discord_id/appeals/:appeal_id/decision", appealController.DecideAppeal)
+	return router
+}
+
+func TestAppealController_GetUserBans(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	userDiscordID := utils.RandomSnowflakeID().String()
+	banCase := generateBanCase(guild.DiscordID, userDiscordID)
+	expiredBan := generateBanCase(utils.RandomSnowflakeID().String(), userDiscordID)
+	expiredBan.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+	unban := generateBanCase(utils.RandomSnowflakeID().String(), userDiscordID)
+	unban.Action = db.CaseActionUnban
+	guildConfig := generateGuildConfig(t, func(config *objects.GuildConfig) {
+		config.Data.Appeals.Enabled = true
+	})
+	disabledGuildConfig := generateGuildConfig(t, func(config *objects.GuildConfig) {
+		config.Data.Appeals.Enabled = false
+	})
+
+	deniedAppeal := generateRandomAppeal(banCase)
+	deniedAppeal.Status = db.AppealStatusDenied
+	deniedAppeal.ReviewerDiscordID = sql.NullString{String: utils.RandomSnowflakeID().String(), Valid: true}
+	deniedAppeal.DecidedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTargetLatestBanCases(gomock.Any(), gomock.Eq(userDiscordID)).
+					Times(1).
+					Return([]db.ModerationCase{banCase, expiredBan, unban}, nil)
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID, Name: guild.Name, Icon: guild.Icon}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetLatestCaseAppeal(gomock.Any(), gomock.Eq(banCase.ID)).
+					Times(1).
+					Return(db.Appeal{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res []ResponseBan
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res, 1)
+				require.Equal(t, guild.Name, res[0].GuildName)
+				require.Equal(t, banCase.Reason, res[0].Reason)
+				require.True(t, res[0].AppealsEnabled)
+				require.Equal(t, objects.NewDefaultGuildConfig().Data.Appeals.Questions, res[0].Questions)
+				require.Nil(t, res[0].Appeal)
+				require.True(t, res[0].CanAppeal)
+			},
+		},
+		{
+			name: "OK/Cooldown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTargetLatestBanCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ModerationCase{banCase}, nil)
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetLatestCaseAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(deniedAppeal, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res []ResponseBan
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res, 1)
+				require.False(t, res[0].CanAppeal)
+				require.NotNil(t, res[0].CanAppealAt)
+				require.WithinDuration(t, deniedAppeal.DecidedAt.Time.AddDate(0, 0, 30), *res[0].CanAppealAt, time.Second)
+				require.NotNil(t, res[0].Appeal)
+				require.Equal(t, db.AppealStatusDenied, res[0].Appeal.Status)
+				require.Nil(t, res[0].Appeal.ReviewerDiscordID)
+			},
+		},
+		{
+			name: "OK/AppealsDisabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTargetLatestBanCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ModerationCase{banCase}, nil)
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(disabledGuildConfig, nil)
+				store.EXPECT().
+					GetLatestCaseAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Appeal{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res []ResponseBan
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res, 1)
+				require.False(t, res[0].AppealsEnabled)
+				require.Empty(t, res[0].Questions)
+				require.False(t, res[0].CanAppeal)
+			},
+		},
+		{
+			name: "InternalServerError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTargetLatestBanCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			w := serveAuthorized(t, newAppealRouter(store, memStore), userDiscordID, http.MethodGet, "/api/v1/users/me/bans", nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAppealController_SubmitAppeal(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	userDiscordID := utils.RandomSnowflakeID().String()
+	banCase := generateBanCase(guild.DiscordID, userDiscordID)
+	pendingAppeal := generateRandomAppeal(banCase)
+	deniedAppeal := generateRandomAppeal(banCase)
+	deniedAppeal.Status = db.AppealStatusDenied
+	deniedAppeal.DecidedAt = sql.NullTime{Time: time.Now().AddDate(0, 0, -31), Valid: true}
+	recentlyDeniedAppeal := deniedAppeal
+	recentlyDeniedAppeal.DecidedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	formJSON, err := json.Marshal(forms.SubmitAppealJSON{Answers: map[string]string{"reason": "  sorry  "}})
+	require.NoError(t, err)
+	unknownQuestionJSON, err := json.Marshal(forms.SubmitAppealJSON{Answers: map[string]string{"bribe": "100$"}})
+	require.NoError(t, err)
+
+	// buildBanStubs expects lookup of the ban, the config and the latest appeal of the ban
+	buildBanStubs := func(store *mockdb.MockStore, enabled bool, latest db.Appeal, latestErr error) {
+		store.EXPECT().
+			GetTargetLatestBanCases(gomock.Any(), gomock.Eq(userDiscordID)).
+			Times(1).
+			Return([]db.ModerationCase{banCase}, nil)
+		store.EXPECT().
+			GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+			Times(1).
+			Return(generateGuildConfig(t, func(config *objects.GuildConfig) {
+				config.Data.Appeals.Enabled = enabled
+			}), nil)
+		store.EXPECT().
+			GetLatestCaseAppeal(gomock.Any(), gomock.Eq(banCase.ID)).
+			Times(1).
+			Return(latest, latestErr)
+	}
+
+	testCases := []struct {
+		name          string
+		guildID       string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			guildID: guild.DiscordID,
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, true, db.Appeal{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Eq(db.CreateAppealParams{
+						GuildDiscordID: guild.DiscordID,
+						CaseID:         banCase.ID,
+						UserDiscordID:  userDiscordID,
+						Answers:        json.RawMessage(`{"reason":"sorry"}`),
+					})).
+					Times(1).
+					Return(pendingAppeal, nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventAppealCreated, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, w.Code)
+
+				var res ResponseAppeal
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, pendingAppeal.ID, res.ID)
+				require.Equal(t, map[string]string{"reason": "sorry"}, res.Answers)
+			},
+		},
+		{
+			name:    "OK/CooldownPassed",
+			guildID: guild.DiscordID,
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, true, deniedAppeal, nil)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(pendingAppeal, nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, w.Code)
+			},
+		},
+		{
+			name:    "NotFound/NotBanned",
+			guildID: utils.RandomSnowflakeID().String(),
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetTargetLatestBanCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ModerationCase{banCase}, nil)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:    "Forbidden/AppealsDisabled",
+			guildID: guild.DiscordID,
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, false, db.Appeal{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:    "Conflict/Pending",
+			guildID: guild.DiscordID,
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, true, pendingAppeal, nil)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name:    "Conflict/Cooldown",
+			guildID: guild.DiscordID,
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, true, recentlyDeniedAppeal, nil)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name:    "Conflict/ConcurrentSubmission",
+			guildID: guild.DiscordID,
+			body:    formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, true, db.Appeal{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Appeal{}, &pq.Error{Code: "23505"})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name:    "BadRequest/UnknownQuestion",
+			guildID: guild.DiscordID,
+			body:    unknownQuestionJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				buildBanStubs(store, true, db.Appeal{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAppeal(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:    "BadRequest/NoAnswers",
+			guildID: guild.DiscordID,
+			body:    []byte(`{}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetTargetLatestBanCases(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			url := fmt.Sprintf("/api/v1/users/me/bans/%s/appeals", tc.guildID)
+			w := serveAuthorized(t, newAppealRouter(store, memStore), userDiscordID, http.MethodPost, url, tc.body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAppealController_GetAppeals(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	appeal := generateRandomAppeal(generateBanCase(guild.DiscordID, utils.RandomSnowflakeID().String()))
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?status=pending&before=500&limit=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildAppeals(gomock.Any(), gomock.Eq(db.GetGuildAppealsParams{
+						GuildDiscordID: guild.DiscordID,
+						Status:         db.AppealStatusPending,
+						BeforeID:       500,
+						MaxResults:     1,
+					})).
+					Times(1).
+					Return([]db.Appeal{appeal}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Appeals    []ResponseAppeal `json:"appeals"`
+					NextBefore *int64           `json:"next_before"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res.Appeals, 1)
+				require.Equal(t, appeal.ID, res.Appeals[0].ID)
+				require.NotNil(t, res.NextBefore)
+				require.Equal(t, appeal.ID, *res.NextBefore)
+			},
+		},
+		{
+			name:  "BadRequest/Status",
+			query: "?status=forgotten",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildAppeals(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/appeals%s", guild.DiscordID, tc.query)
+			w := serveAuthorized(t, newAppealRouter(store, memStore), utils.RandomSnowflakeID().String(), http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAppealController_GetAppeal(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	banCase := generateBanCase(guild.DiscordID, utils.RandomSnowflakeID().String())
+	appeal := generateRandomAppeal(banCase)
+	comment := db.AppealComment{
+		ID:              1,
+		AppealID:        appeal.ID,
+		AuthorDiscordID: sql.NullString{String: utils.RandomSnowflakeID().String(), Valid: true},
+		Content:         utils.RandomString(20),
+		CreatedAt:       time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAppeal(gomock.Any(), gomock.Eq(db.GetAppealParams{ID: appeal.ID, GuildDiscordID: guild.DiscordID})).
+					Times(1).
+					Return(appeal, nil)
+				store.EXPECT().
+					GetModerationCaseByID(gomock.Any(), gomock.Eq(banCase.ID)).
+					Times(1).
+					Return(banCase, nil)
+				store.EXPECT().
+					GetAppealComments(gomock.Any(), gomock.Eq(appeal.ID)).
+					Times(1).
+					Return([]db.AppealComment{comment}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Appeal   ResponseAppeal          `json:"appeal"`
+					Case     ResponseCase            `json:"case"`
+					Comments []ResponseAppealComment `json:"comments"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, appeal.ID, res.Appeal.ID)
+				require.Equal(t, banCase.CaseNumber, res.Case.CaseNumber)
+				require.Len(t, res.Comments, 1)
+				require.Equal(t, comment.Content, res.Comments[0].Content)
+				require.Equal(t, comment.AuthorDiscordID.String, *res.Comments[0].AuthorDiscordID)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Appeal{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetAppealComments(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/appeals/%d", guild.DiscordID, appeal.ID)
+			w := serveAuthorized(t, newAppealRouter(store, memStore), utils.RandomSnowflakeID().String(), http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAppealController_CreateAppealComment(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	appeal := generateRandomAppeal(generateBanCase(guild.DiscordID, utils.RandomSnowflakeID().String()))
+	moderatorDiscordID := utils.RandomSnowflakeID().String()
+
+	formJSON, err := json.Marshal(forms.AppealCommentJSON{Content: "looks sincere"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(appeal, nil)
+				store.EXPECT().
+					CreateAppealComment(gomock.Any(), gomock.Eq(db.CreateAppealCommentParams{
+						AppealID:        appeal.ID,
+						AuthorDiscordID: sql.NullString{String: moderatorDiscordID, Valid: true},
+						Content:         "looks sincere",
+					})).
+					Times(1).
+					Return(db.AppealComment{ID: 1, AppealID: appeal.ID, AuthorDiscordID: sql.NullString{String: moderatorDiscordID, Valid: true}, Content: "looks sincere"}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAppeal(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Appeal{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAppealComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/Empty",
+			body: []byte(`{"content":""}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAppeal(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/appeals/%d/comments", guild.DiscordID, appeal.ID)
+			w := serveAuthorized(t, newAppealRouter(store, memStore), moderatorDiscordID, http.MethodPost, url, tc.body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAppealController_DecideAppeal(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	appeal := generateRandomAppeal(generateBanCase(guild.DiscordID, utils.RandomSnowflakeID().String()))
+
+	acceptJSON, err := json.Marshal(forms.DecideAppealJSON{Decision: "accept", Reason: "second chance"})
+	require.NoError(t, err)
+	denyJSON, err := json.Marshal(forms.DecideAppealJSON{Decision: "deny"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/Accept",
+			body: acceptJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventAppealDecided, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "OK/Deny",
+			body: denyJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: denyJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrNoRows)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "Conflict/AlreadyDecided",
+			body: acceptJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errAppealDecided)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/Decision",
+			body: []byte(`{"decision":"maybe"}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/appeals/%d/decision", guild.DiscordID, appeal.ID)
+			w := serveAuthorized(t, newAppealRouter(store, memStore), utils.RandomSnowflakeID().String(), http.MethodPost, url, tc.body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
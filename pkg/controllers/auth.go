@@ -6,11 +6,15 @@ import (
 	"fmt"
 	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares/permissions"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
 	"net/http"
+	"time"
 )
 
 type AuthController struct {
@@ -36,10 +40,15 @@ func NewAuthController(
 
 func (ctrl *AuthController) RefreshToken(c *gin.Context) {
 	refreshPayload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	if refreshPayload.Guild != nil {
+		err := errors.New("guild-scoped token can not be refreshed")
+		c.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
 
 	session, err := ctrl.memStore.GetSession(c, refreshPayload.ID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, redis.Nil) {
 			c.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
@@ -59,35 +68,118 @@ func (ctrl *AuthController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newAccessToken, _, err := ctrl.tokenMaker.CreateToken(refreshPayload.UserDiscordID, ctrl.config.AccessTokenDuration)
+	newSession, newAccessToken, err := createSession(c, ctrl.memStore, ctrl.config, ctrl.tokenMaker, refreshPayload.UserDiscordID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	newRefreshToken, newRefreshPayload, err := ctrl.tokenMaker.CreateToken(refreshPayload.UserDiscordID, ctrl.config.RefreshTokenDuration)
+
+	// clients which keep refresh token themselves send it in Authorization header
+	useCookies := ctrl.config.AuthCookieMode && c.GetHeader(middlewares.AuthorizationHeaderKey) == ""
+	writeSession(c, ctrl.config, newSession, newAccessToken, useCookies)
+}
+
+// createSession issues access and refresh tokens and stores the session of the refresh token
+func createSession(
+	c *gin.Context,
+	memStore memdb.Store,
+	config utils.Config,
+	tokenMaker token.Maker,
+	userDiscordID string,
+) (memdb.Session, string, error) {
+	accessToken, _, err := tokenMaker.CreateToken(userDiscordID, config.AccessTokenDuration)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return memdb.Session{}, "", err
+	}
+	refreshToken, refreshPayload, err := tokenMaker.CreateToken(userDiscordID, config.RefreshTokenDuration)
+	if err != nil {
+		return memdb.Session{}, "", err
 	}
 
-	newSession, err := ctrl.memStore.SetSession(c, memdb.Session{
-		ID:           newRefreshPayload.ID,
-		DiscordID:    newRefreshPayload.UserDiscordID,
-		RefreshToken: newRefreshToken,
+	session, err := memStore.SetSession(c, memdb.Session{
+		ID:           refreshPayload.ID,
+		DiscordID:    refreshPayload.UserDiscordID,
+		RefreshToken: refreshToken,
 		UserAgent:    c.Request.UserAgent(),
 		ClientIp:     c.ClientIP(),
 		IsBlocked:    false,
-	}, ctrl.config.RefreshTokenDuration)
+		CreatedAt:    time.Now(),
+	}, config.RefreshTokenDuration)
+	if err != nil {
+		return memdb.Session{}, "", err
+	}
+	return session, accessToken, nil
+}
+
+// writeSession responds with tokens of the new session. With cookies the refresh token is set
+// as HttpOnly cookie sent to auth routes only, and CSRF token is issued for double-submit check
+func writeSession(c *gin.Context, config utils.Config, session memdb.Session, accessToken string, useCookies bool) {
+	res := gin.H{
+		"session_id":       session.ID,
+		"access_token":     accessToken,
+		"access_duration":  config.AccessTokenDuration.Milliseconds(),
+		"refresh_duration": config.RefreshTokenDuration.Milliseconds(),
+	}
+
+	if !useCookies {
+		res["refresh_token"] = session.RefreshToken
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	csrfToken := utils.RandomString(32)
+	maxAge := int(config.RefreshTokenDuration.Seconds())
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(middlewares.RefreshTokenCookie, session.RefreshToken, maxAge, middlewares.RefreshTokenCookiePath, "", true, true)
+	c.SetCookie(middlewares.CSRFTokenCookie, csrfToken, maxAge, "/", "", true, false)
+
+	res["csrf_token"] = csrfToken
+	c.JSON(http.StatusOK, res)
+}
+
+// CreateGuildToken issues short-lived access token with capabilities of the user in the guild,
+// so permission checks of subsequent requests do not hit the database
+func (ctrl *AuthController) CreateGuildToken(c *gin.Context) {
+	var req forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	if authPayload.Guild != nil {
+		err := errors.New("guild-scoped token can not issue other tokens")
+		c.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	capabilities, err := permissions.GuildCapabilities(c, ctrl.store, authPayload.UserDiscordID, req.DiscordID)
+	if err != nil {
+		if errors.Is(err, permissions.ErrNoGuildRelation) || errors.Is(err, permissions.ErrGuildConfigNotFound) {
+			c.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if len(capabilities) == 0 {
+		c.JSON(http.StatusForbidden, errorResponse(permissions.ErrInsufficientPermission))
+		return
+	}
+
+	guildClaims := token.GuildClaims{
+		DiscordID:    req.DiscordID,
+		Capabilities: capabilities,
+	}
+	accessToken, _, err := ctrl.tokenMaker.CreateGuildToken(authPayload.UserDiscordID, guildClaims, ctrl.config.GuildTokenDuration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"session_id":       newSession.ID,
-		"access_token":     newAccessToken,
-		"access_duration":  ctrl.config.AccessTokenDuration.Milliseconds(),
-		"refresh_token":    newRefreshToken,
-		"refresh_duration": ctrl.config.RefreshTokenDuration.Milliseconds(),
+		"access_token":    accessToken,
+		"access_duration": ctrl.config.GuildTokenDuration.Milliseconds(),
+		"guild":           guildClaims,
 	})
 }
@@ -2,12 +2,16 @@ package controllers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
 	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -171,3 +175,223 @@ func TestAuthController_RefreshToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthController_CreateGuildToken(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	config := utils.Config{
+		GuildTokenDuration: 5 * time.Minute,
+	}
+
+	tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+	guild := generateRandomGuild()
+	userDiscordID := utils.RandomSnowflakeID().String()
+	guildConfigJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	require.NoError(t, err)
+
+	accessToken, _, err := tokenMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+	guildToken, _, err := tokenMaker.CreateGuildToken(userDiscordID, token.GuildClaims{
+		DiscordID:    guild.DiscordID,
+		Capabilities: []string{token.CapabilityGuildConfigRead},
+	}, time.Minute)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		accessToken   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			accessToken: accessToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Eq(db.GetUserGuildRelParams{
+						AccountDiscordID: userDiscordID,
+						GuildDiscordID:   guild.DiscordID,
+					})).
+					Times(1).
+					Return(db.UserGuild{Permissions: 8}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{Json: guildConfigJSON}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					AccessToken string `json:"access_token"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				payload, err := tokenMaker.VerifyToken(res.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, userDiscordID, payload.UserDiscordID)
+				require.True(t, payload.HasCapability(guild.DiscordID, token.CapabilityGuildConfigRead))
+				require.True(t, payload.HasCapability(guild.DiscordID, token.CapabilityGuildConfigEdit))
+				require.WithinDuration(t, time.Now().Add(config.GuildTokenDuration), payload.ExpiredAt, time.Second)
+			},
+		},
+		{
+			name:        "Forbidden/GuildScopedToken",
+			accessToken: guildToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:        "Forbidden/NoGuildRelation",
+			accessToken: accessToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:        "Forbidden/NoCapabilities",
+			accessToken: accessToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				restrictedConfigJSON, err := json.Marshal(objects.GuildConfig{
+					Permissions: objects.GuildConfigPermissions{Edit: 8, Read: 8},
+				})
+				require.NoError(t, err)
+
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{Permissions: 1}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{Json: restrictedConfigJSON}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:        "InternalServerError/DBGetGuildConfig",
+			accessToken: accessToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{Permissions: 8}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			router := gin.New()
+			authMiddleware := middlewares.NewAuthMiddleware(tokenMaker)
+			authController := NewAuthController(store, nil, config, tokenMaker)
+			router.POST("/auth/guild-token/:discord_id", authMiddleware, authController.CreateGuildToken)
+
+			url := fmt.Sprintf("/auth/guild-token/%s", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			authHeader := fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, tc.accessToken)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, authHeader)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAuthController_RefreshTokenCookieMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	config := utils.Config{
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 2 * time.Hour,
+		AuthCookieMode:       true,
+	}
+
+	tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+	refreshToken, refreshPayload, err := tokenMaker.CreateToken("1234", time.Minute)
+	require.NoError(t, err)
+	session := generateRandomSession(refreshPayload)
+	csrfToken := utils.RandomString(32)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	memStore := mockmemdb.NewMockStore(ctrl)
+	memStore.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(session.ID)).
+		Times(1).
+		Return(session, nil)
+	memStore.EXPECT().
+		SetSession(gomock.Any(), gomock.Any(), gomock.Eq(config.RefreshTokenDuration)).
+		Times(1).
+		DoAndReturn(func(_ interface{}, s memdb.Session, _ time.Duration) (memdb.Session, error) {
+			return s, nil
+		})
+
+	router := gin.New()
+	authMiddleware := middlewares.NewAuthMiddleware(tokenMaker)
+	authController := NewAuthController(nil, memStore, config, tokenMaker)
+	router.POST("/api/v1/auth/paseto/refresh", authMiddleware, authController.RefreshToken)
+
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/auth/paseto/refresh", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: middlewares.RefreshTokenCookie, Value: refreshToken})
+	req.AddCookie(&http.Cookie{Name: middlewares.CSRFTokenCookie, Value: csrfToken})
+	req.Header.Set(middlewares.CSRFTokenHeaderKey, csrfToken)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.NotContains(t, res, "refresh_token")
+	require.NotEmpty(t, res["access_token"])
+
+	cookies := make(map[string]*http.Cookie)
+	for _, cookie := range w.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+	require.Contains(t, cookies, middlewares.RefreshTokenCookie)
+	require.True(t, cookies[middlewares.RefreshTokenCookie].HttpOnly)
+	require.True(t, cookies[middlewares.RefreshTokenCookie].Secure)
+	require.Equal(t, http.SameSiteStrictMode, cookies[middlewares.RefreshTokenCookie].SameSite)
+	require.Equal(t, middlewares.RefreshTokenCookiePath, cookies[middlewares.RefreshTokenCookie].Path)
+	require.NotEqual(t, refreshToken, cookies[middlewares.RefreshTokenCookie].Value)
+
+	require.Contains(t, cookies, middlewares.CSRFTokenCookie)
+	require.False(t, cookies[middlewares.CSRFTokenCookie].HttpOnly)
+	require.Equal(t, res["csrf_token"], cookies[middlewares.CSRFTokenCookie].Value)
+}
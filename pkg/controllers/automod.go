@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/automod"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type AutomodController struct {
+	store    db.Store
+	memStore memdb.Store
+
+	mu       sync.Mutex
+	rulesets map[string]compiledRuleset
+}
+
+// compiledRuleset is cached until config of the guild changes
+type compiledRuleset struct {
+	source  []byte
+	ruleset *automod.Ruleset
+}
+
+func NewAutomodController(store db.Store, memStore memdb.Store) *AutomodController {
+	return &AutomodController{
+		store:    store,
+		memStore: memStore,
+		rulesets: make(map[string]compiledRuleset),
+	}
+}
+
+// GetAutomodRuleset returns compiled ruleset for the bot to evaluate messages locally.
+// Version of the ruleset is sent as ETag, so the bot can poll it cheaply.
+func (ctrl *AutomodController) GetAutomodRuleset(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ruleset, err := ctrl.ruleset(c, uri.DiscordID)
+	if err != nil {
+		ctrl.rulesetError(c, err)
+		return
+	}
+
+	etag := `"` + ruleset.Version + `"`
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, ruleset)
+}
+
+// EvaluateAutomod evaluates the message against rules of the guild, rate limits are shared by all replicas
+func (ctrl *AutomodController) EvaluateAutomod(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotEvaluateAutomodJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if form.SentAt.IsZero() {
+		form.SentAt = time.Now()
+	}
+
+	ruleset, err := ctrl.ruleset(c, uri.DiscordID)
+	if err != nil {
+		ctrl.rulesetError(c, err)
+		return
+	}
+
+	violations, err := ruleset.Evaluate(c, redisRateCounter{memStore: ctrl.memStore}, automod.Message{
+		GuildDiscordID:   uri.DiscordID,
+		ChannelDiscordID: form.ChannelDiscordID,
+		AuthorDiscordID:  form.AuthorDiscordID,
+		RoleDiscordIDs:   form.RoleDiscordIDs,
+		Content:          form.Content,
+		SentAt:           form.SentAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version":    ruleset.Version,
+		"violations": violations,
+		"outcome":    automod.Resolve(violations),
+	})
+}
+
+type ResponseAutomodTest struct {
+	Content    string              `json:"content"`
+	Violations []automod.Violation `json:"violations"`
+	Outcome    automod.Outcome     `json:"outcome"`
+}
+
+// TestAutomod shows which rules fire on sample messages, so moderators can try filters before enabling them.
+// Messages are evaluated in order as if sent at once, rate limits are not shared with live traffic.
+func (ctrl *AutomodController) TestAutomod(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	_ = c.ShouldBindUri(&uri)
+	var form forms.TestAutomodJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var ruleset *automod.Ruleset
+	var err error
+	if form.Draft != nil {
+		ruleset, err = automod.Compile(*form.Draft)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	} else {
+		ruleset, err = ctrl.ruleset(c, uri.DiscordID)
+		if err != nil {
+			ctrl.rulesetError(c, err)
+			return
+		}
+	}
+
+	counter := automod.NewMemoryRateCounter()
+	sentAt := time.Now()
+	results := make([]ResponseAutomodTest, 0, len(form.Messages))
+	for _, message := range form.Messages {
+		violations, err := ruleset.Evaluate(c, counter, automod.Message{
+			GuildDiscordID:   uri.DiscordID,
+			ChannelDiscordID: message.ChannelDiscordID,
+			AuthorDiscordID:  message.AuthorDiscordID,
+			RoleDiscordIDs:   message.RoleDiscordIDs,
+			Content:          message.Content,
+			SentAt:           sentAt,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		results = append(results, ResponseAutomodTest{
+			Content:    message.Content,
+			Violations: violations,
+			Outcome:    automod.Resolve(violations),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version": ruleset.Version,
+		"results": results,
+	})
+}
+
+func (ctrl *AutomodController) ruleset(ctx context.Context, guildDiscordID string) (*automod.Ruleset, error) {
+	guildConfig, err := ctrl.store.GetGuildConfig(ctx, guildDiscordID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctrl.mu.Lock()
+	cached, ok := ctrl.rulesets[guildDiscordID]
+	ctrl.mu.Unlock()
+	if ok && bytes.Equal(cached.source, guildConfig.Json) {
+		return cached.ruleset, nil
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return nil, err
+	}
+	ruleset, err := automod.Compile(guildConfigObj.Data.Automod)
+	if err != nil {
+		return nil, err
+	}
+
+	ctrl.mu.Lock()
+	ctrl.rulesets[guildDiscordID] = compiledRuleset{source: guildConfig.Json, ruleset: ruleset}
+	ctrl.mu.Unlock()
+	return ruleset, nil
+}
+
+func (ctrl *AutomodController) rulesetError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// redisRateCounter adapts memory store to the automod rate counter
+type redisRateCounter struct {
+	memStore memdb.Store
+}
+
+func (r redisRateCounter) Hit(ctx context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	hits, err := r.memStore.HitAutomodRate(ctx, key, at, window)
+	return int(hits), err
+}
@@ -0,0 +1,429 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/automod"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func generateAutomodGuildConfig(t *testing.T) db.GuildConfig {
+	guildConfigObj := objects.NewDefaultGuildConfig()
+	guildConfigObj.Data.Automod = objects.AutomodConfig{
+		Enabled: true,
+		Rules: []objects.AutomodRule{
+			{
+				Name:    "slurs",
+				Type:    objects.AutomodRuleWords,
+				Enabled: true,
+				Actions: []string{objects.AutomodActionDelete, objects.AutomodActionWarn},
+				Words:   []string{"badword"},
+			},
+			{
+				Name:              "spam",
+				Type:              objects.AutomodRuleRate,
+				Enabled:           true,
+				Actions:           []string{objects.AutomodActionDelete},
+				RateCount:         5,
+				RateWindowSeconds: 10,
+			},
+		},
+	}
+	guildConfigJSON, err := json.Marshal(guildConfigObj)
+	require.NoError(t, err)
+	return db.GuildConfig{
+		ID:   int64(utils.RandomInt(1, 1000)),
+		Json: guildConfigJSON,
+	}
+}
+
+func TestAutomodController_GetAutomodRuleset(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	guildConfig := generateAutomodGuildConfig(t)
+
+	var guildConfigObj objects.GuildConfig
+	require.NoError(t, json.Unmarshal(guildConfig.Json, &guildConfigObj))
+	ruleset, err := automod.Compile(guildConfigObj.Data.Automod)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		ifNoneMatch   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, fmt.Sprintf(`"%s"`, ruleset.Version), w.Header().Get("ETag"))
+
+				var res automod.Ruleset
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, ruleset.Version, res.Version)
+				require.Len(t, res.Rules, 2)
+			},
+		},
+		{
+			name:        "NotModified",
+			ifNoneMatch: fmt.Sprintf(`"%s"`, ruleset.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetGuildConfig",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			automodController := NewAutomodController(store, memStore)
+			router := gin.New()
+			router.GET("/api/v1/bot/guilds/:discord_id/automod", automodController.GetAutomodRuleset)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/automod", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAutomodController_EvaluateAutomod(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	guildConfig := generateAutomodGuildConfig(t)
+
+	formJSON, err := json.Marshal(forms.BotEvaluateAutomodJSON{
+		ChannelDiscordID: "1",
+		AuthorDiscordID:  "2",
+		Content:          "this is a BadWord!",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+				memStore.EXPECT().
+					HitAutomodRate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(6), nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Violations []automod.Violation `json:"violations"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res.Violations, 2)
+				require.Equal(t, "slurs", res.Violations[0].Rule)
+				require.Equal(t, "badword", res.Violations[0].Match)
+				require.Equal(t, "spam", res.Violations[1].Rule)
+			},
+		},
+		{
+			name: "BadRequest/JSON",
+			body: []byte(`{"content": "hi"}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/MemDBHitAutomodRate",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(guildConfig, nil)
+				memStore.EXPECT().
+					HitAutomodRate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			automodController := NewAutomodController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/automod/evaluate", automodController.EvaluateAutomod)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/automod/evaluate", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestAutomodController_TestAutomod(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	guildConfig := generateAutomodGuildConfig(t)
+
+	messages := []forms.TestAutomodMessageJSON{
+		{Content: "hello there"},
+		{Content: "badword and BADWORD"},
+	}
+	storedJSON, err := json.Marshal(forms.TestAutomodJSON{Messages: messages})
+	require.NoError(t, err)
+	draftJSON, err := json.Marshal(forms.TestAutomodJSON{
+		Messages: messages,
+		Draft: &objects.AutomodConfig{
+			Enabled: true,
+			Rules: []objects.AutomodRule{{
+				Name:                "greetings",
+				Type:                objects.AutomodRuleRegex,
+				Enabled:             true,
+				Actions:             []string{objects.AutomodActionDelete, objects.AutomodActionMute},
+				MuteDurationSeconds: 60,
+				Patterns:            []string{`(?i)hel+o`},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	expensiveDraftJSON, err := json.Marshal(forms.TestAutomodJSON{
+		Messages: messages,
+		Draft: &objects.AutomodConfig{
+			Enabled: true,
+			Rules: []objects.AutomodRule{{
+				Name:     "backtracking",
+				Type:     objects.AutomodRuleRegex,
+				Enabled:  true,
+				Actions:  []string{objects.AutomodActionDelete},
+				Patterns: []string{`(a+)+$`},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	type response struct {
+		Results []ResponseAutomodTest `json:"results"`
+	}
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/StoredConfig",
+			body: storedJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res.Results, 2)
+				require.Empty(t, res.Results[0].Violations)
+				require.Len(t, res.Results[1].Violations, 1)
+				require.Equal(t, []automod.Span{{Start: 0, End: 7}, {Start: 12, End: 19}}, res.Results[1].Violations[0].Spans)
+				require.Equal(t, automod.Outcome{Delete: true, Punishment: objects.AutomodActionWarn}, res.Results[1].Outcome)
+			},
+		},
+		{
+			name: "OK/Draft",
+			body: draftJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res.Results, 2)
+				require.Equal(t, "hello", res.Results[0].Violations[0].Match)
+				require.Equal(t, automod.Outcome{
+					Delete:              true,
+					Punishment:          objects.AutomodActionMute,
+					MuteDurationSeconds: 60,
+				}, res.Results[0].Outcome)
+				require.Empty(t, res.Results[1].Violations)
+			},
+		},
+		{
+			name: "BadRequest/NoMessages",
+			body: []byte(`{"messages": []}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/ExpensivePattern",
+			body: expensiveDraftJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+				require.Contains(t, w.Body.String(), automod.ErrNestedQuantifier.Error())
+			},
+		},
+		{
+			name: "InternalServerError/DBGetGuildConfig",
+			body: storedJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			automodController := NewAutomodController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/guilds/:discord_id/automod/test", automodController.TestAutomod)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/automod/test", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
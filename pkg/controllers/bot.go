@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/apikey"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+type BotController struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewBotController(store db.Store, memStore memdb.Store) *BotController {
+	return &BotController{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+// JoinGuild records the bot joining the guild, the bot also calls it on reconnect to sync guild name, icon and owner
+func (ctrl *BotController) JoinGuild(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotJoinGuildJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	defaultGuildConfigJSON, _ := json.Marshal(objects.NewDefaultGuildConfig())
+
+	var guild db.Guild
+	configCreated := false
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		guild, err = q.SetGuildBotJoined(c, db.SetGuildBotJoinedParams{
+			DiscordID:      uri.DiscordID,
+			Name:           form.Name,
+			Icon:           form.Icon,
+			OwnerDiscordID: form.OwnerDiscordID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.TryCreateGuildConfig(c, db.TryCreateGuildConfigParams{
+			DiscordID: uri.DiscordID,
+			Json:      defaultGuildConfigJSON,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		configCreated = err == nil
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctrl.publishBotStatus(c, guild)
+	ctrl.publishSyncResult(c, guild, configCreated)
+	c.JSON(http.StatusOK, guild)
+}
+
+func (ctrl *BotController) LeaveGuild(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	guild, err := ctrl.store.SetGuildBotLeft(c, uri.DiscordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctrl.publishBotStatus(c, guild)
+	c.JSON(http.StatusOK, guild)
+}
+
+// TransferGuildOwner records ownership transfer reported by the bot. Relation of the previous owner
+// is dropped, since it had owner permissions, and is restored with actual ones on next login.
+func (ctrl *BotController) TransferGuildOwner(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotTransferGuildOwnerJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var guild db.UpdateGuildOwnerRow
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		guild, err = q.UpdateGuildOwner(c, db.UpdateGuildOwnerParams{
+			DiscordID:      uri.DiscordID,
+			OwnerDiscordID: form.OwnerDiscordID,
+		})
+		if err != nil {
+			return err
+		}
+		if guild.PreviousOwnerDiscordID == guild.OwnerDiscordID {
+			return nil
+		}
+
+		if guild.PreviousOwnerDiscordID != "" {
+			err = q.DeleteUserGuildRel(c, db.DeleteUserGuildRelParams{
+				AccountDiscordID: guild.PreviousOwnerDiscordID,
+				GuildDiscordID:   uri.DiscordID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		data, _ := json.Marshal(gin.H{
+			"previous_owner_discord_id": guild.PreviousOwnerDiscordID,
+			"owner_discord_id":          guild.OwnerDiscordID,
+		})
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			GuildDiscordID: uri.DiscordID,
+			Action:         db.AuditActionGuildOwnerTransfer,
+			Data:           data,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if guild.PreviousOwnerDiscordID != guild.OwnerDiscordID {
+		data, _ := json.Marshal(gin.H{"owner_discord_id": guild.OwnerDiscordID})
+		err = ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+			Type:           memdb.GuildEventOwnerChanged,
+			GuildDiscordID: guild.DiscordID,
+			Data:           data,
+			CreatedAt:      time.Now(),
+		})
+		if err != nil {
+			logrus.Warnf("Failed to publish owner change: %v", err.Error())
+		}
+	}
+
+	c.JSON(http.StatusOK, guild)
+}
+
+func (ctrl *BotController) publishBotStatus(c *gin.Context, guild db.Guild) {
+	data, _ := json.Marshal(gin.H{
+		"bot_present":   guild.BotPresent,
+		"bot_joined_at": nullTimeToPtr(guild.BotJoinedAt),
+	})
+	err := ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           memdb.GuildEventBotStatus,
+		GuildDiscordID: guild.DiscordID,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish bot status: %v", err.Error())
+	}
+}
+
+// publishSyncResult shows guild data synced by the bot, configCreated is true when the guild got the default config
+func (ctrl *BotController) publishSyncResult(c *gin.Context, guild db.Guild, configCreated bool) {
+	data, _ := json.Marshal(gin.H{
+		"name":             guild.Name,
+		"icon":             guild.Icon,
+		"owner_discord_id": guild.OwnerDiscordID,
+		"config_created":   configCreated,
+	})
+	err := ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           memdb.GuildEventSyncResult,
+		GuildDiscordID: guild.DiscordID,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish sync result: %v", err.Error())
+	}
+}
+
+// GetCommands streams commands for the bot using SSE. Commands are published to every connected bot and
+// filtered by guilds of its API key, a command published while no bot is connected is not delivered and its
+// sender has to retry. Commands requiring acknowledgement are delivered again until they are acknowledged.
+func (ctrl *BotController) GetCommands(c *gin.Context) {
+	key := c.MustGet(middlewares.APIKeyPayloadKey).(db.ApiKey)
+
+	commands, closeCommands, err := ctrl.memStore.SubscribeBotCommands(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer closeCommands()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case command, ok := <-commands:
+			if !ok {
+				return
+			}
+			if !apikey.HasGuild(key.GuildDiscordIDs, command.GuildDiscordID) {
+				continue
+			}
+			c.SSEvent(command.Type, command)
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"time": time.Now()})
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// AckCommand confirms that the bot performed the command, so it is not delivered again
+func (ctrl *BotController) AckCommand(c *gin.Context) {
+	var uri forms.BotAckCommandURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	key := c.MustGet(middlewares.APIKeyPayloadKey).(db.ApiKey)
+
+	action, err := ctrl.store.GetScheduledAction(c, uri.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// commands of other guilds are reported as missing, so keys cannot probe them
+	if !apikey.HasGuild(key.GuildDiscordIDs, action.GuildDiscordID) {
+		c.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	_, err = ctrl.store.AckScheduledAction(c, action.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := errors.New("command is not awaiting acknowledgement")
+			c.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,589 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBotController_JoinGuild(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	guild.BotPresent = true
+	guild.BotJoinedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	form := forms.BotJoinGuildJSON{
+		Name:           guild.Name,
+		Icon:           guild.Icon,
+		OwnerDiscordID: guild.OwnerDiscordID,
+	}
+	formJSON, err := json.Marshal(form)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventBotStatus, event.Type)
+						return nil
+					})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventSyncResult, event.Type)
+						var data map[string]interface{}
+						require.NoError(t, json.Unmarshal(event.Data, &data))
+						require.Contains(t, data, "config_created")
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "OK/MemDBPublishError",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/JSON",
+			body: []byte(`{"name":""}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			botController := NewBotController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/join", botController.JoinGuild)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/join", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestBotController_LeaveGuild(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					SetGuildBotLeft(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guild, nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					SetGuildBotLeft(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.Guild{}, sql.ErrNoRows)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBSetGuildBotLeft",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					SetGuildBotLeft(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.Guild{}, sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			botController := NewBotController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/leave", botController.LeaveGuild)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/leave", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestBotController_TransferGuildOwner(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+
+	formJSON, err := json.Marshal(forms.BotTransferGuildOwnerJSON{
+		OwnerDiscordID: generateRandomUser().DiscordID,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/JSON",
+			body: []byte(`{}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			botController := NewBotController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/owner", botController.TransferGuildOwner)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/owner", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestBotController_GetCommands(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	otherGuild := generateRandomGuild()
+	command := memdb.BotCommand{
+		ID:             "1",
+		Type:           memdb.BotCommandUnban,
+		GuildDiscordID: guild.DiscordID,
+		Data:           json.RawMessage(`{"case_id":1}`),
+		RequiresAck:    true,
+		CreatedAt:      time.Now(),
+	}
+	otherCommand := memdb.BotCommand{
+		ID:             "2",
+		Type:           memdb.BotCommandUnmute,
+		GuildDiscordID: otherGuild.DiscordID,
+		Data:           json.RawMessage(`{"case_id":2}`),
+		CreatedAt:      time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		key           db.ApiKey
+		buildStubs    func(memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			key:  db.ApiKey{},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				commands := make(chan memdb.BotCommand, 2)
+				commands <- command
+				commands <- otherCommand
+				close(commands)
+				memStore.EXPECT().
+					SubscribeBotCommands(gomock.Any()).
+					Times(1).
+					Return((<-chan memdb.BotCommand)(commands), func() error { return nil }, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s", memdb.BotCommandUnban))
+				require.Contains(t, w.Body.String(), guild.DiscordID)
+				require.Contains(t, w.Body.String(), otherGuild.DiscordID)
+			},
+		},
+		{
+			name: "OK/SkipsOtherGuilds",
+			key:  db.ApiKey{GuildDiscordIDs: []string{guild.DiscordID}},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				commands := make(chan memdb.BotCommand, 2)
+				commands <- otherCommand
+				commands <- command
+				close(commands)
+				memStore.EXPECT().
+					SubscribeBotCommands(gomock.Any()).
+					Times(1).
+					Return((<-chan memdb.BotCommand)(commands), func() error { return nil }, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), guild.DiscordID)
+				require.NotContains(t, w.Body.String(), otherGuild.DiscordID)
+				require.NotContains(t, w.Body.String(), fmt.Sprintf("event:%s", memdb.BotCommandUnmute))
+			},
+		},
+		{
+			name: "InternalServerError/MemDBSubscribeBotCommands",
+			key:  db.ApiKey{},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					SubscribeBotCommands(gomock.Any()).
+					Times(1).
+					Return(nil, nil, redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(memStore)
+
+			botController := NewBotController(store, memStore)
+			router := gin.New()
+			setKey := func(c *gin.Context) {
+				c.Set(middlewares.APIKeyPayloadKey, tc.key)
+			}
+			router.GET("/api/v1/bot/commands", setKey, botController.GetCommands)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/bot/commands", nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestBotController_AckCommand(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	action := db.ScheduledAction{
+		ID:              1,
+		GuildDiscordID:  guild.DiscordID,
+		CaseID:          1,
+		Action:          db.ScheduledActionUnban,
+		TargetDiscordID: utils.RandomSnowflakeID().String(),
+		RunAt:           time.Now(),
+		Status:          db.ScheduledActionStatusDispatched,
+		DispatchedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		commandID     string
+		key           db.ApiKey
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "NoContent",
+			commandID: "1",
+			key:       db.ApiKey{GuildDiscordIDs: []string{guild.DiscordID}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledAction(gomock.Any(), gomock.Eq(action.ID)).
+					Times(1).
+					Return(action, nil)
+				store.EXPECT().
+					AckScheduledAction(gomock.Any(), gomock.Eq(action.ID)).
+					Times(1).
+					Return(action, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, w.Code)
+			},
+		},
+		{
+			name:      "BadRequest/ID",
+			commandID: "abc",
+			key:       db.ApiKey{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledAction(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			commandID: "1",
+			key:       db.ApiKey{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledAction{}, sql.ErrNoRows)
+				store.EXPECT().
+					AckScheduledAction(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:      "NotFound/OtherGuild",
+			commandID: "1",
+			key:       db.ApiKey{GuildDiscordIDs: []string{utils.RandomSnowflakeID().String()}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(action, nil)
+				store.EXPECT().
+					AckScheduledAction(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:      "Conflict/NotDispatched",
+			commandID: "1",
+			key:       db.ApiKey{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(action, nil)
+				store.EXPECT().
+					AckScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledAction{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name:      "InternalServerError/DBAckScheduledAction",
+			commandID: "1",
+			key:       db.ApiKey{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(action, nil)
+				store.EXPECT().
+					AckScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledAction{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			botController := NewBotController(store, memStore)
+			router := gin.New()
+			setKey := func(c *gin.Context) {
+				c.Set(middlewares.APIKeyPayloadKey, tc.key)
+			}
+			router.POST("/api/v1/bot/commands/:id/ack", setKey, botController.AckCommand)
+
+			url := fmt.Sprintf("/api/v1/bot/commands/%s/ack", tc.commandID)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+const defaultCasesLimit = 25
+
+var (
+	errCaseNotExpirable  = errors.New("only mute and ban cases can expire")
+	errCaseAlreadyLifted = errors.New("punishment of the case has already been lifted")
+	errCaseNoExpiry      = errors.New("case has no pending expiry")
+	errCaseSuperseded    = errors.New("case is superseded by a newer case of the target")
+)
+
+type CaseController struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewCaseController(store db.Store, memStore memdb.Store) *CaseController {
+	return &CaseController{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+type ResponseCase struct {
+	CaseNumber         int64      `json:"case_number"`
+	GuildDiscordID     string     `json:"guild_discord_id"`
+	Action             string     `json:"action"`
+	TargetDiscordID    string     `json:"target_discord_id"`
+	ModeratorDiscordID string     `json:"moderator_discord_id"`
+	Reason             string     `json:"reason"`
+	DurationSeconds    *int64     `json:"duration_seconds"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+func newResponseCase(moderationCase db.ModerationCase) ResponseCase {
+	var duration *int64
+	if moderationCase.DurationSeconds.Valid {
+		duration = &moderationCase.DurationSeconds.Int64
+	}
+	return ResponseCase{
+		CaseNumber:         moderationCase.CaseNumber,
+		GuildDiscordID:     moderationCase.GuildDiscordID,
+		Action:             moderationCase.Action,
+		TargetDiscordID:    moderationCase.TargetDiscordID,
+		ModeratorDiscordID: moderationCase.ModeratorDiscordID,
+		Reason:             moderationCase.Reason,
+		DurationSeconds:    duration,
+		ExpiresAt:          nullTimeToPtr(moderationCase.ExpiresAt),
+		CreatedAt:          moderationCase.CreatedAt,
+	}
+}
+
+// CreateCase records moderation action taken by the bot, the guild assigns the next case number
+func (ctrl *CaseController) CreateCase(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotCreateCaseJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var duration sql.NullInt64
+	var expiresAt sql.NullTime
+	switch {
+	case form.DurationSeconds > 0 && form.Action != db.CaseActionMute && form.Action != db.CaseActionBan:
+		err := errors.New("only mute and ban cases can have duration")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	case form.DurationSeconds == 0 && form.Action == db.CaseActionMute:
+		err := errors.New("mute case requires duration")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	case form.DurationSeconds > 0:
+		duration = sql.NullInt64{Int64: form.DurationSeconds, Valid: true}
+		expiresAt = sql.NullTime{Time: time.Now().Add(time.Duration(form.DurationSeconds) * time.Second), Valid: true}
+	}
+
+	var moderationCase db.ModerationCase
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		moderationCase, err = q.CreateModerationCase(c, db.CreateModerationCaseParams{
+			GuildDiscordID:     uri.DiscordID,
+			Action:             form.Action,
+			TargetDiscordID:    form.TargetDiscordID,
+			ModeratorDiscordID: form.ModeratorDiscordID,
+			Reason:             form.Reason,
+			DurationSeconds:    duration,
+			ExpiresAt:          expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		liftingAction, ok := db.LiftingAction(form.Action)
+		if !ok {
+			return nil
+		}
+		// the new case supersedes pending lifting of the previous punishment
+		_, err = q.CancelTargetScheduledActions(c, db.CancelTargetScheduledActionsParams{
+			GuildDiscordID:  uri.DiscordID,
+			TargetDiscordID: form.TargetDiscordID,
+			Action:          liftingAction,
+		})
+		if err != nil || !expiresAt.Valid {
+			return err
+		}
+
+		_, err = q.ScheduleCaseAction(c, db.ScheduleCaseActionParams{
+			GuildDiscordID:  uri.DiscordID,
+			CaseID:          moderationCase.ID,
+			Action:          liftingAction,
+			TargetDiscordID: form.TargetDiscordID,
+			RunAt:           expiresAt.Time,
+		})
+		return err
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			err := errors.New("guild not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := newResponseCase(moderationCase)
+	ctrl.publishCaseEvent(c, memdb.GuildEventCaseCreated, res)
+	c.JSON(http.StatusCreated, res)
+}
+
+func (ctrl *CaseController) GetCase(c *gin.Context) {
+	var uri forms.GetCaseURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	moderationCase, err := ctrl.store.GetModerationCase(c, db.GetModerationCaseParams{
+		GuildDiscordID: uri.DiscordID,
+		CaseNumber:     uri.CaseNumber,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newResponseCase(moderationCase))
+}
+
+// GetCases lists cases from the newest, next page starts before the case number returned in next_before
+func (ctrl *CaseController) GetCases(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	_ = c.ShouldBindUri(&uri)
+	var query forms.GetCasesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultCasesLimit
+	}
+
+	moderationCases, err := ctrl.store.GetModerationCases(c, db.GetModerationCasesParams{
+		GuildDiscordID:     uri.DiscordID,
+		Action:             query.Action,
+		TargetDiscordID:    query.TargetDiscordID,
+		ModeratorDiscordID: query.ModeratorDiscordID,
+		BeforeCaseNumber:   query.Before,
+		MaxResults:         query.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	cases := make([]ResponseCase, 0, len(moderationCases))
+	for _, moderationCase := range moderationCases {
+		cases = append(cases, newResponseCase(moderationCase))
+	}
+
+	var nextBefore *int64
+	if len(cases) == int(query.Limit) {
+		nextBefore = &cases[len(cases)-1].CaseNumber
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cases":       cases,
+		"next_before": nextBefore,
+	})
+}
+
+// RescheduleCaseExpiry moves lifting of the case punishment, permanent punishments become temporary
+func (ctrl *CaseController) RescheduleCaseExpiry(c *gin.Context) {
+	var uri forms.GetCaseURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.RescheduleCaseExpiryJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !form.ExpiresAt.After(time.Now()) {
+		err := errors.New("expires_at must be in the future")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	moderationCase, err := ctrl.store.GetModerationCase(c, db.GetModerationCaseParams{
+		GuildDiscordID: uri.DiscordID,
+		CaseNumber:     uri.CaseNumber,
+	})
+	if err != nil {
+		ctrl.caseExpiryError(c, err)
+		return
+	}
+	liftingAction, ok := db.LiftingAction(moderationCase.Action)
+	if !ok || moderationCase.Action == db.CaseActionUnban {
+		ctrl.caseExpiryError(c, errCaseNotExpirable)
+		return
+	}
+
+	// a newer case cancelled lifting of this one on purpose, reviving it would lift the newer punishment
+	latestCase, err := ctrl.store.GetTargetLatestCase(c, db.GetTargetLatestCaseParams{
+		GuildDiscordID:  uri.DiscordID,
+		TargetDiscordID: moderationCase.TargetDiscordID,
+		Actions:         db.LiftedActions(liftingAction),
+	})
+	if err != nil {
+		ctrl.caseExpiryError(c, err)
+		return
+	}
+	if latestCase.ID != moderationCase.ID {
+		ctrl.caseExpiryError(c, errCaseSuperseded)
+		return
+	}
+
+	err = ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		_, err = q.ScheduleCaseAction(c, db.ScheduleCaseActionParams{
+			GuildDiscordID:  uri.DiscordID,
+			CaseID:          moderationCase.ID,
+			Action:          liftingAction,
+			TargetDiscordID: moderationCase.TargetDiscordID,
+			RunAt:           form.ExpiresAt,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return errCaseAlreadyLifted
+		}
+		if err != nil {
+			return err
+		}
+
+		moderationCase, err = q.UpdateModerationCaseExpiry(c, db.UpdateModerationCaseExpiryParams{
+			ID:              moderationCase.ID,
+			DurationSeconds: sql.NullInt64{Int64: int64(form.ExpiresAt.Sub(moderationCase.CreatedAt).Seconds()), Valid: true},
+			ExpiresAt:       sql.NullTime{Time: form.ExpiresAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return ctrl.auditCase(c, q, db.AuditActionCaseReschedule, moderationCase)
+	})
+	if err != nil {
+		ctrl.caseExpiryError(c, err)
+		return
+	}
+
+	res := newResponseCase(moderationCase)
+	ctrl.publishCaseEvent(c, memdb.GuildEventCaseUpdated, res)
+	c.JSON(http.StatusOK, res)
+}
+
+// CancelCaseExpiry keeps the case punishment in place, it may be scheduled again later
+func (ctrl *CaseController) CancelCaseExpiry(c *gin.Context) {
+	var uri forms.GetCaseURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var moderationCase db.ModerationCase
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		moderationCase, err = q.GetModerationCase(c, db.GetModerationCaseParams{
+			GuildDiscordID: uri.DiscordID,
+			CaseNumber:     uri.CaseNumber,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CancelCaseScheduledAction(c, moderationCase.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errCaseNoExpiry
+		}
+		if err != nil {
+			return err
+		}
+
+		moderationCase, err = q.UpdateModerationCaseExpiry(c, db.UpdateModerationCaseExpiryParams{
+			ID: moderationCase.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return ctrl.auditCase(c, q, db.AuditActionCaseCancelExpiry, moderationCase)
+	})
+	if err != nil {
+		ctrl.caseExpiryError(c, err)
+		return
+	}
+
+	res := newResponseCase(moderationCase)
+	ctrl.publishCaseEvent(c, memdb.GuildEventCaseUpdated, res)
+	c.JSON(http.StatusOK, res)
+}
+
+func (ctrl *CaseController) caseExpiryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, errCaseNoExpiry):
+		c.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, errCaseNotExpirable):
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+	case errors.Is(err, errCaseAlreadyLifted), errors.Is(err, errCaseSuperseded):
+		c.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+func (ctrl *CaseController) auditCase(c *gin.Context, q *db.Queries, action string, moderationCase db.ModerationCase) error {
+	var actorDiscordID sql.NullString
+	if payload, ok := c.Get(middlewares.AuthorizationPayloadKey); ok {
+		actorDiscordID = sql.NullString{String: payload.(*token.Payload).UserDiscordID, Valid: true}
+	}
+
+	data, err := json.Marshal(newResponseCase(moderationCase))
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+		ActorDiscordID: actorDiscordID,
+		GuildDiscordID: moderationCase.GuildDiscordID,
+		Action:         action,
+		Data:           data,
+	})
+	return err
+}
+
+func (ctrl *CaseController) publishCaseEvent(c *gin.Context, eventType string, res ResponseCase) {
+	data, _ := json.Marshal(res)
+	err := ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           eventType,
+		GuildDiscordID: res.GuildDiscordID,
+		Capability:     token.CapabilityCasesRead,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish case event: %v", err.Error())
+	}
+}
@@ -0,0 +1,709 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func generateRandomCase(guildDiscordID string, caseNumber int64) db.ModerationCase {
+	return db.ModerationCase{
+		ID:                 int64(utils.RandomInt(1, 1000)),
+		GuildDiscordID:     guildDiscordID,
+		CaseNumber:         caseNumber,
+		Action:             db.CaseActionWarn,
+		TargetDiscordID:    utils.RandomSnowflakeID().String(),
+		ModeratorDiscordID: utils.RandomSnowflakeID().String(),
+		Reason:             utils.RandomString(20),
+		CreatedAt:          time.Now(),
+	}
+}
+
+func TestCaseController_CreateCase(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	moderationCase := generateRandomCase(guild.DiscordID, 1)
+
+	formJSON, err := json.Marshal(forms.BotCreateCaseJSON{
+		Action:             moderationCase.Action,
+		TargetDiscordID:    moderationCase.TargetDiscordID,
+		ModeratorDiscordID: moderationCase.ModeratorDiscordID,
+		Reason:             moderationCase.Reason,
+	})
+	require.NoError(t, err)
+	warnWithDurationJSON, err := json.Marshal(forms.BotCreateCaseJSON{
+		Action:             db.CaseActionWarn,
+		TargetDiscordID:    moderationCase.TargetDiscordID,
+		ModeratorDiscordID: moderationCase.ModeratorDiscordID,
+		DurationSeconds:    60,
+	})
+	require.NoError(t, err)
+	muteWithoutDurationJSON, err := json.Marshal(forms.BotCreateCaseJSON{
+		Action:             db.CaseActionMute,
+		TargetDiscordID:    moderationCase.TargetDiscordID,
+		ModeratorDiscordID: moderationCase.ModeratorDiscordID,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventCaseCreated, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/JSON",
+			body: []byte(`{"action": "timeout"}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/DurationNotAllowed",
+			body: warnWithDurationJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/DurationRequired",
+			body: muteWithoutDurationJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&pq.Error{Code: "23503"})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			caseController := NewCaseController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/cases", caseController.CreateCase)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/cases", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestCaseController_GetCase(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	moderationCase := generateRandomCase(guild.DiscordID, 3)
+
+	testCases := []struct {
+		name          string
+		caseNumber    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			caseNumber: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetModerationCaseParams{
+					GuildDiscordID: guild.DiscordID,
+					CaseNumber:     moderationCase.CaseNumber,
+				}
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(moderationCase, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:       "BadRequest/URI",
+			caseNumber: "0",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			caseNumber: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ModerationCase{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:       "InternalServerError/DBGetModerationCase",
+			caseNumber: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ModerationCase{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			caseController := NewCaseController(store, memStore)
+			router := gin.New()
+			router.GET("/api/v1/guilds/:discord_id/cases/:case_number", caseController.GetCase)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/cases/%s", guild.DiscordID, tc.caseNumber)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestCaseController_GetCases(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	moderationCases := []db.ModerationCase{
+		generateRandomCase(guild.DiscordID, 5),
+		generateRandomCase(guild.DiscordID, 4),
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?action=warn",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetModerationCasesParams{
+					GuildDiscordID: guild.DiscordID,
+					Action:         db.CaseActionWarn,
+					MaxResults:     defaultCasesLimit,
+				}
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(moderationCases, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Cases      []ResponseCase `json:"cases"`
+					NextBefore *int64         `json:"next_before"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res.Cases, len(moderationCases))
+				require.Nil(t, res.NextBefore)
+			},
+		},
+		{
+			name:  "OK/NextPage",
+			query: "?limit=2&before=6",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetModerationCasesParams{
+					GuildDiscordID:   guild.DiscordID,
+					BeforeCaseNumber: 6,
+					MaxResults:       2,
+				}
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(moderationCases, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					NextBefore *int64 `json:"next_before"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.NotNil(t, res.NextBefore)
+				require.Equal(t, int64(4), *res.NextBefore)
+			},
+		},
+		{
+			name:  "BadRequest/Query",
+			query: "?action=timeout",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:  "InternalServerError/DBGetModerationCases",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			caseController := NewCaseController(store, memStore)
+			router := gin.New()
+			router.GET("/api/v1/guilds/:discord_id/cases", caseController.GetCases)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/cases%s", guild.DiscordID, tc.query)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestCaseController_RescheduleCaseExpiry(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	muteCase := generateRandomCase(guild.DiscordID, 1)
+	muteCase.Action = db.CaseActionMute
+	warnCase := generateRandomCase(guild.DiscordID, 1)
+	newerMuteCase := generateRandomCase(guild.DiscordID, 2)
+	newerMuteCase.ID = muteCase.ID + 1
+	newerMuteCase.Action = db.CaseActionMute
+	newerMuteCase.TargetDiscordID = muteCase.TargetDiscordID
+
+	formJSON, err := json.Marshal(forms.RescheduleCaseExpiryJSON{
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	pastJSON, err := json.Marshal(forms.RescheduleCaseExpiryJSON{
+		ExpiresAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Eq(db.GetModerationCaseParams{
+						GuildDiscordID: guild.DiscordID,
+						CaseNumber:     1,
+					})).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					GetTargetLatestCase(gomock.Any(), gomock.Eq(db.GetTargetLatestCaseParams{
+						GuildDiscordID:  guild.DiscordID,
+						TargetDiscordID: muteCase.TargetDiscordID,
+						Actions:         []string{db.CaseActionMute},
+					})).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/JSON",
+			body: []byte(`{}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/ExpiresInPast",
+			body: pastJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/NotExpirable",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(warnCase, nil)
+				store.EXPECT().
+					GetTargetLatestCase(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ModerationCase{}, sql.ErrNoRows)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "Conflict/AlreadyLifted",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					GetTargetLatestCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errCaseAlreadyLifted)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name: "Conflict/Superseded",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					GetTargetLatestCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(newerMuteCase, nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetModerationCase",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ModerationCase{}, sql.ErrConnDone)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetModerationCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					GetTargetLatestCase(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(muteCase, nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			caseController := NewCaseController(store, memStore)
+			router := gin.New()
+			router.PUT("/api/v1/guilds/:discord_id/cases/:case_number/expiry", caseController.RescheduleCaseExpiry)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/cases/1/expiry", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestCaseController_CancelCaseExpiry(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "NotFound/NoExpiry",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errCaseNoExpiry)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			caseController := NewCaseController(store, memStore)
+			router := gin.New()
+			router.DELETE("/api/v1/guilds/:discord_id/cases/:case_number/expiry", caseController.CancelCaseExpiry)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/cases/1/expiry", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type EscalationController struct {
+	escalationService *services.EscalationService
+}
+
+func NewEscalationController(escalationService *services.EscalationService) *EscalationController {
+	return &EscalationController{escalationService: escalationService}
+}
+
+// EscalateInfraction is called by the bot before issuing an infraction and returns the action to take under
+// escalation policy of the guild. Nothing is recorded, the bot creates a case with the returned action.
+func (ctrl *EscalationController) EscalateInfraction(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotEscalateInfractionJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	standing, err := ctrl.escalationService.Escalate(c, uri.DiscordID, form.TargetDiscordID, form.Action, form.DurationSeconds, time.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, standing)
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEscalationController_EscalateInfraction(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	guildConfig := generateGuildConfig(t, func(config *objects.GuildConfig) {
+		config.Data.Escalation.Enabled = true
+	})
+	disabledGuildConfig := generateGuildConfig(t, func(config *objects.GuildConfig) {
+		config.Data.Escalation.Enabled = false
+	})
+	targetDiscordID := utils.RandomSnowflakeID().String()
+	warns := []db.ModerationCase{
+		generateRandomCase(guild.DiscordID, 1),
+		generateRandomCase(guild.DiscordID, 2),
+	}
+
+	formJSON, err := json.Marshal(forms.BotEscalateInfractionJSON{
+		Action:          db.CaseActionWarn,
+		TargetDiscordID: targetDiscordID,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/Escalated",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetTargetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.GetTargetModerationCasesParams) ([]db.ModerationCase, error) {
+						require.Equal(t, guild.DiscordID, arg.GuildDiscordID)
+						require.Equal(t, targetDiscordID, arg.TargetDiscordID)
+						require.False(t, arg.CreatedAt.IsZero())
+						return warns, nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res services.MemberStanding
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, 2, res.Standing)
+				require.Equal(t, 3, res.ProjectedStanding)
+				require.Equal(t, 1, res.Points)
+				require.True(t, res.Decision.Escalated)
+				require.Equal(t, db.CaseActionMute, res.Decision.Action)
+				require.Equal(t, int64(3600), res.Decision.DurationSeconds)
+			},
+		},
+		{
+			name: "OK/NotEscalated",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetTargetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(warns[:1], nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res services.MemberStanding
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.False(t, res.Decision.Escalated)
+				require.Equal(t, db.CaseActionWarn, res.Decision.Action)
+			},
+		},
+		{
+			name: "OK/Disabled",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(disabledGuildConfig, nil)
+				store.EXPECT().
+					GetTargetModerationCases(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res services.MemberStanding
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.False(t, res.Decision.Escalated)
+				require.Equal(t, db.CaseActionWarn, res.Decision.Action)
+			},
+		},
+		{
+			name: "BadRequest/Action",
+			body: []byte(fmt.Sprintf(`{"action":"unban","target_discord_id":"%s"}`, targetDiscordID)),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetTargetModerationCases",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetTargetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			escalationController := NewEscalationController(services.NewEscalationService(store))
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/infractions/escalate", escalationController.EscalateInfraction)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/infractions/escalate", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
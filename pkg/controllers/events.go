@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"errors"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares/permissions"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"time"
+)
+
+const eventsHeartbeatInterval = 30 * time.Second
+
+type EventsController struct {
+	store    db.Store
+	memStore memdb.Store
+	upgrader websocket.Upgrader
+}
+
+func NewEventsController(store db.Store, memStore memdb.Store, config utils.Config) *EventsController {
+	allowedOrigins := config.AllowedOrigins()
+	return &EventsController{
+		store:    store,
+		memStore: memStore,
+		upgrader: websocket.Upgrader{
+			// browsers connect with stream tickets, which are not bound to the origin,
+			// so cross-site pages must not be able to open the connection
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true // not a browser
+				}
+				for _, allowed := range allowedOrigins {
+					if origin == allowed {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+}
+
+// CreateEventsTicket issues single-use ticket which authenticates the events stream with ?ticket= query
+func (ctrl *EventsController) CreateEventsTicket(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	capabilities, err := ctrl.subscriberCapabilities(c, payload, uri.DiscordID)
+	if err != nil {
+		if errors.Is(err, permissions.ErrNoGuildRelation) || errors.Is(err, permissions.ErrGuildConfigNotFound) {
+			c.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ticket := utils.RandomString(32)
+	err = ctrl.memStore.SetStreamTicket(c, ticket, memdb.StreamTicket{
+		UserDiscordID:  payload.UserDiscordID,
+		GuildDiscordID: uri.DiscordID,
+		Capabilities:   capabilities,
+	}, middlewares.StreamTicketDuration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticket":     ticket,
+		"expires_at": time.Now().Add(middlewares.StreamTicketDuration),
+	})
+}
+
+// GetGuildEvents streams guild events using SSE or WebSocket if upgrade was requested.
+// Events requiring a capability are sent only to subscribers having it when the stream was opened
+func (ctrl *EventsController) GetGuildEvents(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	_ = c.ShouldBindUri(&uri)
+
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	capabilities, err := ctrl.subscriberCapabilities(c, payload, uri.DiscordID)
+	if err != nil {
+		if errors.Is(err, permissions.ErrNoGuildRelation) || errors.Is(err, permissions.ErrGuildConfigNotFound) {
+			c.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	events, closeEvents, err := ctrl.memStore.SubscribeGuildEvents(c, uri.DiscordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer closeEvents()
+
+	if c.IsWebsocket() {
+		ctrl.streamWebsocket(c, events, capabilities)
+		return
+	}
+	ctrl.streamSSE(c, events, capabilities)
+}
+
+// subscriberCapabilities trusts claims of guild-scoped tokens and stream tickets and falls back to the database otherwise
+func (ctrl *EventsController) subscriberCapabilities(c *gin.Context, payload *token.Payload, guildDiscordID string) ([]string, error) {
+	if payload.Guild != nil {
+		return payload.Guild.Capabilities, nil
+	}
+	return permissions.GuildCapabilities(c, ctrl.store, payload.UserDiscordID, guildDiscordID)
+}
+
+// canReceiveEvent checks whether the subscriber has capability required by the event
+func canReceiveEvent(event memdb.GuildEvent, capabilities []string) bool {
+	if event.Capability == "" {
+		return true
+	}
+	for _, c := range capabilities {
+		if c == event.Capability {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctrl *EventsController) streamSSE(c *gin.Context, events <-chan memdb.GuildEvent, capabilities []string) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !canReceiveEvent(event, capabilities) {
+				continue
+			}
+			c.SSEvent(event.Type, event)
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", gin.H{"time": time.Now()})
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+func (ctrl *EventsController) streamWebsocket(c *gin.Context, events <-chan memdb.GuildEvent, capabilities []string) {
+	conn, err := ctrl.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// client messages are not expected, reading is only needed to notice disconnection
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !canReceiveEvent(event, capabilities) {
+				continue
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			deadline := time.Now().Add(eventsHeartbeatInterval)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				return
+			}
+		case <-clientGone:
+			return
+		}
+	}
+}
@@ -0,0 +1,387 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEventsController_GetGuildEvents(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	userDiscordID := utils.RandomSnowflakeID().String()
+	guild := generateRandomGuild()
+	guildConfigJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	require.NoError(t, err)
+	configEvent := memdb.GuildEvent{
+		Type:           memdb.GuildEventConfigUpdated,
+		GuildDiscordID: guild.DiscordID,
+		Data:           json.RawMessage(`{"preset":"default"}`),
+		CreatedAt:      time.Now(),
+	}
+	caseEvent := memdb.GuildEvent{
+		Type:           memdb.GuildEventCaseCreated,
+		GuildDiscordID: guild.DiscordID,
+		Capability:     token.CapabilityCasesRead,
+		Data:           json.RawMessage(`{"reason":"spam"}`),
+		CreatedAt:      time.Now(),
+	}
+
+	lockdownEvents := publishedLockdownEvents(t, guild.DiscordID)
+
+	tokenMaker, err := token.NewPasetoMaker(utils.RandomString(32))
+	require.NoError(t, err)
+	userToken, _, err := tokenMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+	moderatorToken, _, err := tokenMaker.CreateGuildToken(userDiscordID, token.GuildClaims{
+		DiscordID:    guild.DiscordID,
+		Capabilities: []string{token.CapabilityGuildConfigRead, token.CapabilityCasesRead},
+	}, time.Minute)
+	require.NoError(t, err)
+	configReaderToken, _, err := tokenMaker.CreateGuildToken(userDiscordID, token.GuildClaims{
+		DiscordID:    guild.DiscordID,
+		Capabilities: []string{token.CapabilityGuildConfigRead},
+	}, time.Minute)
+	require.NoError(t, err)
+
+	subscribe := func(memStore *mockmemdb.MockStore, published ...memdb.GuildEvent) {
+		events := make(chan memdb.GuildEvent, len(published))
+		for _, event := range published {
+			events <- event
+		}
+		close(events)
+		memStore.EXPECT().
+			SubscribeGuildEvents(gomock.Any(), gomock.Eq(guild.DiscordID)).
+			Times(1).
+			Return((<-chan memdb.GuildEvent)(events), func() error { return nil }, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		accessToken   string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK/ModeratorReceivesCaseEvents",
+			accessToken: moderatorToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+				subscribe(memStore, configEvent, caseEvent)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s", memdb.GuildEventConfigUpdated))
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s", memdb.GuildEventCaseCreated))
+				require.Contains(t, w.Body.String(), guild.DiscordID)
+			},
+		},
+		{
+			name:        "OK/ReadOnlyMemberSkipsCaseEvents",
+			accessToken: userToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{Permissions: int64(discordperm.ViewChannel)}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{Json: guildConfigJSON}, nil)
+				subscribe(memStore, caseEvent, configEvent)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s", memdb.GuildEventConfigUpdated))
+				require.NotContains(t, w.Body.String(), memdb.GuildEventCaseCreated)
+				require.NotContains(t, w.Body.String(), "spam")
+			},
+		},
+		{
+			name:        "OK/ModeratorReceivesLockdownEvents",
+			accessToken: moderatorToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				subscribe(memStore, lockdownEvents...)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s\n", memdb.GuildEventLockdown))
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s\n", memdb.GuildEventLockdownLifted))
+			},
+		},
+		{
+			name:        "OK/ConfigReaderSkipsLockdownEvents",
+			accessToken: configReaderToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				subscribe(memStore, append(lockdownEvents, configEvent)...)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), fmt.Sprintf("event:%s", memdb.GuildEventConfigUpdated))
+				require.NotContains(t, w.Body.String(), memdb.GuildEventLockdown)
+				require.NotContains(t, w.Body.String(), "raiders joined")
+			},
+		},
+		{
+			name:        "Forbidden/NoGuildRelation",
+			accessToken: userToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrNoRows)
+				memStore.EXPECT().SubscribeGuildEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:        "InternalServerError/MemDBSubscribeGuildEvents",
+			accessToken: moderatorToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					SubscribeGuildEvents(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(nil, nil, redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			eventsController := NewEventsController(store, memStore, utils.Config{})
+			router := gin.New()
+			router.GET("/api/v1/guilds/:discord_id/events", middlewares.NewAuthMiddleware(tokenMaker), eventsController.GetGuildEvents)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/events", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, tc.accessToken))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+// publishedLockdownEvents returns events the lockdown service publishes when the guild is locked and unlocked
+func publishedLockdownEvents(t *testing.T, guildDiscordID string) []memdb.GuildEvent {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var events []memdb.GuildEvent
+	memStore := mockmemdb.NewMockStore(ctrl)
+	memStore.EXPECT().
+		PublishBotCommand(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(int64(1), nil)
+	memStore.EXPECT().
+		PublishGuildEvent(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+			events = append(events, event)
+			return nil
+		})
+
+	lockdown := db.GuildLockdown{
+		GuildDiscordID: guildDiscordID,
+		Scope:          db.LockdownScopeAll,
+		Reason:         "raiders joined",
+		CreatedAt:      time.Now(),
+	}
+	lockdownService := services.NewLockdownService(mockdb.NewMockStore(ctrl), memStore)
+	lockdownService.Publish(context.Background(), lockdown)
+	lockdownService.PublishLift(context.Background(), lockdown)
+	return events
+}
+
+func TestEventsController_CreateEventsTicket(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	userDiscordID := utils.RandomSnowflakeID().String()
+	guild := generateRandomGuild()
+	guildConfigJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	require.NoError(t, err)
+
+	tokenMaker, err := token.NewPasetoMaker(utils.RandomString(32))
+	require.NoError(t, err)
+	userToken, _, err := tokenMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+	guildClaims := token.GuildClaims{
+		DiscordID:    guild.DiscordID,
+		Capabilities: []string{token.CapabilityGuildConfigRead, token.CapabilityCasesRead},
+	}
+	guildToken, _, err := tokenMaker.CreateGuildToken(userDiscordID, guildClaims, time.Minute)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		accessToken   string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK/Database",
+			accessToken: userToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{Permissions: int64(discordperm.ViewChannel)}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{Json: guildConfigJSON}, nil)
+				memStore.EXPECT().
+					SetStreamTicket(gomock.Any(), gomock.Any(), gomock.Eq(memdb.StreamTicket{
+						UserDiscordID:  userDiscordID,
+						GuildDiscordID: guild.DiscordID,
+						Capabilities:   []string{token.CapabilityGuildConfigRead},
+					}), gomock.Eq(middlewares.StreamTicketDuration)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Ticket string `json:"ticket"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Len(t, res.Ticket, 32)
+			},
+		},
+		{
+			name:        "OK/GuildClaims",
+			accessToken: guildToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+				memStore.EXPECT().
+					SetStreamTicket(gomock.Any(), gomock.Any(), gomock.Eq(memdb.StreamTicket{
+						UserDiscordID:  userDiscordID,
+						GuildDiscordID: guild.DiscordID,
+						Capabilities:   guildClaims.Capabilities,
+					}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:        "Forbidden/NoGuildRelation",
+			accessToken: userToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrNoRows)
+				memStore.EXPECT().SetStreamTicket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:        "InternalServerError/MemDBSetStreamTicket",
+			accessToken: guildToken,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					SetStreamTicket(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			eventsController := NewEventsController(store, memStore, utils.Config{})
+			router := gin.New()
+			router.POST("/api/v1/guilds/:discord_id/events/ticket", middlewares.NewAuthMiddleware(tokenMaker), eventsController.CreateEventsTicket)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/events/ticket", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, tc.accessToken))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestEventsController_CheckOrigin(t *testing.T) {
+	config := utils.Config{
+		DiscordRedirectURLs:         []string{"https://dashboard.example.com/callback"},
+		DiscordInviteBotRedirectURL: "https://dashboard.example.com/invite",
+	}
+	eventsController := NewEventsController(nil, nil, config)
+
+	testCases := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{name: "AllowedOrigin", origin: "https://dashboard.example.com", allowed: true},
+		{name: "NoOrigin", origin: "", allowed: true},
+		{name: "OtherOrigin", origin: "https://evil.example.com", allowed: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			require.Equal(t, tc.allowed, eventsController.upgrader.CheckOrigin(req))
+		})
+	}
+}
@@ -6,10 +6,12 @@ import (
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
-	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"time"
 )
 
 type GuildController struct {
@@ -17,13 +19,29 @@ type GuildController struct {
 }
 
 type ResponseGuild struct {
-	ID             int64  `json:"id"`
-	DiscordID      string `json:"discord_id"`
-	OwnerDiscordID string `json:"owner_discord_id"`
-	Name           string `json:"name"`
-	Icon           string `json:"icon"`
-	CanReadConfig  bool   `json:"can_read_config"`
-	CanEditConfig  bool   `json:"can_edit_config"`
+	ID             int64      `json:"id"`
+	DiscordID      string     `json:"discord_id"`
+	OwnerDiscordID string     `json:"owner_discord_id"`
+	Name           string     `json:"name"`
+	Icon           string     `json:"icon"`
+	BotPresent     bool       `json:"bot_present"`
+	BotJoinedAt    *time.Time `json:"bot_joined_at"`
+	CanReadConfig  bool       `json:"can_read_config"`
+	CanEditConfig  bool       `json:"can_edit_config"`
+}
+
+func nullTimeToPtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullStringToPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
 }
 
 func NewGuildController(store db.Store) *GuildController {
@@ -52,18 +70,32 @@ func (ctrl *GuildController) GetUserGuild(c *gin.Context) {
 		return
 	}
 
+	member := discordperm.Permissions(guild.Permissions)
+	configPermissions := objects.GuildConfigPermissions{
+		Read:            guild.ConfigRead,
+		Edit:            guild.ConfigEdit,
+		EveryoneCanRead: guild.ConfigEveryoneCanRead,
+	}
 	c.JSON(http.StatusOK, ResponseGuild{
 		ID:             guild.ID,
 		DiscordID:      guild.DiscordID,
 		OwnerDiscordID: guild.OwnerDiscordID,
 		Name:           guild.Name,
 		Icon:           guild.Icon,
-		CanReadConfig:  utils.AnyOfPermissions(guild.Permissions, guild.ConfigRead),
-		CanEditConfig:  utils.AnyOfPermissions(guild.Permissions, guild.ConfigEdit),
+		BotPresent:     guild.BotPresent,
+		BotJoinedAt:    nullTimeToPtr(guild.BotJoinedAt),
+		CanReadConfig:  configPermissions.CanRead(member),
+		CanEditConfig:  configPermissions.CanEdit(member),
 	})
 }
 
 func (ctrl *GuildController) GetUserGuilds(c *gin.Context) {
+	var query forms.GetUserGuildsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
 
 	guilds, err := ctrl.store.GetUserGuilds(c, payload.UserDiscordID)
@@ -74,14 +106,25 @@ func (ctrl *GuildController) GetUserGuilds(c *gin.Context) {
 
 	var rGuilds []ResponseGuild
 	for _, guild := range guilds {
+		if query.BotPresent != nil && *query.BotPresent != guild.BotPresent {
+			continue
+		}
+		member := discordperm.Permissions(guild.Permissions)
+		configPermissions := objects.GuildConfigPermissions{
+			Read:            guild.ConfigRead,
+			Edit:            guild.ConfigEdit,
+			EveryoneCanRead: guild.ConfigEveryoneCanRead,
+		}
 		rGuilds = append(rGuilds, ResponseGuild{
 			ID:             guild.ID,
 			DiscordID:      guild.DiscordID,
 			OwnerDiscordID: guild.OwnerDiscordID,
 			Name:           guild.Name,
 			Icon:           guild.Icon,
-			CanReadConfig:  utils.AnyOfPermissions(guild.Permissions, guild.ConfigRead),
-			CanEditConfig:  utils.AnyOfPermissions(guild.Permissions, guild.ConfigEdit),
+			BotPresent:     guild.BotPresent,
+			BotJoinedAt:    nullTimeToPtr(guild.BotJoinedAt),
+			CanReadConfig:  configPermissions.CanRead(member),
+			CanEditConfig:  configPermissions.CanEdit(member),
 		})
 	}
 
@@ -1,21 +1,41 @@
 package controllers
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares/permissions"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/appeal"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/automod"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/escalation"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/raid"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/verification"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sirupsen/logrus"
 	"net/http"
+	"time"
 )
 
 type GuildConfigController struct {
-	store db.Store
+	store    db.Store
+	memStore memdb.Store
 }
 
-func NewGuildConfigController(store db.Store) *GuildConfigController {
+func NewGuildConfigController(store db.Store, memStore memdb.Store) *GuildConfigController {
 	return &GuildConfigController{
-		store: store,
+		store:    store,
+		memStore: memStore,
 	}
 }
 
@@ -29,7 +49,7 @@ func (ctrl *GuildConfigController) GetGuildConfigPreset(c *gin.Context) {
 	var guildConfig objects.GuildConfig
 	switch uri.Preset {
 	case "default":
-		guildConfig = objects.DefaultGuildConfig
+		guildConfig = objects.NewDefaultGuildConfig()
 	}
 
 	c.JSON(http.StatusOK, guildConfig)
@@ -38,11 +58,15 @@ func (ctrl *GuildConfigController) GetGuildConfigPreset(c *gin.Context) {
 func (ctrl *GuildConfigController) OverwriteGuildConfig(c *gin.Context) {
 	var uri forms.RequireDiscordIDRequest
 	_ = c.ShouldBindUri(&uri)
-	var newGuildConfig forms.OverwriteGuildConfigJSON
+	var newGuildConfig = forms.NewOverwriteGuildConfigJSON()
 	if err := c.ShouldBindJSON(&newGuildConfig); err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	if err := validateGuildConfigData(newGuildConfig.Data); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
 	newGuildConfigJSON, err := json.Marshal(newGuildConfig)
 	if err != nil {
@@ -50,15 +74,36 @@ func (ctrl *GuildConfigController) OverwriteGuildConfig(c *gin.Context) {
 		return
 	}
 
-	_, err = ctrl.store.CreateOrUpdateGuildConfig(c, db.CreateOrUpdateGuildConfigParams{
-		DiscordID: uri.DiscordID,
-		Json:      newGuildConfigJSON,
+	var actorDiscordID sql.NullString
+	if payload, ok := c.Get(middlewares.AuthorizationPayloadKey); ok {
+		actorDiscordID = sql.NullString{String: payload.(*token.Payload).UserDiscordID, Valid: true}
+	}
+
+	var guildConfig db.GuildConfig
+	err = ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		guildConfig, err = q.CreateOrUpdateGuildConfig(c, db.CreateOrUpdateGuildConfigParams{
+			DiscordID: uri.DiscordID,
+			Json:      newGuildConfigJSON,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			ActorDiscordID: actorDiscordID,
+			GuildDiscordID: uri.DiscordID,
+			Action:         db.AuditActionGuildConfigOverwrite,
+			Data:           newGuildConfigJSON,
+		})
+		return err
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	ctrl.publishConfigUpdated(c, uri.DiscordID, guildConfig.Json)
 	c.JSON(http.StatusOK, gin.H{})
 }
 
@@ -74,3 +119,264 @@ func (ctrl *GuildConfigController) GetGuildConfig(c *gin.Context) {
 
 	c.JSON(http.StatusOK, guildConfig)
 }
+
+// bulk guild config result statuses
+const (
+	BulkStatusApplied   = "applied"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusPreview   = "preview"
+	BulkStatusSkipped   = "skipped"
+	BulkStatusForbidden = "forbidden"
+	BulkStatusNotFound  = "not_found"
+	BulkStatusInvalid   = "invalid"
+	BulkStatusFailed    = "failed"
+)
+
+type BulkGuildConfigResult struct {
+	GuildDiscordID string          `json:"guild_discord_id"`
+	Status         string          `json:"status"`
+	Error          string          `json:"error,omitempty"`
+	Changed        bool            `json:"changed"`
+	Config         json.RawMessage `json:"config,omitempty"`
+}
+
+func (r *BulkGuildConfigResult) fail(status string, err error) {
+	r.Status = status
+	r.Error = err.Error()
+	r.Config = nil
+}
+
+// BulkGuildConfig applies patch or preset to configs of many guilds. Every guild is checked separately,
+// atomic mode writes nothing unless all guilds pass, best effort mode writes every guild which passed.
+func (ctrl *GuildConfigController) BulkGuildConfig(c *gin.Context) {
+	var form forms.BulkGuildConfigJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if bytes.Equal(form.Patch, []byte("null")) {
+		form.Patch = nil
+	}
+	if (len(form.Patch) == 0) == (form.Preset == "") {
+		err := errors.New("either patch or preset must be set")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+	if payload.Guild != nil {
+		c.JSON(http.StatusForbidden, errorResponse(permissions.ErrTokenScopedToGuild))
+		return
+	}
+
+	results := make([]*BulkGuildConfigResult, 0, len(form.GuildDiscordIDs))
+	seen := make(map[string]bool, len(form.GuildDiscordIDs))
+	for _, guildDiscordID := range form.GuildDiscordIDs {
+		if seen[guildDiscordID] {
+			continue
+		}
+		seen[guildDiscordID] = true
+
+		result, err := ctrl.prepareBulkGuildConfig(c, payload.UserDiscordID, guildDiscordID, form)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		results = append(results, result)
+	}
+
+	ready := make([]*BulkGuildConfigResult, 0, len(results))
+	for _, result := range results {
+		if result.Status == BulkStatusPreview && result.Changed {
+			ready = append(ready, result)
+		}
+	}
+
+	if form.DryRun {
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "mode": form.Mode, "results": results})
+		return
+	}
+
+	failed := false
+	for _, result := range results {
+		if result.Status == BulkStatusPreview && !result.Changed {
+			result.Status = BulkStatusUnchanged
+		}
+		if result.Error != "" {
+			failed = true
+		}
+	}
+
+	if form.Mode == forms.BulkModeAtomic {
+		if failed {
+			for _, result := range ready {
+				result.Status = BulkStatusSkipped
+			}
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"dry_run": false, "mode": form.Mode, "results": results})
+			return
+		}
+
+		if len(ready) == 0 {
+			c.JSON(http.StatusOK, gin.H{"dry_run": false, "mode": form.Mode, "results": results})
+			return
+		}
+
+		err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+			for _, result := range ready {
+				if err := saveGuildConfig(c, q, payload.UserDiscordID, result.GuildDiscordID, result.Config); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for _, result := range ready {
+			result.Status = BulkStatusApplied
+		}
+	} else {
+		for _, result := range ready {
+			err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+				return saveGuildConfig(c, q, payload.UserDiscordID, result.GuildDiscordID, result.Config)
+			})
+			if err != nil {
+				result.fail(BulkStatusFailed, err)
+				continue
+			}
+			result.Status = BulkStatusApplied
+		}
+	}
+
+	for _, result := range ready {
+		if result.Status == BulkStatusApplied {
+			ctrl.publishConfigUpdated(c, result.GuildDiscordID, result.Config)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dry_run": false, "mode": form.Mode, "results": results})
+}
+
+// prepareBulkGuildConfig checks permissions of the user and computes new config of the guild,
+// guild specific problems are reported in the result, returned error means the whole request failed
+func (ctrl *GuildConfigController) prepareBulkGuildConfig(ctx context.Context, userDiscordID string, guildDiscordID string, form forms.BulkGuildConfigJSON) (*BulkGuildConfigResult, error) {
+	result := &BulkGuildConfigResult{GuildDiscordID: guildDiscordID}
+
+	userGuildRel, err := ctrl.store.GetUserGuildRel(ctx, db.GetUserGuildRelParams{
+		AccountDiscordID: userDiscordID,
+		GuildDiscordID:   guildDiscordID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			result.fail(BulkStatusForbidden, permissions.ErrNoGuildRelation)
+			return result, nil
+		}
+		return nil, err
+	}
+
+	guildConfig, err := ctrl.store.GetGuildConfig(ctx, guildDiscordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			result.fail(BulkStatusNotFound, permissions.ErrGuildConfigNotFound)
+			return result, nil
+		}
+		return nil, err
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return nil, err
+	}
+	if !guildConfigObj.Permissions.CanEdit(discordperm.Permissions(userGuildRel.Permissions)) {
+		result.fail(BulkStatusForbidden, permissions.ErrInsufficientPermission)
+		return result, nil
+	}
+
+	newJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	if err != nil {
+		return nil, err
+	}
+	if len(form.Patch) > 0 {
+		newJSON, err = utils.MergePatch(guildConfig.Json, form.Patch)
+		if err != nil {
+			result.fail(BulkStatusInvalid, err)
+			return result, nil
+		}
+	}
+
+	newConfig, err := normalizeGuildConfig(newJSON)
+	if err != nil {
+		result.fail(BulkStatusInvalid, err)
+		return result, nil
+	}
+	oldConfig, err := normalizeGuildConfig(guildConfig.Json)
+
+	result.Status = BulkStatusPreview
+	result.Changed = err != nil || !bytes.Equal(oldConfig, newConfig)
+	result.Config = newConfig
+	return result, nil
+}
+
+func (ctrl *GuildConfigController) publishConfigUpdated(c *gin.Context, guildDiscordID string, config json.RawMessage) {
+	// config is already saved, so failed notification must not fail the request
+	err := ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           memdb.GuildEventConfigUpdated,
+		GuildDiscordID: guildDiscordID,
+		Data:           config,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish guild config update: %v", err.Error())
+	}
+}
+
+// normalizeGuildConfig validates the config the same way as overwrite request does and re-encodes it
+func normalizeGuildConfig(data []byte) ([]byte, error) {
+	var config = forms.NewOverwriteGuildConfigJSON()
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if err := binding.Validator.ValidateStruct(&config); err != nil {
+		return nil, err
+	}
+	if err := validateGuildConfigData(config.Data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// validateGuildConfigData runs checks of config modules which binding tags can not express
+func validateGuildConfigData(data objects.GuildConfigData) error {
+	if _, err := automod.Compile(data.Automod); err != nil {
+		return err
+	}
+	if err := escalation.Validate(data.Escalation); err != nil {
+		return err
+	}
+	if err := appeal.ValidateConfig(data.Appeals); err != nil {
+		return err
+	}
+	if err := verification.ValidateConfig(data.Verification); err != nil {
+		return err
+	}
+	return raid.Validate(data.Raid)
+}
+
+func saveGuildConfig(ctx context.Context, q *db.Queries, actorDiscordID string, guildDiscordID string, config json.RawMessage) error {
+	_, err := q.CreateOrUpdateGuildConfig(ctx, db.CreateOrUpdateGuildConfigParams{
+		DiscordID: guildDiscordID,
+		Json:      config,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAuditLog(ctx, db.CreateAuditLogParams{
+		ActorDiscordID: sql.NullString{String: actorDiscordID, Valid: true},
+		GuildDiscordID: guildDiscordID,
+		Action:         db.AuditActionGuildConfigOverwrite,
+		Data:           config,
+	})
+	return err
+}
@@ -5,10 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
 	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"net/http"
@@ -42,7 +49,7 @@ func TestGuildConfigController_GetGuildConfigPreset(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			guildConfigController := NewGuildConfigController(nil)
+			guildConfigController := NewGuildConfigController(nil, nil)
 			router := gin.New()
 			router.GET("/api/v1/guilds/configs/presets/:preset", guildConfigController.GetGuildConfigPreset)
 
@@ -75,60 +82,158 @@ func TestGuildConfigController_OverwriteGuildConfig(t *testing.T) {
 	}
 	guildConfigJSON, err := json.Marshal(guildConfigObj)
 	require.NoError(t, err)
-	guildConfig := db.GuildConfig{
-		ID:        guild.ID,
-		Json:      guildConfigJSON,
-		CreatedAt: time.Time{},
+
+	invalidAutomodObj := guildConfigObj
+	invalidAutomodObj.Data.Automod = objects.AutomodConfig{
+		Enabled: true,
+		Rules: []objects.AutomodRule{{
+			Name:     "links",
+			Type:     objects.AutomodRuleRegex,
+			Enabled:  true,
+			Actions:  []string{objects.AutomodActionDelete},
+			Patterns: []string{"https?://("},
+		}},
+	}
+	invalidAutomodJSON, err := json.Marshal(invalidAutomodObj)
+	require.NoError(t, err)
+
+	invalidEscalationObj := guildConfigObj
+	invalidEscalationObj.Data.Escalation = objects.EscalationConfig{
+		Enabled: true,
+		Steps:   []objects.EscalationStep{{Points: 3, Action: objects.AutomodActionMute}},
 	}
+	invalidEscalationJSON, err := json.Marshal(invalidEscalationObj)
+	require.NoError(t, err)
+
+	verificationWithoutRoleObj := guildConfigObj
+	verificationWithoutRoleObj.Data.Verification = objects.VerificationConfig{Enabled: true, Difficulty: 16}
+	verificationWithoutRoleJSON, err := json.Marshal(verificationWithoutRoleObj)
+	require.NoError(t, err)
+
+	raidWithoutThresholdsObj := guildConfigObj
+	raidWithoutThresholdsObj.Data.Raid = objects.RaidConfig{Enabled: true, WindowSeconds: 60}
+	raidWithoutThresholdsJSON, err := json.Marshal(raidWithoutThresholdsObj)
+	require.NoError(t, err)
 
 	testCases := []struct {
 		name            string
 		guildDiscordID  string
 		guildConfigJSON []byte
-		buildStubs      func(store *mockdb.MockStore)
+		buildStubs      func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
 		checkResponse   func(t *testing.T, w *httptest.ResponseRecorder)
 	}{
 		{
 			name:            "OK",
 			guildDiscordID:  guild.DiscordID,
 			guildConfigJSON: guildConfigJSON,
-			buildStubs: func(store *mockdb.MockStore) {
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
 				store.EXPECT().
-					CreateOrUpdateGuildConfig(gomock.Any(), gomock.Eq(db.CreateOrUpdateGuildConfigParams{
-						DiscordID: guild.DiscordID,
-						Json:      guildConfigJSON,
-					})).
+					ExecTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(guildConfig, nil)
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
 			},
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, w.Code)
 			},
 		},
 		{
-			name:            "InternalServerError/DBCreateOrUpdateGuildConfig",
+			name:            "InternalServerError/DBExecTx",
 			guildDiscordID:  guild.DiscordID,
 			guildConfigJSON: guildConfigJSON,
-			buildStubs: func(store *mockdb.MockStore) {
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
 				store.EXPECT().
-					CreateOrUpdateGuildConfig(gomock.Any(), gomock.Eq(db.CreateOrUpdateGuildConfigParams{
-						DiscordID: guild.DiscordID,
-						Json:      guildConfigJSON,
-					})).
+					ExecTx(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.GuildConfig{}, sql.ErrConnDone)
+					Return(sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, w.Code)
 			},
 		},
+		{
+			name:            "OK/PublishGuildEventFailed",
+			guildDiscordID:  guild.DiscordID,
+			guildConfigJSON: guildConfigJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
 		{
 			name:            "BadRequest/JSON",
 			guildDiscordID:  guild.DiscordID,
 			guildConfigJSON: []byte("not_json"),
-			buildStubs: func(store *mockdb.MockStore) {
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:            "BadRequest/Automod",
+			guildDiscordID:  guild.DiscordID,
+			guildConfigJSON: invalidAutomodJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
 				store.EXPECT().
-					CreateOrUpdateGuildConfig(gomock.Any(), gomock.Any()).
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:            "BadRequest/Escalation",
+			guildDiscordID:  guild.DiscordID,
+			guildConfigJSON: invalidEscalationJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:            "BadRequest/VerificationWithoutRole",
+			guildDiscordID:  guild.DiscordID,
+			guildConfigJSON: verificationWithoutRoleJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:            "BadRequest/RaidWithoutThresholds",
+			guildDiscordID:  guild.DiscordID,
+			guildConfigJSON: raidWithoutThresholdsJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
@@ -143,9 +248,10 @@ func TestGuildConfigController_OverwriteGuildConfig(t *testing.T) {
 			defer ctrl.Finish()
 
 			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
 
-			guildConfigController := NewGuildConfigController(store)
+			guildConfigController := NewGuildConfigController(store, memStore)
 			router := gin.New()
 			router.POST("/api/v1/guilds/:discord_id/config", guildConfigController.OverwriteGuildConfig)
 
@@ -226,7 +332,7 @@ func TestGuildConfigController_GetGuildConfig(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			guildConfigController := NewGuildConfigController(store)
+			guildConfigController := NewGuildConfigController(store, nil)
 			router := gin.New()
 			router.GET("/api/v1/guilds/:discord_id/config", guildConfigController.GetGuildConfig)
 
@@ -241,3 +347,231 @@ func TestGuildConfigController_GetGuildConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGuildConfigController_BulkGuildConfig(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	user := generateRandomUser()
+	editable := generateRandomGuild()
+	readOnly := generateRandomGuild()
+
+	defaultConfigJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	require.NoError(t, err)
+
+	type bulkResponse struct {
+		DryRun  bool                    `json:"dry_run"`
+		Results []BulkGuildConfigResult `json:"results"`
+	}
+	requireResults := func(t *testing.T, w *httptest.ResponseRecorder, statuses ...string) bulkResponse {
+		var res bulkResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		require.Len(t, res.Results, len(statuses))
+		for i, status := range statuses {
+			require.Equal(t, status, res.Results[i].Status)
+		}
+		return res
+	}
+	stubGuild := func(store *mockdb.MockStore, guildDiscordID string, permissions discordperm.Permissions) {
+		store.EXPECT().
+			GetUserGuildRel(gomock.Any(), gomock.Eq(db.GetUserGuildRelParams{
+				AccountDiscordID: user.DiscordID,
+				GuildDiscordID:   guildDiscordID,
+			})).
+			Times(1).
+			Return(db.UserGuild{Permissions: int64(permissions)}, nil)
+		store.EXPECT().
+			GetGuildConfig(gomock.Any(), gomock.Eq(guildDiscordID)).
+			Times(1).
+			Return(db.GuildConfig{Json: defaultConfigJSON}, nil)
+	}
+	bothGuilds := []string{editable.DiscordID, readOnly.DiscordID}
+	patch := json.RawMessage(`{"data":{"use_config":true}}`)
+
+	testCases := []struct {
+		name          string
+		body          forms.BulkGuildConfigJSON
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/DryRun",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: bothGuilds, Patch: patch, Mode: forms.BulkModeAtomic, DryRun: true},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				stubGuild(store, readOnly.DiscordID, discordperm.ViewChannel)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				res := requireResults(t, w, BulkStatusPreview, BulkStatusForbidden)
+				require.True(t, res.DryRun)
+				require.True(t, res.Results[0].Changed)
+
+				var config objects.GuildConfig
+				require.NoError(t, json.Unmarshal(res.Results[0].Config, &config))
+				require.True(t, config.Data.UseConfig)
+				require.Equal(t, objects.NewDefaultGuildConfig().Permissions, config.Permissions)
+			},
+		},
+		{
+			name: "UnprocessableEntity/Atomic",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: bothGuilds, Patch: patch, Mode: forms.BulkModeAtomic},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				stubGuild(store, readOnly.DiscordID, discordperm.ViewChannel)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(0)
+				memStore.EXPECT().PublishGuildEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+				requireResults(t, w, BulkStatusSkipped, BulkStatusForbidden)
+			},
+		},
+		{
+			name: "OK/Atomic",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: bothGuilds, Patch: patch, Mode: forms.BulkModeAtomic},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				stubGuild(store, readOnly.DiscordID, discordperm.Administrator)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				memStore.EXPECT().PublishGuildEvent(gomock.Any(), gomock.Any()).Times(2).Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				requireResults(t, w, BulkStatusApplied, BulkStatusApplied)
+			},
+		},
+		{
+			name: "OK/Unchanged",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: []string{editable.DiscordID}, Preset: "default", Mode: forms.BulkModeAtomic},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				requireResults(t, w, BulkStatusUnchanged)
+			},
+		},
+		{
+			name: "OK/BestEffort",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: append(bothGuilds, editable.DiscordID), Patch: patch, Mode: forms.BulkModeBestEffort},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				stubGuild(store, readOnly.DiscordID, discordperm.ViewChannel)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				memStore.EXPECT().PublishGuildEvent(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				requireResults(t, w, BulkStatusApplied, BulkStatusForbidden)
+			},
+		},
+		{
+			name: "OK/BestEffortWriteFailed",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: []string{editable.DiscordID}, Patch: patch, Mode: forms.BulkModeBestEffort},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+				memStore.EXPECT().PublishGuildEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				requireResults(t, w, BulkStatusFailed)
+			},
+		},
+		{
+			name: "OK/InvalidPatch",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: []string{editable.DiscordID}, Patch: json.RawMessage(`{"preset":"unknown"}`), Mode: forms.BulkModeBestEffort},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				stubGuild(store, editable.DiscordID, discordperm.ManageGuild)
+				store.EXPECT().ExecTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				requireResults(t, w, BulkStatusInvalid)
+			},
+		},
+		{
+			name: "NotFound/Guild",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: []string{editable.DiscordID}, Patch: patch, Mode: forms.BulkModeBestEffort},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{Permissions: int64(discordperm.ManageGuild)}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				requireResults(t, w, BulkStatusNotFound)
+			},
+		},
+		{
+			name: "BadRequest/PatchAndPreset",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: bothGuilds, Patch: patch, Preset: "default", Mode: forms.BulkModeAtomic},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/Mode",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: bothGuilds, Patch: patch, Mode: "some"},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetUserGuildRel",
+			body: forms.BulkGuildConfigJSON{GuildDiscordIDs: bothGuilds, Patch: patch, Mode: forms.BulkModeAtomic},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			tokenMaker, err := token.NewPasetoMaker(utils.RandomString(32))
+			require.NoError(t, err)
+			guildConfigController := NewGuildConfigController(store, memStore)
+			router := gin.New()
+			router.POST("/api/v1/guilds/configs/bulk", middlewares.NewAuthMiddleware(tokenMaker), guildConfigController.BulkGuildConfig)
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/guilds/configs/bulk", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			accessToken, _, err := tokenMaker.CreateToken(user.DiscordID, time.Minute)
+			require.NoError(t, err)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -151,6 +151,7 @@ func TestGuildController_GetUserGuilds(t *testing.T) {
 		Icon:           guild.Icon,
 		Name:           guild.Name,
 		Permissions:    accountGuildRel.Permissions,
+		BotPresent:     true,
 		ConfigRead:     0xfffffffff,
 		ConfigEdit:     40,
 	}
@@ -158,6 +159,7 @@ func TestGuildController_GetUserGuilds(t *testing.T) {
 	testCases := []struct {
 		name             string
 		accountDiscordID string
+		query            string
 		buildStubs       func(store *mockdb.MockStore)
 		checkResponse    func(t *testing.T, w *httptest.ResponseRecorder)
 	}{
@@ -172,6 +174,35 @@ func TestGuildController_GetUserGuilds(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), guild.DiscordID)
+			},
+		},
+		{
+			name:             "OK/FilterBotPresent",
+			accountDiscordID: account.DiscordID,
+			query:            "?bot_present=false",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuilds(gomock.Any(), gomock.Eq(account.DiscordID)).
+					Times(1).
+					Return([]db.GetUserGuildsRow{guildRow}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.NotContains(t, w.Body.String(), guild.DiscordID)
+			},
+		},
+		{
+			name:             "BadRequest/Query",
+			accountDiscordID: account.DiscordID,
+			query:            "?bot_present=maybe",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuilds(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
 			},
 		},
 		{
@@ -202,7 +233,7 @@ func TestGuildController_GetUserGuilds(t *testing.T) {
 			router := gin.New()
 			router.GET("/api/v1/users/me/guilds", authMiddleware, guildController.GetUserGuilds)
 
-			url := "/api/v1/users/me/guilds"
+			url := "/api/v1/users/me/guilds" + tc.query
 			req, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 			accessToken, _, err := tokenMaker.CreateToken(tc.accountDiscordID, time.Minute)
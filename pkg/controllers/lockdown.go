@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+var (
+	errNotLockedDown    = errors.New("guild is not locked down")
+	errLockdownChannels = errors.New("channel_discord_ids are required by channels scope and not allowed otherwise")
+)
+
+type LockdownController struct {
+	store           db.Store
+	lockdownService *services.LockdownService
+}
+
+func NewLockdownController(store db.Store, lockdownService *services.LockdownService) *LockdownController {
+	return &LockdownController{
+		store:           store,
+		lockdownService: lockdownService,
+	}
+}
+
+// GetLockdown returns lockdown of the guild, null when the guild is not locked down.
+// The bot restores channel overwrites from it after missing commands.
+func (ctrl *LockdownController) GetLockdown(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	lockdown, err := ctrl.store.GetGuildLockdown(c, uri.DiscordID)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusOK, gin.H{"lockdown": nil})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lockdown": services.NewLockdown(lockdown)})
+}
+
+// LockdownGuild locks all or selected channels of the guild, replacing the current lockdown
+func (ctrl *LockdownController) LockdownGuild(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.LockdownJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if (form.Scope == db.LockdownScopeChannels) != (len(form.ChannelDiscordIDs) > 0) {
+		c.JSON(http.StatusBadRequest, errorResponse(errLockdownChannels))
+		return
+	}
+	var liftsAt sql.NullTime
+	if form.LiftsAt != nil {
+		if !form.LiftsAt.After(time.Now()) {
+			err := errors.New("lifts_at must be in the future")
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		liftsAt = sql.NullTime{Time: *form.LiftsAt, Valid: true}
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var lockdown db.GuildLockdown
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		lockdown, err = q.SetGuildLockdown(c, db.SetGuildLockdownParams{
+			GuildDiscordID:    uri.DiscordID,
+			Scope:             form.Scope,
+			ChannelDiscordIDs: form.ChannelDiscordIDs,
+			Reason:            form.Reason,
+			ActorDiscordID:    sql.NullString{String: payload.UserDiscordID, Valid: true},
+			LiftsAt:           liftsAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(services.NewLockdown(lockdown))
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			ActorDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+			GuildDiscordID: uri.DiscordID,
+			Action:         db.AuditActionGuildLockdown,
+			Data:           data,
+		})
+		return err
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			err := errors.New("guild not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctrl.lockdownService.Publish(c, lockdown)
+	c.JSON(http.StatusOK, services.NewLockdown(lockdown))
+}
+
+// LiftLockdown unlocks the guild before the lockdown lifts itself
+func (ctrl *LockdownController) LiftLockdown(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var lifted db.GuildLockdown
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		lifted, err = q.DeleteGuildLockdown(c, uri.DiscordID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errNotLockedDown
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(services.NewLockdown(lifted))
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			ActorDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+			GuildDiscordID: uri.DiscordID,
+			Action:         db.AuditActionGuildLockdownLift,
+			Data:           data,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, errNotLockedDown) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctrl.lockdownService.PublishLift(c, lifted)
+	c.Status(http.StatusNoContent)
+}
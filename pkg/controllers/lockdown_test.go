@@ -0,0 +1,386 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newLockdownRouter routes lockdown endpoints of moderators
+func newLockdownRouter(store *mockdb.MockStore, memStore *mockmemdb.MockStore) *gin.Engine {
+	lockdownController := NewLockdownController(store, services.NewLockdownService(store, memStore))
+	router := newAuthorizedRouter()
+	router.GET("/api/v1/guilds/:discord_id/lockdown", lockdownController.GetLockdown)
+	router.POST("/api/v1/guilds/:discord_id/lockdown", lockdownController.LockdownGuild)
+	router.DELETE("/api/v1/guilds/:discord_id/lockdown", lockdownController.LiftLockdown)
+	return router
+}
+
+func TestLockdownController_GetLockdown(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateRandomUser()
+	lockdown := db.GuildLockdown{
+		GuildDiscordID: guild.DiscordID,
+		Scope:          db.LockdownScopeAll,
+		Reason:         utils.RandomString(20),
+		ActorDiscordID: sql.NullString{String: user.DiscordID, Valid: true},
+		CreatedAt:      time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/LockedDown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildLockdown(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(lockdown, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Lockdown *services.Lockdown `json:"lockdown"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.NotNil(t, res.Lockdown)
+				require.Equal(t, lockdown.Reason, res.Lockdown.Reason)
+				require.Equal(t, user.DiscordID, *res.Lockdown.ActorDiscordID)
+				require.Empty(t, res.Lockdown.ChannelDiscordIDs)
+				require.Nil(t, res.Lockdown.LiftsAt)
+			},
+		},
+		{
+			name: "OK/NotLockedDown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildLockdown(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildLockdown{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.JSONEq(t, `{"lockdown":null}`, w.Body.String())
+			},
+		},
+		{
+			name: "InternalServerError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildLockdown(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildLockdown{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/lockdown", guild.DiscordID)
+			w := serveAuthorized(t, newLockdownRouter(store, mockmemdb.NewMockStore(ctrl)), user.DiscordID, http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestLockdownController_LockdownGuild(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateRandomUser()
+	channelDiscordID := utils.RandomSnowflakeID().String()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/All",
+			body: gin.H{
+				"scope":    db.LockdownScopeAll,
+				"reason":   "raid",
+				"lifts_at": time.Now().Add(time.Hour),
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, command memdb.BotCommand) (int64, error) {
+						require.Equal(t, memdb.BotCommandLockdown, command.Type)
+						return 1, nil
+					})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventLockdown, event.Type)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "OK/Channels",
+			body: gin.H{
+				"scope":               db.LockdownScopeChannels,
+				"channel_discord_ids": []string{channelDiscordID},
+				"reason":              "spam",
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/ChannelsWithoutIDs",
+			body: gin.H{
+				"scope":  db.LockdownScopeChannels,
+				"reason": "spam",
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/AllWithIDs",
+			body: gin.H{
+				"scope":               db.LockdownScopeAll,
+				"channel_discord_ids": []string{channelDiscordID},
+				"reason":              "raid",
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/LiftsAtPast",
+			body: gin.H{
+				"scope":    db.LockdownScopeAll,
+				"reason":   "raid",
+				"lifts_at": time.Now().Add(-time.Minute),
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/NoReason",
+			body: gin.H{
+				"scope": db.LockdownScopeAll,
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound/Guild",
+			body: gin.H{
+				"scope":  db.LockdownScopeAll,
+				"reason": "raid",
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&pq.Error{Code: "23503"})
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			body: gin.H{
+				"scope":  db.LockdownScopeAll,
+				"reason": "raid",
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/api/v1/guilds/%s/lockdown", guild.DiscordID)
+			w := serveAuthorized(t, newLockdownRouter(store, memStore), user.DiscordID, http.MethodPost, url, body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestLockdownController_LiftLockdown(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateRandomUser()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "NoContent",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, command memdb.BotCommand) (int64, error) {
+						require.Equal(t, memdb.BotCommandLiftLockdown, command.Type)
+						return 1, nil
+					})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventLockdownLifted, event.Type)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, w.Code)
+			},
+		},
+		{
+			name: "NotFound/NotLockedDown",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errNotLockedDown)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/lockdown", guild.DiscordID)
+			w := serveAuthorized(t, newLockdownRouter(store, memStore), user.DiscordID, http.MethodDelete, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -4,10 +4,13 @@ import "github.com/gin-gonic/gin"
 
 type User interface {
 	GetUser(c *gin.Context)
+	ExportUser(c *gin.Context)
+	DeleteUser(c *gin.Context)
 }
 
 type Auth interface {
 	RefreshToken(c *gin.Context)
+	CreateGuildToken(c *gin.Context)
 }
 
 type Guild interface {
@@ -17,6 +20,7 @@ type Guild interface {
 
 type GuildConfig interface {
 	OverwriteGuildConfig(c *gin.Context)
+	BulkGuildConfig(c *gin.Context)
 	GetGuildConfig(c *gin.Context)
 	GetGuildConfigPreset(c *gin.Context)
 }
@@ -25,6 +29,82 @@ type Oauth2 interface {
 	GetNewOauth2URL(c *gin.Context)
 	GetNewInviteBotURL(c *gin.Context)
 	HandleDiscordCallback(c *gin.Context)
+	PollOauth2Flow(c *gin.Context)
+	HandleInviteBotCallback(c *gin.Context)
+}
+
+type Events interface {
+	CreateEventsTicket(c *gin.Context)
+	GetGuildEvents(c *gin.Context)
+}
+
+type Bot interface {
+	JoinGuild(c *gin.Context)
+	LeaveGuild(c *gin.Context)
+	TransferGuildOwner(c *gin.Context)
+	GetCommands(c *gin.Context)
+	AckCommand(c *gin.Context)
+}
+
+type Case interface {
+	CreateCase(c *gin.Context)
+	GetCase(c *gin.Context)
+	GetCases(c *gin.Context)
+	RescheduleCaseExpiry(c *gin.Context)
+	CancelCaseExpiry(c *gin.Context)
+}
+
+type Automod interface {
+	GetAutomodRuleset(c *gin.Context)
+	EvaluateAutomod(c *gin.Context)
+	TestAutomod(c *gin.Context)
+}
+
+type Escalation interface {
+	EscalateInfraction(c *gin.Context)
+}
+
+type Member interface {
+	GetMemberProfile(c *gin.Context)
+	CreateMemberNote(c *gin.Context)
+	UpdateMemberNote(c *gin.Context)
+	DeleteMemberNote(c *gin.Context)
+	GetMemberNoteRevisions(c *gin.Context)
+	ReportMembersSeen(c *gin.Context)
+}
+
+type Appeal interface {
+	GetUserBans(c *gin.Context)
+	SubmitAppeal(c *gin.Context)
+	GetUserAppeals(c *gin.Context)
+	GetAppeals(c *gin.Context)
+	GetAppeal(c *gin.Context)
+	CreateAppealComment(c *gin.Context)
+	DecideAppeal(c *gin.Context)
+}
+
+type Verification interface {
+	GetVerification(c *gin.Context)
+	SubmitVerification(c *gin.Context)
+	GetMemberVerification(c *gin.Context)
+}
+
+type Raid interface {
+	ReportMemberEvents(c *gin.Context)
+	GetRaidTimeline(c *gin.Context)
+	GetRaidIncidents(c *gin.Context)
+	ResolveRaidIncident(c *gin.Context)
+}
+
+type Lockdown interface {
+	GetLockdown(c *gin.Context)
+	LockdownGuild(c *gin.Context)
+	LiftLockdown(c *gin.Context)
+}
+
+type WellKnown interface {
+	GetPaserk(c *gin.Context)
+	GetJWKS(c *gin.Context)
 }
 
 type Controllers struct {
@@ -33,6 +113,17 @@ type Controllers struct {
 	Guild
 	GuildConfig
 	Oauth2
+	Events
+	Bot
+	Case
+	Automod
+	Escalation
+	Member
+	Appeal
+	Verification
+	Raid
+	Lockdown
+	WellKnown
 }
 
 func errorResponse(err error) gin.H {
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testTokenMaker signs access tokens of requests sent by serveAuthorized
+var testTokenMaker = func() token.Maker {
+	tokenMaker, err := token.NewPasetoMaker(utils.RandomString(32))
+	if err != nil {
+		panic(err)
+	}
+	return tokenMaker
+}()
+
+// generateGuildConfig returns the default guild config changed by edit
+func generateGuildConfig(t *testing.T, edit func(config *objects.GuildConfig)) db.GuildConfig {
+	guildConfigObj := objects.NewDefaultGuildConfig()
+	edit(&guildConfigObj)
+	guildConfigJSON, err := json.Marshal(guildConfigObj)
+	require.NoError(t, err)
+	return db.GuildConfig{
+		ID:   int64(utils.RandomInt(1, 1000)),
+		Json: guildConfigJSON,
+	}
+}
+
+// newAuthorizedRouter returns router which authenticates requests sent by serveAuthorized
+func newAuthorizedRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(middlewares.NewAuthMiddleware(testTokenMaker))
+	return router
+}
+
+// serveAuthorized sends the request authorized as the given user through the router
+func serveAuthorized(t *testing.T, router *gin.Engine, userDiscordID, method, url string, body []byte) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	accessToken, _, err := testTokenMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+	req.Header.Set(middlewares.AuthorizationHeaderKey, fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,339 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+type MemberController struct {
+	store             db.Store
+	escalationService *services.EscalationService
+}
+
+func NewMemberController(store db.Store, escalationService *services.EscalationService) *MemberController {
+	return &MemberController{
+		store:             store,
+		escalationService: escalationService,
+	}
+}
+
+type ResponseMemberNote struct {
+	ID int64 `json:"id"`
+	// AuthorDiscordID is null if the author deleted the account
+	AuthorDiscordID *string    `json:"author_discord_id"`
+	Content         string     `json:"content"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+func newResponseMemberNote(note db.MemberNote) ResponseMemberNote {
+	return ResponseMemberNote{
+		ID:              note.ID,
+		AuthorDiscordID: nullStringToPtr(note.AuthorDiscordID),
+		Content:         note.Content,
+		CreatedAt:       note.CreatedAt,
+		UpdatedAt:       note.UpdatedAt,
+		DeletedAt:       nullTimeToPtr(note.DeletedAt),
+	}
+}
+
+type ResponseMemberNoteRevision struct {
+	ID     int64  `json:"id"`
+	NoteID int64  `json:"note_id"`
+	Action string `json:"action"`
+	// EditorDiscordID is null if the editor deleted the account
+	EditorDiscordID *string   `json:"editor_discord_id"`
+	Content         string    `json:"content"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func newResponseMemberNoteRevision(r db.MemberNoteRevision) ResponseMemberNoteRevision {
+	return ResponseMemberNoteRevision{
+		ID:              r.ID,
+		NoteID:          r.NoteID,
+		Action:          r.Action,
+		EditorDiscordID: nullStringToPtr(r.EditorDiscordID),
+		Content:         r.Content,
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+type ResponseMemberProfile struct {
+	GuildDiscordID  string     `json:"guild_discord_id"`
+	MemberDiscordID string     `json:"member_discord_id"`
+	FirstSeenAt     *time.Time `json:"first_seen_at"`
+	LastSeenAt      *time.Time `json:"last_seen_at"`
+	// Standing is sum of infraction points under escalation policy of the guild
+	Standing int `json:"standing"`
+	// Cases are the newest cases of the member, older ones are listed by the cases endpoint
+	Cases []ResponseCase       `json:"cases"`
+	Notes []ResponseMemberNote `json:"notes"`
+}
+
+// GetMemberProfile aggregates everything moderators know about the member of the guild
+func (ctrl *MemberController) GetMemberProfile(c *gin.Context) {
+	var uri forms.MemberURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	profile := ResponseMemberProfile{
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: uri.MemberID,
+	}
+
+	member, err := ctrl.store.GetGuildMember(c, db.GetGuildMemberParams{
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: uri.MemberID,
+	})
+	switch {
+	case err == nil:
+		profile.FirstSeenAt = &member.FirstSeenAt
+		profile.LastSeenAt = &member.LastSeenAt
+	case !errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	moderationCases, err := ctrl.store.GetModerationCases(c, db.GetModerationCasesParams{
+		GuildDiscordID:  uri.DiscordID,
+		TargetDiscordID: uri.MemberID,
+		MaxResults:      defaultCasesLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	profile.Cases = make([]ResponseCase, 0, len(moderationCases))
+	for _, moderationCase := range moderationCases {
+		profile.Cases = append(profile.Cases, newResponseCase(moderationCase))
+	}
+
+	policy, err := ctrl.escalationService.Policy(c, uri.DiscordID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	profile.Standing, err = ctrl.escalationService.Standing(c, policy, uri.DiscordID, uri.MemberID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	notes, err := ctrl.store.GetMemberNotes(c, db.GetMemberNotesParams{
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: uri.MemberID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	profile.Notes = make([]ResponseMemberNote, 0, len(notes))
+	for _, note := range notes {
+		profile.Notes = append(profile.Notes, newResponseMemberNote(note))
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (ctrl *MemberController) CreateMemberNote(c *gin.Context) {
+	var uri forms.MemberURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.MemberNoteJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var note db.MemberNote
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		note, err = q.CreateMemberNote(c, db.CreateMemberNoteParams{
+			GuildDiscordID:  uri.DiscordID,
+			MemberDiscordID: uri.MemberID,
+			AuthorDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+			Content:         form.Content,
+		})
+		if err != nil {
+			return err
+		}
+		return ctrl.createRevision(c, q, db.NoteRevisionCreate, note, payload.UserDiscordID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, newResponseMemberNote(note))
+}
+
+// UpdateMemberNote replaces content of the note, previous content stays in revision history
+func (ctrl *MemberController) UpdateMemberNote(c *gin.Context) {
+	var uri forms.MemberNoteURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.MemberNoteJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var note db.MemberNote
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		note, err = q.UpdateMemberNote(c, db.UpdateMemberNoteParams{
+			ID:              uri.NoteID,
+			GuildDiscordID:  uri.DiscordID,
+			MemberDiscordID: uri.MemberID,
+			Content:         form.Content,
+		})
+		if err != nil {
+			return err
+		}
+		return ctrl.createRevision(c, q, db.NoteRevisionEdit, note, payload.UserDiscordID)
+	})
+	if err != nil {
+		ctrl.noteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newResponseMemberNote(note))
+}
+
+// DeleteMemberNote hides the note from the profile, its revision history is kept
+func (ctrl *MemberController) DeleteMemberNote(c *gin.Context) {
+	var uri forms.MemberNoteURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var note db.MemberNote
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		note, err = q.DeleteMemberNote(c, db.DeleteMemberNoteParams{
+			ID:              uri.NoteID,
+			GuildDiscordID:  uri.DiscordID,
+			MemberDiscordID: uri.MemberID,
+		})
+		if err != nil {
+			return err
+		}
+		return ctrl.createRevision(c, q, db.NoteRevisionDelete, note, payload.UserDiscordID)
+	})
+	if err != nil {
+		ctrl.noteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newResponseMemberNote(note))
+}
+
+// GetMemberNoteRevisions returns edit history of the note from its creation, deleted notes included
+func (ctrl *MemberController) GetMemberNoteRevisions(c *gin.Context) {
+	var uri forms.MemberNoteURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	note, err := ctrl.store.GetMemberNote(c, db.GetMemberNoteParams{
+		ID:              uri.NoteID,
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: uri.MemberID,
+	})
+	if err != nil {
+		ctrl.noteError(c, err)
+		return
+	}
+
+	revisions, err := ctrl.store.GetMemberNoteRevisions(c, note.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	res := make([]ResponseMemberNoteRevision, 0, len(revisions))
+	for _, r := range revisions {
+		res = append(res, newResponseMemberNoteRevision(r))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"note":      newResponseMemberNote(note),
+		"revisions": res,
+	})
+}
+
+// ReportMembersSeen records activity of members observed by the bot
+func (ctrl *MemberController) ReportMembersSeen(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotReportMembersSeenJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		for _, member := range form.Members {
+			_, err := q.RecordGuildMemberSeen(c, db.RecordGuildMemberSeenParams{
+				GuildDiscordID:  uri.DiscordID,
+				MemberDiscordID: member.MemberDiscordID,
+				SeenAt:          member.SeenAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			err := errors.New("guild not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *MemberController) createRevision(c *gin.Context, q *db.Queries, action string, note db.MemberNote, editorDiscordID string) error {
+	_, err := q.CreateMemberNoteRevision(c, db.CreateMemberNoteRevisionParams{
+		NoteID:          note.ID,
+		Action:          action,
+		Content:         note.Content,
+		EditorDiscordID: sql.NullString{String: editorDiscordID, Valid: true},
+	})
+	return err
+}
+
+func (ctrl *MemberController) noteError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+	c.JSON(http.StatusInternalServerError, errorResponse(err))
+}
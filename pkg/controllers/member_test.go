@@ -0,0 +1,471 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func generateRandomMemberNote(guildDiscordID, memberDiscordID string) db.MemberNote {
+	return db.MemberNote{
+		ID:              int64(utils.RandomInt(1, 1000)),
+		GuildDiscordID:  guildDiscordID,
+		MemberDiscordID: memberDiscordID,
+		AuthorDiscordID: sql.NullString{String: utils.RandomSnowflakeID().String(), Valid: true},
+		Content:         utils.RandomString(50),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+}
+
+// newMemberRouter routes member profile endpoints of moderators and member reports of the bot
+func newMemberRouter(store *mockdb.MockStore) *gin.Engine {
+	memberController := NewMemberController(store, services.NewEscalationService(store))
+	router := newAuthorizedRouter()
+	router.GET("/api/v1/guilds/:discord_id/members/:member_id", memberController.GetMemberProfile)
+	router.POST("/api/v1/guilds/:discord_id/members/:member_id/notes", memberController.CreateMemberNote)
+	router.PUT("/api/v1/guilds/:discord_id/members/:member_id/notes/:note_id", memberController.UpdateMemberNote)
+	router.DELETE("/api/v1/guilds/:discord_id/members/:member_id/notes/:note_id", memberController.DeleteMemberNote)
+	router.GET("/api/v1/guilds/:discord_id/members/:member_id/notes/:note_id/revisions", memberController.GetMemberNoteRevisions)
+	router.POST("/api/v1/bot/guilds/:discord_id/members/seen", memberController.ReportMembersSeen)
+	return router
+}
+
+func TestMemberController_GetMemberProfile(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	guildConfig := generateGuildConfig(t, func(config *objects.GuildConfig) {
+		config.Data.Escalation.Enabled = true
+	})
+	memberDiscordID := utils.RandomSnowflakeID().String()
+	member := db.GuildMember{
+		GuildDiscordID:  guild.DiscordID,
+		MemberDiscordID: memberDiscordID,
+		FirstSeenAt:     time.Now().Add(-time.Hour),
+		LastSeenAt:      time.Now(),
+	}
+	moderationCase := generateRandomCase(guild.DiscordID, 1)
+	moderationCase.TargetDiscordID = memberDiscordID
+	note := generateRandomMemberNote(guild.DiscordID, memberDiscordID)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildMember(gomock.Any(), gomock.Eq(db.GetGuildMemberParams{
+						GuildDiscordID:  guild.DiscordID,
+						MemberDiscordID: memberDiscordID,
+					})).
+					Times(1).
+					Return(member, nil)
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Eq(db.GetModerationCasesParams{
+						GuildDiscordID:  guild.DiscordID,
+						TargetDiscordID: memberDiscordID,
+						MaxResults:      defaultCasesLimit,
+					})).
+					Times(1).
+					Return([]db.ModerationCase{moderationCase}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetTargetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ModerationCase{moderationCase}, nil)
+				store.EXPECT().
+					GetMemberNotes(gomock.Any(), gomock.Eq(db.GetMemberNotesParams{
+						GuildDiscordID:  guild.DiscordID,
+						MemberDiscordID: memberDiscordID,
+					})).
+					Times(1).
+					Return([]db.MemberNote{note}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseMemberProfile
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, memberDiscordID, res.MemberDiscordID)
+				require.NotNil(t, res.FirstSeenAt)
+				require.WithinDuration(t, member.FirstSeenAt, *res.FirstSeenAt, time.Second)
+				require.Equal(t, 1, res.Standing)
+				require.Len(t, res.Cases, 1)
+				require.Equal(t, moderationCase.CaseNumber, res.Cases[0].CaseNumber)
+				require.Len(t, res.Notes, 1)
+				require.Equal(t, note.Content, res.Notes[0].Content)
+			},
+		},
+		{
+			name: "OK/NeverSeen",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildMember{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(guildConfig, nil)
+				store.EXPECT().
+					GetTargetModerationCases(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					GetMemberNotes(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseMemberProfile
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Nil(t, res.FirstSeenAt)
+				require.Nil(t, res.LastSeenAt)
+				require.NotNil(t, res.Cases)
+				require.NotNil(t, res.Notes)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetGuildMember",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildMember{}, sql.ErrConnDone)
+				store.EXPECT().
+					GetModerationCases(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/members/%s", guild.DiscordID, memberDiscordID)
+			w := serveAuthorized(t, newMemberRouter(store), utils.RandomSnowflakeID().String(), http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestMemberController_MemberNotes(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	memberDiscordID := utils.RandomSnowflakeID().String()
+	notesURL := fmt.Sprintf("/api/v1/guilds/%s/members/%s/notes", guild.DiscordID, memberDiscordID)
+	noteURL := notesURL + "/42"
+
+	formJSON, err := json.Marshal(forms.MemberNoteJSON{Content: "keeps baiting others in #general"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Create/Created",
+			method: http.MethodPost,
+			url:    notesURL,
+			body:   formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, w.Code)
+			},
+		},
+		{
+			name:   "Create/BadRequest",
+			method: http.MethodPost,
+			url:    notesURL,
+			body:   []byte(`{"content":""}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:   "Update/OK",
+			method: http.MethodPut,
+			url:    noteURL,
+			body:   formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:   "Update/NotFound",
+			method: http.MethodPut,
+			url:    noteURL,
+			body:   formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:   "Update/BadRequestNoteID",
+			method: http.MethodPut,
+			url:    notesURL + "/abc",
+			body:   formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:   "Delete/OK",
+			method: http.MethodDelete,
+			url:    noteURL,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:   "Delete/InternalServerError",
+			method: http.MethodDelete,
+			url:    noteURL,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			w := serveAuthorized(t, newMemberRouter(store), utils.RandomSnowflakeID().String(), tc.method, tc.url, tc.body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestMemberController_GetMemberNoteRevisions(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	memberDiscordID := utils.RandomSnowflakeID().String()
+	note := generateRandomMemberNote(guild.DiscordID, memberDiscordID)
+	note.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	revisions := []db.MemberNoteRevision{
+		{ID: 1, NoteID: note.ID, Action: db.NoteRevisionCreate, Content: "first", EditorDiscordID: note.AuthorDiscordID},
+		{ID: 2, NoteID: note.ID, Action: db.NoteRevisionDelete, Content: "first", EditorDiscordID: note.AuthorDiscordID},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMemberNote(gomock.Any(), gomock.Eq(db.GetMemberNoteParams{
+						ID:              note.ID,
+						GuildDiscordID:  guild.DiscordID,
+						MemberDiscordID: memberDiscordID,
+					})).
+					Times(1).
+					Return(note, nil)
+				store.EXPECT().
+					GetMemberNoteRevisions(gomock.Any(), gomock.Eq(note.ID)).
+					Times(1).
+					Return(revisions, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Note      ResponseMemberNote           `json:"note"`
+					Revisions []ResponseMemberNoteRevision `json:"revisions"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.NotNil(t, res.Note.DeletedAt)
+				require.Len(t, res.Revisions, 2)
+				require.Equal(t, db.NoteRevisionDelete, res.Revisions[1].Action)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMemberNote(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MemberNote{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetMemberNoteRevisions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/members/%s/notes/%d/revisions", guild.DiscordID, memberDiscordID, note.ID)
+			w := serveAuthorized(t, newMemberRouter(store), utils.RandomSnowflakeID().String(), http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestMemberController_ReportMembersSeen(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	formJSON, err := json.Marshal(forms.BotReportMembersSeenJSON{
+		Members: []forms.BotMemberSeenJSON{
+			{MemberDiscordID: utils.RandomSnowflakeID().String(), SeenAt: time.Now()},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "NoContent",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/Empty",
+			body: []byte(`{"members":[]}`),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound/Guild",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/members/seen", guild.DiscordID)
+			w := serveAuthorized(t, newMemberRouter(store), utils.RandomSnowflakeID().String(), http.MethodPost, url, tc.body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
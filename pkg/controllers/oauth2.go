@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
@@ -14,6 +19,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v9"
 	"net/http"
+	"time"
 )
 
 type Oauth2Controller struct {
@@ -40,12 +46,120 @@ func NewOauth2Controller(
 	}
 }
 
+// Oauth2BindingCookie binds the flow to the browser which initiated it, so the state
+// obtained by someone else can not be used to log the victim into attacker's account
+const Oauth2BindingCookie = "oauth2_binding"
+
+// Oauth2PollSecretHeaderKey carries the secret returned to native client which initiated the flow
+const Oauth2PollSecretHeaderKey = "X-Poll-Secret"
+
+const oauth2FlowPollInterval = time.Second
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func secretMatches(secret string, hashedSecret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(hashedSecret)) == 1
+}
+
 func (ctrl *Oauth2Controller) GetNewOauth2URL(c *gin.Context) {
+	var query forms.GetNewOauth2URLQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.RedirectURL == "" && len(ctrl.config.DiscordRedirectURLs) > 0 {
+		query.RedirectURL = ctrl.config.DiscordRedirectURLs[0]
+	}
+	if !ctrl.config.IsAllowedRedirectURL(query.RedirectURL) {
+		err := errors.New("redirect url is not allowed")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	state := utils.RandomString(32)
+	codeVerifier := utils.NewCodeVerifier()
+	flow := memdb.Oauth2Flow{
+		Completed:    false,
+		CodeVerifier: codeVerifier,
+		RedirectURL:  query.RedirectURL,
+		Native:       query.Native,
+	}
+	res := gin.H{
+		"url":   ctrl.discordOauth2Service.NewURL(state, codeVerifier, query.RedirectURL),
+		"state": state,
+	}
 
-	err := ctrl.memStore.SetOauth2Flow(c, state, memdb.Oauth2Flow{
-		Completed:     false,
-		UserDiscordID: 0,
+	// native clients open the URL in a separate browser, so they are bound by poll secret instead of cookie
+	var binding string
+	if query.Native {
+		pollSecret := utils.RandomString(32)
+		flow.PollSecret = hashSecret(pollSecret)
+		res["poll_secret"] = pollSecret
+	} else {
+		binding = utils.RandomString(32)
+		flow.BrowserBinding = hashSecret(binding)
+	}
+
+	err := ctrl.memStore.SetOauth2Flow(c, state, flow, ctrl.config.Oauth2FlowStateDuration)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !query.Native {
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie(Oauth2BindingCookie, binding, int(ctrl.config.Oauth2FlowStateDuration.Seconds()), "/api/v1/oauth2", "", true, true)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (ctrl *Oauth2Controller) GetNewInviteBotURL(c *gin.Context) {
+	var query forms.GetNewInviteBotURLQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	// config reveals enabled modules, so it is read only for guilds the user is related with
+	guildConfigObj := objects.NewDefaultGuildConfig()
+	related := false
+	if query.GuildID != "" {
+		_, err := ctrl.store.GetUserGuildRel(c, db.GetUserGuildRelParams{
+			AccountDiscordID: payload.UserDiscordID,
+			GuildDiscordID:   query.GuildID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		related = err == nil
+	}
+	if related {
+		guildConfig, err := ctrl.store.GetGuildConfig(c, query.GuildID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if err == nil {
+			guildConfigObj, err = objects.DecodeGuildConfig(guildConfig.Json)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+	}
+	permissions := guildConfigObj.RequiredBotPermissions()
+
+	state := utils.RandomString(32)
+	err := ctrl.memStore.SetBotInviteFlow(c, state, memdb.BotInviteFlow{
+		UserDiscordID:  payload.UserDiscordID,
+		GuildDiscordID: query.GuildID,
+		Permissions:    permissions,
 	}, ctrl.config.Oauth2FlowStateDuration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -53,15 +167,61 @@ func (ctrl *Oauth2Controller) GetNewOauth2URL(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"url":   ctrl.discordOauth2Service.NewURL(state),
-		"state": state,
+		"url":         ctrl.discordOauth2Service.NewInviteBotURL(state, query.GuildID, permissions),
+		"state":       state,
+		"permissions": permissions,
 	})
 }
 
-func (ctrl *Oauth2Controller) GetNewInviteBotURL(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"url": ctrl.discordOauth2Service.NewInviteBotURL(),
+func (ctrl *Oauth2Controller) HandleInviteBotCallback(c *gin.Context) {
+	var form forms.InviteBotRedirectForm
+	if err := c.ShouldBindQuery(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	flow, err := ctrl.memStore.GetBotInviteFlow(c, form.State)
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			err := errors.New("state not exists or expired")
+			c.JSON(http.StatusMethodNotAllowed, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err := ctrl.memStore.DeleteBotInviteFlow(c, form.State); err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// exchanging code proves that the authorization was granted by Discord and tells the guild
+	botGuild, err := ctrl.discordOauth2Service.ExchangeInviteBot(form.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if flow.GuildDiscordID != "" && flow.GuildDiscordID != botGuild.ID {
+		err := errors.New("bot was installed in another guild")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	permissions := botGuild.Permissions
+	if permissions == 0 {
+		permissions = flow.Permissions
+	}
+
+	installation, err := ctrl.store.CreateBotInstallation(c, db.CreateBotInstallationParams{
+		GuildDiscordID:     botGuild.ID,
+		InstallerDiscordID: flow.UserDiscordID,
+		Permissions:        permissions,
 	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, installation)
 }
 
 func (ctrl *Oauth2Controller) HandleDiscordCallback(c *gin.Context) {
@@ -71,7 +231,7 @@ func (ctrl *Oauth2Controller) HandleDiscordCallback(c *gin.Context) {
 		return
 	}
 
-	_, err := ctrl.memStore.GetOauth2Flow(c, form.State)
+	flow, err := ctrl.memStore.GetOauth2Flow(c, form.State)
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			err := errors.New("state not exists or expired")
@@ -81,9 +241,57 @@ func (ctrl *Oauth2Controller) HandleDiscordCallback(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if flow.Native {
+		if flow.Completed {
+			err := errors.New("state was already used")
+			c.JSON(http.StatusMethodNotAllowed, errorResponse(err))
+			return
+		}
+		// state is claimed before the code exchange, so only one of concurrent callbacks completes the flow
+		if err := ctrl.memStore.ClaimOauth2Flow(c, form.State, ctrl.config.Oauth2FlowStateDuration); err != nil {
+			if errors.Is(err, redis.Nil) {
+				err := errors.New("state was already used")
+				c.JSON(http.StatusMethodNotAllowed, errorResponse(err))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	} else {
+		// state is consumed before the code exchange, so only one of concurrent callbacks succeeds
+		if err := ctrl.memStore.DeleteOauth2Flow(c, form.State); err != nil {
+			if errors.Is(err, redis.Nil) {
+				err := errors.New("state not exists or expired")
+				c.JSON(http.StatusMethodNotAllowed, errorResponse(err))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		binding, _ := c.Cookie(Oauth2BindingCookie)
+		if !secretMatches(binding, flow.BrowserBinding) {
+			err := errors.New("state was issued to another browser")
+			c.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		c.SetCookie(Oauth2BindingCookie, "", -1, "/api/v1/oauth2", "", true, true)
+	}
+
+	// allowlist could change while the flow was in progress
+	if !ctrl.config.IsAllowedRedirectURL(flow.RedirectURL) {
+		err := errors.New("redirect url is not allowed")
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if origin := c.GetHeader("Origin"); origin != "" && origin != utils.URLOrigin(flow.RedirectURL) {
+		err := errors.New("callback origin does not match redirect url")
+		c.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
 
 	// obtaining user data using Discord oauth2 API
-	dToken, err := ctrl.discordOauth2Service.Exchange(form.Code)
+	dToken, err := ctrl.discordOauth2Service.Exchange(form.Code, flow.CodeVerifier, flow.RedirectURL)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse(err))
 		return
@@ -99,7 +307,7 @@ func (ctrl *Oauth2Controller) HandleDiscordCallback(c *gin.Context) {
 		return
 	}
 
-	defaultGuildConfigObj := objects.DefaultGuildConfig
+	defaultGuildConfigObj := objects.NewDefaultGuildConfig()
 	defaultGuildConfigJSON, _ := json.Marshal(defaultGuildConfigObj)
 
 	// create user and his relations form obtained oauth2 data
@@ -118,17 +326,22 @@ func (ctrl *Oauth2Controller) HandleDiscordCallback(c *gin.Context) {
 			return err
 		}
 
+		guildDiscordIDs := make([]string, 0, len(dGuilds))
 		for _, dGuild := range dGuilds {
+			guildDiscordIDs = append(guildDiscordIDs, dGuild.ID)
+
+			// empty owner keeps the recorded one, since guild list of a member does not tell who the owner is
 			var ownerDiscordID = ""
 			if dGuild.IsOwner {
 				ownerDiscordID = dUser.ID
-				dGuild.Permissions = 0xfffffffffff
 			}
+			permissions := discordperm.Effective(discordperm.Permissions(dGuild.Permissions), dGuild.IsOwner)
 			_, err = q.CreateOrUpdateGuild(c, db.CreateOrUpdateGuildParams{
-				DiscordID:      dGuild.ID,
-				Name:           dGuild.Name,
-				Icon:           dGuild.Icon,
-				OwnerDiscordID: ownerDiscordID,
+				DiscordID:       dGuild.ID,
+				Name:            dGuild.Name,
+				Icon:            dGuild.Icon,
+				OwnerDiscordID:  ownerDiscordID,
+				MemberDiscordID: dUser.ID,
 			})
 			if err != nil {
 				return err
@@ -145,48 +358,112 @@ func (ctrl *Oauth2Controller) HandleDiscordCallback(c *gin.Context) {
 			_, err = q.CreateOrUpdateUserGuildRel(c, db.CreateOrUpdateUserGuildRelParams{
 				AccountDiscordID: dUser.ID,
 				GuildDiscordID:   dGuild.ID,
-				Permissions:      dGuild.Permissions,
+				Permissions:      int64(permissions),
 			})
 			if err != nil {
 				return err
 			}
 		}
-		return nil
+
+		// relations with guilds the user has left
+		return q.DeleteStaleUserGuildRels(c, db.DeleteStaleUserGuildRelsParams{
+			AccountDiscordID: dUser.ID,
+			GuildDiscordIds:  guildDiscordIDs,
+		})
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	accessToken, _, err := ctrl.tokenMaker.CreateToken(dUser.ID, ctrl.config.AccessTokenDuration)
+	if flow.Native {
+		flow.Completed = true
+		flow.UserDiscordID = dUser.ID
+		if err := ctrl.memStore.SetOauth2Flow(c, form.State, flow, ctrl.config.Oauth2FlowStateDuration); err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"completed": true})
+		return
+	}
+
+	session, accessToken, err := createSession(c, ctrl.memStore, ctrl.config, ctrl.tokenMaker, dUser.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
-	refreshToken, refreshPayload, err := ctrl.tokenMaker.CreateToken(dUser.ID, ctrl.config.RefreshTokenDuration)
-	if err != nil {
+
+	writeSession(c, ctrl.config, session, accessToken, ctrl.config.AuthCookieMode)
+}
+
+// PollOauth2Flow lets native client collect tokens once the flow was completed in the browser.
+// If ?wait is set, the request is held up to that many seconds until the flow completes
+func (ctrl *Oauth2Controller) PollOauth2Flow(c *gin.Context) {
+	var req forms.PollOauth2FlowRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var query forms.PollOauth2FlowQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	pollSecret := c.GetHeader(Oauth2PollSecretHeaderKey)
+	deadline := time.Now().Add(time.Duration(query.Wait) * time.Second)
+	for {
+		flow, err := ctrl.memStore.GetOauth2Flow(c, req.State)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				err := errors.New("flow not exists or expired")
+				c.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if !flow.Native || !secretMatches(pollSecret, flow.PollSecret) {
+			err := errors.New("invalid poll secret")
+			c.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		if flow.Completed {
+			ctrl.collectOauth2Flow(c, req.State, flow)
+			return
+		}
+
+		if !time.Now().Before(deadline) {
+			c.JSON(http.StatusAccepted, gin.H{"completed": false})
+			return
+		}
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-time.After(oauth2FlowPollInterval):
+		}
+	}
+}
+
+func (ctrl *Oauth2Controller) collectOauth2Flow(c *gin.Context, state string, flow memdb.Oauth2Flow) {
+	// the grant is one-time, concurrent polls can not both obtain a session
+	if err := ctrl.memStore.DeleteOauth2Flow(c, state); err != nil {
+		if errors.Is(err, redis.Nil) {
+			err := errors.New("flow not exists or expired")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	session, err := ctrl.memStore.SetSession(c, memdb.Session{
-		ID:           refreshPayload.ID,
-		DiscordID:    refreshPayload.UserDiscordID,
-		RefreshToken: refreshToken,
-		UserAgent:    c.Request.UserAgent(),
-		ClientIp:     c.ClientIP(),
-		IsBlocked:    false,
-	}, ctrl.config.RefreshTokenDuration)
+	session, accessToken, err := createSession(c, ctrl.memStore, ctrl.config, ctrl.tokenMaker, flow.UserDiscordID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"session_id":       session.ID,
-		"access_token":     accessToken,
-		"access_duration":  ctrl.config.AccessTokenDuration.Milliseconds(),
-		"refresh_token":    refreshToken,
-		"refresh_duration": ctrl.config.RefreshTokenDuration.Milliseconds(),
-	})
+	writeSession(c, ctrl.config, session, accessToken, false)
 }
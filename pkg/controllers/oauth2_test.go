@@ -0,0 +1,741 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/ravener/discord-oauth2"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestDiscordOauth2Service() *services.DiscordOauth2Service {
+	return services.NewDiscordOauth2Service(&oauth2.Config{
+		Endpoint:    discord.Endpoint,
+		Scopes:      []string{discord.ScopeIdentify},
+		RedirectURL: "http://localhost/oauth2/discord_callback",
+		ClientID:    "client_id",
+	}, "http://localhost/oauth2/invite_bot_callback")
+}
+
+func TestOauth2Controller_GetNewInviteBotURL(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	config := utils.Config{Oauth2FlowStateDuration: time.Hour}
+	user := generateRandomUser()
+	guild := generateRandomGuild()
+	guildConfigJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		guildID       string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			guildID: guild.DiscordID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Eq(db.GetUserGuildRelParams{
+						AccountDiscordID: user.DiscordID,
+						GuildDiscordID:   guild.DiscordID,
+					})).
+					Times(1).
+					Return(db.UserGuild{}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{ID: guild.ID, Json: guildConfigJSON}, nil)
+				memStore.EXPECT().
+					SetBotInviteFlow(gomock.Any(), gomock.Any(), gomock.Eq(memdb.BotInviteFlow{
+						UserDiscordID:  user.DiscordID,
+						GuildDiscordID: guild.DiscordID,
+						Permissions:    objects.NewDefaultGuildConfig().RequiredBotPermissions(),
+					}), gomock.Eq(config.Oauth2FlowStateDuration)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var body struct {
+					URL   string `json:"url"`
+					State string `json:"state"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				inviteURL, err := url.Parse(body.URL)
+				require.NoError(t, err)
+				require.Equal(t, guild.DiscordID, inviteURL.Query().Get("guild_id"))
+				require.Equal(t, "true", inviteURL.Query().Get("disable_guild_select"))
+				require.Equal(t, body.State, inviteURL.Query().Get("state"))
+				require.Equal(t,
+					strconv.FormatInt(objects.NewDefaultGuildConfig().RequiredBotPermissions(), 10),
+					inviteURL.Query().Get("permissions"))
+			},
+		},
+		{
+			name:    "OK/NoGuild",
+			guildID: "",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					SetBotInviteFlow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.NotContains(t, w.Body.String(), "disable_guild_select")
+			},
+		},
+		{
+			name:    "OK/NoGuildRelation",
+			guildID: guild.DiscordID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					SetBotInviteFlow(gomock.Any(), gomock.Any(), gomock.Eq(memdb.BotInviteFlow{
+						UserDiscordID:  user.DiscordID,
+						GuildDiscordID: guild.DiscordID,
+						Permissions:    objects.NewDefaultGuildConfig().RequiredBotPermissions(),
+					}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:    "InternalServerError/DBGetUserGuildRel",
+			guildID: guild.DiscordID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrConnDone)
+				memStore.EXPECT().
+					SetBotInviteFlow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			name:    "InternalServerError/DBGetGuildConfig",
+			guildID: guild.DiscordID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrConnDone)
+				memStore.EXPECT().
+					SetBotInviteFlow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+			authMiddleware := middlewares.NewAuthMiddleware(tokenMaker)
+			oauth2Controller := NewOauth2Controller(store, memStore, config, tokenMaker, newTestDiscordOauth2Service())
+			router := gin.New()
+			router.GET("/api/v1/oauth2/new_invite_bot_url", authMiddleware, oauth2Controller.GetNewInviteBotURL)
+
+			url := fmt.Sprintf("/api/v1/oauth2/new_invite_bot_url?guild_id=%s", tc.guildID)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			accessToken, _, err := tokenMaker.CreateToken(user.DiscordID, time.Minute)
+			require.NoError(t, err)
+
+			authHeader := fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, authHeader)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestOauth2Controller_GetNewOauth2URL(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	config := utils.Config{
+		Oauth2FlowStateDuration: time.Hour,
+		DiscordRedirectURLs: []string{
+			"https://sentinel.example/oauth2/discord_callback",
+			"https://staging.sentinel.example/oauth2/discord_callback",
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		redirectURL   string
+		native        bool
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder, flow memdb.Oauth2Flow)
+	}{
+		{
+			name:        "OK/DefaultRedirectURL",
+			redirectURL: "",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder, flow memdb.Oauth2Flow) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var body struct {
+					URL   string `json:"url"`
+					State string `json:"state"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				authURL, err := url.Parse(body.URL)
+				require.NoError(t, err)
+				require.Equal(t, body.State, authURL.Query().Get("state"))
+				require.Equal(t, config.DiscordRedirectURLs[0], authURL.Query().Get("redirect_uri"))
+				require.Equal(t, config.DiscordRedirectURLs[0], flow.RedirectURL)
+				require.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+				require.Equal(t, utils.CodeChallengeS256(flow.CodeVerifier), authURL.Query().Get("code_challenge"))
+				require.NotContains(t, body.URL, flow.CodeVerifier)
+
+				cookies := w.Result().Cookies()
+				require.Len(t, cookies, 1)
+				require.Equal(t, Oauth2BindingCookie, cookies[0].Name)
+				require.True(t, cookies[0].HttpOnly)
+				require.True(t, cookies[0].Secure)
+				require.Equal(t, hashSecret(cookies[0].Value), flow.BrowserBinding)
+			},
+		},
+		{
+			name:        "OK/ChosenRedirectURL",
+			redirectURL: config.DiscordRedirectURLs[1],
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder, flow memdb.Oauth2Flow) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, config.DiscordRedirectURLs[1], flow.RedirectURL)
+				require.Contains(t, w.Body.String(), url.QueryEscape(config.DiscordRedirectURLs[1]))
+			},
+		},
+		{
+			name:        "OK/Native",
+			redirectURL: "",
+			native:      true,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder, flow memdb.Oauth2Flow) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Empty(t, w.Result().Cookies())
+
+				var body struct {
+					PollSecret string `json:"poll_secret"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				require.True(t, flow.Native)
+				require.Empty(t, flow.BrowserBinding)
+				require.Equal(t, hashSecret(body.PollSecret), flow.PollSecret)
+			},
+		},
+		{
+			name:        "BadRequest/RedirectURLNotAllowed",
+			redirectURL: "https://evil.example/oauth2/discord_callback",
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder, flow memdb.Oauth2Flow) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+				require.Empty(t, flow)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var flow memdb.Oauth2Flow
+			memStore := mockmemdb.NewMockStore(ctrl)
+			memStore.EXPECT().
+				SetOauth2Flow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(config.Oauth2FlowStateDuration)).
+				AnyTimes().
+				DoAndReturn(func(_ interface{}, _ string, f memdb.Oauth2Flow, _ time.Duration) error {
+					flow = f
+					return nil
+				})
+
+			oauth2Controller := NewOauth2Controller(nil, memStore, config, nil, newTestDiscordOauth2Service())
+			router := gin.New()
+			router.GET("/api/v1/oauth2/new_url", oauth2Controller.GetNewOauth2URL)
+
+			reqURL := fmt.Sprintf("/api/v1/oauth2/new_url?redirect_url=%s&native=%t", url.QueryEscape(tc.redirectURL), tc.native)
+			req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w, flow)
+		})
+	}
+}
+
+func TestOauth2Controller_HandleDiscordCallback(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	config := utils.Config{
+		Oauth2FlowStateDuration: time.Hour,
+		DiscordRedirectURLs:     []string{"https://sentinel.example/oauth2/discord_callback"},
+	}
+	state := utils.RandomString(32)
+	binding := utils.RandomString(32)
+	flow := memdb.Oauth2Flow{
+		CodeVerifier:   utils.NewCodeVerifier(),
+		RedirectURL:    config.DiscordRedirectURLs[0],
+		BrowserBinding: hashSecret(binding),
+	}
+	nativeFlow := memdb.Oauth2Flow{
+		CodeVerifier: utils.NewCodeVerifier(),
+		RedirectURL:  config.DiscordRedirectURLs[0],
+		Native:       true,
+	}
+
+	testCases := []struct {
+		name          string
+		binding       string
+		origin        string
+		buildStubs    func(memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "StateNotExists",
+			binding: binding,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(memdb.Oauth2Flow{}, redis.Nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+			},
+		},
+		{
+			name:    "NativeStateAlreadyCompleted",
+			binding: "",
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(memdb.Oauth2Flow{Native: true, Completed: true}, nil)
+				memStore.EXPECT().
+					ClaimOauth2Flow(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+			},
+		},
+		{
+			name:    "NativeStateAlreadyClaimed",
+			binding: "",
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nativeFlow, nil)
+				memStore.EXPECT().
+					ClaimOauth2Flow(gomock.Any(), gomock.Eq(state), gomock.Eq(config.Oauth2FlowStateDuration)).
+					Times(1).
+					Return(redis.Nil)
+				memStore.EXPECT().
+					SetOauth2Flow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+			},
+		},
+		{
+			name:    "InternalServerError/ClaimOauth2Flow",
+			binding: "",
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nativeFlow, nil)
+				memStore.EXPECT().
+					ClaimOauth2Flow(gomock.Any(), gomock.Eq(state), gomock.Eq(config.Oauth2FlowStateDuration)).
+					Times(1).
+					Return(redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			name:    "StateAlreadyConsumed",
+			binding: binding,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(flow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(redis.Nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+			},
+		},
+		{
+			name:    "Forbidden/BindingMismatch",
+			binding: utils.RandomString(32),
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(flow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:    "Forbidden/BindingMissing",
+			binding: "",
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(flow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:    "BadRequest/RedirectURLRemoved",
+			binding: binding,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				removedFlow := flow
+				removedFlow.RedirectURL = "https://old.sentinel.example/oauth2/discord_callback"
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(removedFlow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:    "Forbidden/OriginMismatch",
+			binding: binding,
+			origin:  "https://staging.sentinel.example",
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(flow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:    "InternalServerError/DeleteOauth2Flow",
+			binding: binding,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(flow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(memStore)
+
+			oauth2Controller := NewOauth2Controller(nil, memStore, config, nil, newTestDiscordOauth2Service())
+			router := gin.New()
+			router.GET("/api/v1/oauth2/discord_callback", oauth2Controller.HandleDiscordCallback)
+
+			url := fmt.Sprintf("/api/v1/oauth2/discord_callback?code=%s&state=%s", "code", state)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			if tc.binding != "" {
+				req.AddCookie(&http.Cookie{Name: Oauth2BindingCookie, Value: tc.binding})
+			}
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestOauth2Controller_PollOauth2Flow(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	config := utils.Config{
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 2 * time.Hour,
+		AuthCookieMode:       true,
+	}
+	user := generateRandomUser()
+	state := utils.RandomString(32)
+	pollSecret := utils.RandomString(32)
+	pendingFlow := memdb.Oauth2Flow{
+		Native:     true,
+		PollSecret: hashSecret(pollSecret),
+	}
+	completedFlow := pendingFlow
+	completedFlow.Completed = true
+	completedFlow.UserDiscordID = user.DiscordID
+
+	testCases := []struct {
+		name          string
+		pollSecret    string
+		wait          int
+		buildStubs    func(memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			pollSecret: pollSecret,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(completedFlow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					SetSession(gomock.Any(), gomock.Any(), gomock.Eq(config.RefreshTokenDuration)).
+					Times(1).
+					DoAndReturn(func(_ interface{}, s memdb.Session, _ time.Duration) (memdb.Session, error) {
+						require.Equal(t, user.DiscordID, s.DiscordID)
+						return s, nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				// native clients always receive refresh token in the body
+				require.Contains(t, w.Body.String(), "refresh_token")
+				require.Empty(t, w.Result().Cookies())
+			},
+		},
+		{
+			name:       "OK/LongPoll",
+			pollSecret: pollSecret,
+			wait:       5,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				gomock.InOrder(
+					memStore.EXPECT().
+						GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+						Times(1).
+						Return(pendingFlow, nil),
+					memStore.EXPECT().
+						GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+						Times(1).
+						Return(completedFlow, nil),
+				)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					SetSession(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, s memdb.Session, _ time.Duration) (memdb.Session, error) {
+						return s, nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:       "Accepted/Pending",
+			pollSecret: pollSecret,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(pendingFlow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, w.Code)
+			},
+		},
+		{
+			name:       "Forbidden/InvalidPollSecret",
+			pollSecret: utils.RandomString(32),
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(completedFlow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:       "Forbidden/BrowserFlow",
+			pollSecret: "",
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(memdb.Oauth2Flow{Completed: true, UserDiscordID: user.DiscordID}, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			pollSecret: pollSecret,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(memdb.Oauth2Flow{}, redis.Nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:       "NotFound/AlreadyCollected",
+			pollSecret: pollSecret,
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					GetOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(completedFlow, nil)
+				memStore.EXPECT().
+					DeleteOauth2Flow(gomock.Any(), gomock.Eq(state)).
+					Times(1).
+					Return(redis.Nil)
+				memStore.EXPECT().
+					SetSession(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(memStore)
+
+			tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+			oauth2Controller := NewOauth2Controller(nil, memStore, config, tokenMaker, newTestDiscordOauth2Service())
+			router := gin.New()
+			router.GET("/api/v1/oauth2/flows/:state", oauth2Controller.PollOauth2Flow)
+
+			url := fmt.Sprintf("/api/v1/oauth2/flows/%s?wait=%d", state, tc.wait)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			req.Header.Set(Oauth2PollSecretHeaderKey, tc.pollSecret)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/raid"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/verification"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRaidIncidentsLimit = 25
+	// raidIncidentQuietPeriod is how long an open incident goes without detections before a new one is raised
+	raidIncidentQuietPeriod = 30 * time.Minute
+
+	defaultTimelineRange  = time.Hour
+	defaultTimelineBucket = time.Minute
+	maxTimelineRange      = services.MemberEventRetention
+	maxTimelineBuckets    = 1000
+)
+
+var (
+	errIncidentResolved   = errors.New("raid incident has already been resolved")
+	errConcurrentIncident = errors.New("raid incident was raised concurrently")
+	errTimelineRange      = errors.New("since must be before until")
+	errTimelineTooLarge   = errors.New("timeline range is too large")
+)
+
+type RaidController struct {
+	store           db.Store
+	memStore        memdb.Store
+	lockdownService *services.LockdownService
+}
+
+func NewRaidController(store db.Store, memStore memdb.Store, lockdownService *services.LockdownService) *RaidController {
+	return &RaidController{
+		store:           store,
+		memStore:        memStore,
+		lockdownService: lockdownService,
+	}
+}
+
+type ResponseRaidIncident struct {
+	ID                int64          `json:"id"`
+	GuildDiscordID    string         `json:"guild_discord_id"`
+	Status            string         `json:"status"`
+	Detection         raid.Detection `json:"detection"`
+	LockdownRequested bool           `json:"lockdown_requested"`
+	StartedAt         time.Time      `json:"started_at"`
+	LastDetectedAt    time.Time      `json:"last_detected_at"`
+	// ResolvedByDiscordID is null when the incident was closed after going quiet or the resolver deleted the account
+	ResolvedByDiscordID *string    `json:"resolved_by_discord_id"`
+	ResolvedAt          *time.Time `json:"resolved_at"`
+}
+
+func newResponseRaidIncident(incident db.RaidIncident) ResponseRaidIncident {
+	var detection raid.Detection
+	_ = json.Unmarshal(incident.Detection, &detection)
+
+	var resolvedBy *string
+	if incident.ResolvedByDiscordID.Valid {
+		resolvedBy = &incident.ResolvedByDiscordID.String
+	}
+	return ResponseRaidIncident{
+		ID:                  incident.ID,
+		GuildDiscordID:      incident.GuildDiscordID,
+		Status:              incident.Status,
+		Detection:           detection,
+		LockdownRequested:   incident.LockdownRequested,
+		StartedAt:           incident.StartedAt,
+		LastDetectedAt:      incident.LastDetectedAt,
+		ResolvedByDiscordID: resolvedBy,
+		ResolvedAt:          nullTimeToPtr(incident.ResolvedAt),
+	}
+}
+
+// ReportMemberEvents records joins and leaves observed by the bot and checks recent joins against raid thresholds.
+// Detected raids open an incident, or update the open one, and new incidents may lock the guild down.
+func (ctrl *RaidController) ReportMemberEvents(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.BotReportMemberEventsJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	params := make([]db.CreateMemberEventParams, 0, len(form.Events))
+	for _, event := range form.Events {
+		accountCreatedAt, err := verification.AccountCreatedAt(event.MemberDiscordID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		params = append(params, db.CreateMemberEventParams{
+			GuildDiscordID:   uri.DiscordID,
+			Type:             event.Type,
+			MemberDiscordID:  event.MemberDiscordID,
+			Username:         event.Username,
+			AccountCreatedAt: accountCreatedAt,
+			OccurredAt:       event.OccurredAt,
+		})
+	}
+
+	config, err := ctrl.raidConfig(c, uri.DiscordID)
+	if err != nil {
+		ctrl.raidError(c, err)
+		return
+	}
+
+	err = ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		for _, p := range params {
+			if err := q.CreateMemberEvent(c, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			err := errors.New("guild not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := gin.H{
+		"detection": nil,
+		"incident":  nil,
+	}
+	if !config.Enabled {
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	now := time.Now()
+	events, err := ctrl.store.GetGuildMemberEvents(c, db.GetGuildMemberEventsParams{
+		GuildDiscordID: uri.DiscordID,
+		Since:          raid.Window(config, now),
+		Until:          now,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	detection := raid.Detect(config, toRaidEvents(events), now)
+	res["detection"] = detection
+	if !detection.Raid() {
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	incident, created, err := ctrl.raiseIncident(c, uri.DiscordID, config, detection, now)
+	if errors.Is(err, errConcurrentIncident) {
+		// another report has just raised the incident, this detection joins it
+		incident, created, err = ctrl.raiseIncident(c, uri.DiscordID, config, detection, now)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var lockdown *db.GuildLockdown
+	if created && incident.LockdownRequested {
+		lockdown, err = ctrl.lockdown(c, incident, detection)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	incidentRes := newResponseRaidIncident(incident)
+	ctrl.publishRaidEvent(c, memdb.GuildEventRaidDetected, uri.DiscordID, incidentRes)
+	if lockdown != nil {
+		ctrl.lockdownService.Publish(c, *lockdown)
+	}
+	res["incident"] = incidentRes
+	c.JSON(http.StatusOK, res)
+}
+
+// GetRaidTimeline counts joins and leaves of the guild in buckets, by default the last hour in minute buckets
+func (ctrl *RaidController) GetRaidTimeline(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	_ = c.ShouldBindUri(&uri)
+	var query forms.GetRaidTimelineQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	until := query.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since := query.Since
+	if since.IsZero() {
+		since = until.Add(-defaultTimelineRange)
+	}
+	bucket := defaultTimelineBucket
+	if query.BucketSeconds > 0 {
+		bucket = time.Duration(query.BucketSeconds) * time.Second
+	}
+	if !since.Before(until) {
+		c.JSON(http.StatusBadRequest, errorResponse(errTimelineRange))
+		return
+	}
+	if until.Sub(since) > maxTimelineRange || until.Sub(since)/bucket >= maxTimelineBuckets {
+		c.JSON(http.StatusBadRequest, errorResponse(errTimelineTooLarge))
+		return
+	}
+
+	config, err := ctrl.raidConfig(c, uri.DiscordID)
+	if err != nil {
+		ctrl.raidError(c, err)
+		return
+	}
+
+	events, err := ctrl.store.GetGuildMemberEvents(c, db.GetGuildMemberEventsParams{
+		GuildDiscordID: uri.DiscordID,
+		Since:          since,
+		Until:          until,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"since":          since,
+		"until":          until,
+		"bucket_seconds": int(bucket / time.Second),
+		"buckets":        raid.Timeline(config, toRaidEvents(events), since, until, bucket),
+	})
+}
+
+// GetRaidIncidents lists raid incidents of the guild from the newest, next page starts before the id returned in next_before
+func (ctrl *RaidController) GetRaidIncidents(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	_ = c.ShouldBindUri(&uri)
+	var query forms.GetRaidIncidentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultRaidIncidentsLimit
+	}
+
+	incidents, err := ctrl.store.GetRaidIncidents(c, db.GetRaidIncidentsParams{
+		GuildDiscordID: uri.DiscordID,
+		BeforeID:       query.Before,
+		MaxResults:     query.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := make([]ResponseRaidIncident, 0, len(incidents))
+	for _, incident := range incidents {
+		res = append(res, newResponseRaidIncident(incident))
+	}
+
+	var nextBefore *int64
+	if len(res) == int(query.Limit) {
+		nextBefore = &res[len(res)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"incidents":   res,
+		"next_before": nextBefore,
+	})
+}
+
+// ResolveRaidIncident closes the open incident on behalf of the moderator
+func (ctrl *RaidController) ResolveRaidIncident(c *gin.Context) {
+	var uri forms.RaidIncidentURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var resolved db.RaidIncident
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		var err error
+		resolved, err = q.ResolveRaidIncident(c, db.ResolveRaidIncidentParams{
+			ID:                  uri.IncidentID,
+			GuildDiscordID:      uri.DiscordID,
+			ResolvedByDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// tell a missing incident apart from an already resolved one
+			if _, err := q.GetRaidIncident(c, db.GetRaidIncidentParams{ID: uri.IncidentID, GuildDiscordID: uri.DiscordID}); err != nil {
+				return err
+			}
+			return errIncidentResolved
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(newResponseRaidIncident(resolved))
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			ActorDiscordID: sql.NullString{String: payload.UserDiscordID, Valid: true},
+			GuildDiscordID: uri.DiscordID,
+			Action:         db.AuditActionRaidResolve,
+			Data:           data,
+		})
+		return err
+	})
+	if err != nil {
+		ctrl.raidError(c, err)
+		return
+	}
+
+	res := newResponseRaidIncident(resolved)
+	ctrl.publishRaidEvent(c, memdb.GuildEventRaidResolved, uri.DiscordID, res)
+	c.JSON(http.StatusOK, res)
+}
+
+// raiseIncident records the detection in the open incident of the guild. Incidents which went quiet are closed
+// without a resolver and replaced, created reports whether the returned incident is new.
+func (ctrl *RaidController) raiseIncident(c *gin.Context, guildDiscordID string, config objects.RaidConfig, detection raid.Detection, now time.Time) (db.RaidIncident, bool, error) {
+	data, err := json.Marshal(detection)
+	if err != nil {
+		return db.RaidIncident{}, false, err
+	}
+
+	open, err := ctrl.store.GetOpenRaidIncident(c, guildDiscordID)
+	switch {
+	case err == nil && now.Sub(open.LastDetectedAt) <= raidIncidentQuietPeriod:
+		incident, err := ctrl.store.UpdateRaidIncidentDetection(c, db.UpdateRaidIncidentDetectionParams{
+			ID:        open.ID,
+			Detection: data,
+		})
+		return incident, false, err
+	case err == nil:
+		_, err := ctrl.store.ResolveRaidIncident(c, db.ResolveRaidIncidentParams{
+			ID:             open.ID,
+			GuildDiscordID: guildDiscordID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return db.RaidIncident{}, false, err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return db.RaidIncident{}, false, err
+	}
+
+	incident, err := ctrl.store.CreateRaidIncident(c, db.CreateRaidIncidentParams{
+		GuildDiscordID:    guildDiscordID,
+		Detection:         data,
+		LockdownRequested: config.Lockdown,
+	})
+	if db.IsUniqueViolation(err) {
+		return db.RaidIncident{}, false, errConcurrentIncident
+	}
+	return incident, err == nil, err
+}
+
+func (ctrl *RaidController) raidConfig(c *gin.Context, guildDiscordID string) (objects.RaidConfig, error) {
+	guildConfig, err := ctrl.store.GetGuildConfig(c, guildDiscordID)
+	if err != nil {
+		return objects.RaidConfig{}, err
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return objects.RaidConfig{}, err
+	}
+	return guildConfigObj.Data.Raid, nil
+}
+
+// lockdown locks the guild down on behalf of the new incident, a lockdown already in place is kept
+func (ctrl *RaidController) lockdown(c *gin.Context, incident db.RaidIncident, detection raid.Detection) (*db.GuildLockdown, error) {
+	var lockdown *db.GuildLockdown
+	err := ctrl.store.ExecTx(c, func(q *db.Queries) error {
+		created, err := q.CreateIncidentGuildLockdown(c, db.CreateIncidentGuildLockdownParams{
+			GuildDiscordID: incident.GuildDiscordID,
+			Reason:         fmt.Sprintf("Raid incident #%d: %s", incident.ID, strings.Join(detection.Triggers, ", ")),
+			IncidentID:     sql.NullInt64{Int64: incident.ID, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(services.NewLockdown(created))
+		if err != nil {
+			return err
+		}
+		_, err = q.CreateAuditLog(c, db.CreateAuditLogParams{
+			GuildDiscordID: incident.GuildDiscordID,
+			Action:         db.AuditActionGuildLockdown,
+			Data:           data,
+		})
+		lockdown = &created
+		return err
+	})
+	return lockdown, err
+}
+
+// publishRaidEvent shows the incident to subscribers who can read cases, like the raid endpoints do,
+// detections list members who joined the guild
+func (ctrl *RaidController) publishRaidEvent(c *gin.Context, eventType, guildDiscordID string, res interface{}) {
+	data, _ := json.Marshal(res)
+	err := ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           eventType,
+		GuildDiscordID: guildDiscordID,
+		Capability:     token.CapabilityCasesRead,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish raid event: %v", err.Error())
+	}
+}
+
+func (ctrl *RaidController) raidError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, errIncidentResolved):
+		c.JSON(http.StatusConflict, errorResponse(err))
+	default:
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+func toRaidEvents(events []db.MemberEvent) []raid.Event {
+	res := make([]raid.Event, 0, len(events))
+	for _, e := range events {
+		res = append(res, raid.Event{
+			Type:             e.Type,
+			MemberDiscordID:  e.MemberDiscordID,
+			Username:         e.Username,
+			AccountCreatedAt: e.AccountCreatedAt,
+			OccurredAt:       e.OccurredAt,
+		})
+	}
+	return res
+}
@@ -0,0 +1,622 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/raid"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setRaid switches raid detection which locks the guild down after three joins
+func setRaid(enabled bool) func(config *objects.GuildConfig) {
+	return func(config *objects.GuildConfig) {
+		config.Data.Raid.Enabled = enabled
+		config.Data.Raid.JoinBurst = 3
+		config.Data.Raid.Lockdown = true
+	}
+}
+
+func generateMemberEvents(guildDiscordID string, joins int) []db.MemberEvent {
+	events := make([]db.MemberEvent, 0, joins)
+	for i := 0; i < joins; i++ {
+		events = append(events, db.MemberEvent{
+			ID:               int64(i + 1),
+			GuildDiscordID:   guildDiscordID,
+			Type:             raid.EventJoin,
+			MemberDiscordID:  utils.RandomSnowflakeID().String(),
+			Username:         utils.RandomString(8),
+			AccountCreatedAt: time.Now().AddDate(-1, 0, 0),
+			OccurredAt:       time.Now().Add(-time.Second),
+		})
+	}
+	return events
+}
+
+func generateRaidIncident(guildDiscordID string, lastDetectedAt time.Time) db.RaidIncident {
+	return db.RaidIncident{
+		ID:                int64(utils.RandomInt(1, 1000)),
+		GuildDiscordID:    guildDiscordID,
+		Status:            db.RaidIncidentStatusOpen,
+		Detection:         []byte(`{"triggers":["join_burst"],"joins":3}`),
+		LockdownRequested: true,
+		StartedAt:         lastDetectedAt,
+		LastDetectedAt:    lastDetectedAt,
+	}
+}
+
+// newRaidRouter routes raid endpoints of moderators
+func newRaidRouter(store *mockdb.MockStore, memStore *mockmemdb.MockStore) *gin.Engine {
+	raidController := NewRaidController(store, memStore, services.NewLockdownService(store, memStore))
+	router := newAuthorizedRouter()
+	router.GET("/api/v1/guilds/:discord_id/raids/timeline", raidController.GetRaidTimeline)
+	router.POST("/api/v1/guilds/:discord_id/raids/:incident_id/resolve", raidController.ResolveRaidIncident)
+	return router
+}
+
+func TestRaidController_ReportMemberEvents(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	form := forms.BotReportMemberEventsJSON{
+		Events: []forms.BotMemberEventJSON{
+			{
+				Type:            raid.EventJoin,
+				MemberDiscordID: utils.RandomSnowflakeID().String(),
+				Username:        "raider",
+				OccurredAt:      time.Now(),
+			},
+		},
+	}
+	quietIncident := generateRaidIncident(guild.DiscordID, time.Now().Add(-time.Hour))
+	openIncident := generateRaidIncident(guild.DiscordID, time.Now().Add(-time.Minute))
+	newIncident := generateRaidIncident(guild.DiscordID, time.Now())
+
+	testCases := []struct {
+		name          string
+		form          interface{}
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/Disabled",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(false)), nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.JSONEq(t, `{"detection":null,"incident":null}`, w.Body.String())
+			},
+		},
+		{
+			name: "OK/NoRaid",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 2), nil)
+				store.EXPECT().
+					GetOpenRaidIncident(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Detection raid.Detection        `json:"detection"`
+					Incident  *ResponseRaidIncident `json:"incident"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, 2, res.Detection.Joins)
+				require.Empty(t, res.Detection.Triggers)
+				require.Nil(t, res.Incident)
+			},
+		},
+		{
+			name: "OK/NewIncident",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				// events are recorded first, the lockdown of the new incident follows
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 3), nil)
+				store.EXPECT().
+					GetOpenRaidIncident(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.RaidIncident{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateRaidIncident(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateRaidIncidentParams) (db.RaidIncident, error) {
+						require.True(t, arg.LockdownRequested)
+						return newIncident, nil
+					})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventRaidDetected, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					Incident *ResponseRaidIncident `json:"incident"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.NotNil(t, res.Incident)
+				require.Equal(t, newIncident.ID, res.Incident.ID)
+			},
+		},
+		{
+			name: "InternalServerError/Lockdown",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				gomock.InOrder(
+					store.EXPECT().
+						ExecTx(gomock.Any(), gomock.Any()).
+						Return(nil),
+					store.EXPECT().
+						ExecTx(gomock.Any(), gomock.Any()).
+						Return(sql.ErrConnDone),
+				)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 3), nil)
+				store.EXPECT().
+					GetOpenRaidIncident(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RaidIncident{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateRaidIncident(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(newIncident, nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			name: "OK/UpdatesOpenIncident",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 3), nil)
+				store.EXPECT().
+					GetOpenRaidIncident(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(openIncident, nil)
+				store.EXPECT().
+					UpdateRaidIncidentDetection(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(openIncident, nil)
+				store.EXPECT().
+					CreateRaidIncident(gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "OK/ReplacesQuietIncident",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 3), nil)
+				store.EXPECT().
+					GetOpenRaidIncident(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(quietIncident, nil)
+				store.EXPECT().
+					ResolveRaidIncident(gomock.Any(), gomock.Eq(db.ResolveRaidIncidentParams{
+						ID:             quietIncident.ID,
+						GuildDiscordID: guild.DiscordID,
+					})).
+					Times(1).
+					Return(quietIncident, nil)
+				store.EXPECT().
+					CreateRaidIncident(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(newIncident, nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "OK/ConcurrentIncident",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 3), nil)
+				gomock.InOrder(
+					store.EXPECT().
+						GetOpenRaidIncident(gomock.Any(), gomock.Any()).
+						Return(db.RaidIncident{}, sql.ErrNoRows),
+					store.EXPECT().
+						CreateRaidIncident(gomock.Any(), gomock.Any()).
+						Return(db.RaidIncident{}, &pq.Error{Code: "23505"}),
+					store.EXPECT().
+						GetOpenRaidIncident(gomock.Any(), gomock.Any()).
+						Return(openIncident, nil),
+					store.EXPECT().
+						UpdateRaidIncidentDetection(gomock.Any(), gomock.Any()).
+						Return(openIncident, nil),
+				)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/InvalidMemberID",
+			form: forms.BotReportMemberEventsJSON{
+				Events: []forms.BotMemberEventJSON{
+					{Type: raid.EventJoin, MemberDiscordID: "me", OccurredAt: time.Now()},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/NoEvents",
+			form: forms.BotReportMemberEventsJSON{},
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound/Guild",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GuildConfig{}, sql.ErrNoRows)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			form: form,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			raidController := NewRaidController(store, memStore, services.NewLockdownService(store, memStore))
+			router := gin.New()
+			router.POST("/api/v1/bot/guilds/:discord_id/members/events", raidController.ReportMemberEvents)
+
+			body, err := json.Marshal(tc.form)
+			require.NoError(t, err)
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/members/events", guild.DiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestRaidController_GetRaidTimeline(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateRandomUser()
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK/Default",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(generateGuildConfig(t, setRaid(true)), nil)
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateMemberEvents(guild.DiscordID, 3), nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res struct {
+					BucketSeconds int                   `json:"bucket_seconds"`
+					Buckets       []raid.TimelineBucket `json:"buckets"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, 60, res.BucketSeconds)
+				require.Len(t, res.Buckets, 60)
+				require.Equal(t, 3, res.Buckets[len(res.Buckets)-1].Joins)
+			},
+		},
+		{
+			name:  "BadRequest/Range",
+			query: "?since=2022-10-02T00:00:00Z&until=2022-10-01T00:00:00Z",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name:  "BadRequest/TooManyBuckets",
+			query: "?since=2022-10-01T00:00:00Z&until=2022-10-02T00:00:00Z&bucket_seconds=10",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildMemberEvents(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/raids/timeline%s", guild.DiscordID, tc.query)
+			w := serveAuthorized(t, newRaidRouter(store, mockmemdb.NewMockStore(ctrl)), user.DiscordID, http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestRaidController_ResolveRaidIncident(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateRandomUser()
+	incident := generateRaidIncident(guild.DiscordID, time.Now())
+
+	testCases := []struct {
+		name          string
+		incidentID    int64
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			incidentID: incident.ID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventRaidResolved, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			incidentID: incident.ID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrNoRows)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name:       "Conflict/Resolved",
+			incidentID: incident.ID,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(errIncidentResolved)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, w.Code)
+			},
+		},
+		{
+			name:       "BadRequest/InvalidID",
+			incidentID: 0,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			url := fmt.Sprintf("/api/v1/guilds/%s/raids/%d/resolve", guild.DiscordID, tc.incidentID)
+			w := serveAuthorized(t, newRaidRouter(store, memStore), user.DiscordID, http.MethodPost, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -1,20 +1,30 @@
 package controllers
 
 import (
+	"archive/zip"
+	"bytes"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
 type UserController struct {
-	store db.Store
+	store           db.Store
+	userDataService *services.UserDataService
 }
 
-func NewUserController(store db.Store) *UserController {
-	return &UserController{store: store}
+func NewUserController(store db.Store, userDataService *services.UserDataService) *UserController {
+	return &UserController{
+		store:           store,
+		userDataService: userDataService,
+	}
 }
 
 func (ctrl *UserController) GetUser(c *gin.Context) {
@@ -32,3 +42,93 @@ func (ctrl *UserController) GetUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, account)
 }
+
+func (ctrl *UserController) ExportUser(c *gin.Context) {
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	var query forms.ExportUserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	export, err := ctrl.userDataService.Export(c, payload.UserDiscordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if query.Format != "zip" {
+		c.Header("Content-Disposition", `attachment; filename="sentinel-export.json"`)
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	archive, err := zipUserDataExport(export)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="sentinel-export.zip"`)
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func (ctrl *UserController) DeleteUser(c *gin.Context) {
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	err := ctrl.userDataService.Delete(c, payload.UserDiscordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// sessions are already revoked, clearing cookies only tidies up the browser
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(middlewares.RefreshTokenCookie, "", -1, middlewares.RefreshTokenCookiePath, "", true, true)
+	c.SetCookie(middlewares.CSRFTokenCookie, "", -1, "/", "", true, false)
+	c.Status(http.StatusNoContent)
+}
+
+// zipUserDataExport packs every part of the export into a separate JSON file
+func zipUserDataExport(export services.UserDataExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{name: "user.json", data: export.User},
+		{name: "guilds.json", data: export.Guilds},
+		{name: "sessions.json", data: export.Sessions},
+		{name: "audit_log.json", data: export.AuditLog},
+		{name: "appeals.json", data: export.Appeals},
+		{name: "appeal_comments.json", data: export.AppealComments},
+		{name: "member_notes.json", data: export.MemberNotes},
+		{name: "member_events.json", data: export.MemberEvents},
+		{name: "verified_members.json", data: export.VerifiedMembers},
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, file := range files {
+		f, err := w.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,18 +1,25 @@
 package controllers
 
 import (
+	"archive/zip"
 	"bytes"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
 	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/raid"
 	token2 "github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/services"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"net/http"
@@ -112,7 +119,7 @@ func TestUserController_Get(t *testing.T) {
 			// build server
 			tokenMaker, _ := token2.NewPasetoMaker(utils.RandomString(32))
 			authMiddleware := middlewares.NewAuthMiddleware(tokenMaker)
-			userController := NewUserController(store)
+			userController := NewUserController(store, nil)
 			router := gin.New()
 			router.GET("/api/v1/users/me", authMiddleware, userController.GetUser)
 
@@ -132,3 +139,323 @@ func TestUserController_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestUserController_ExportUser(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	user := generateRandomUser()
+	guild := generateRandomGuild()
+	session := memdb.Session{
+		ID:           uuid.New(),
+		DiscordID:    user.DiscordID,
+		RefreshToken: utils.RandomString(32),
+		UserAgent:    gofakeit.UserAgent(),
+		ClientIp:     gofakeit.IPv4Address(),
+		CreatedAt:    time.Now(),
+	}
+	auditLog := db.AuditLog{
+		ID:             int64(utils.RandomInt(1, 1000)),
+		ActorDiscordID: sql.NullString{String: user.DiscordID, Valid: true},
+		GuildDiscordID: guild.DiscordID,
+		Action:         db.AuditActionGuildConfigOverwrite,
+		Data:           json.RawMessage(`{}`),
+	}
+	author := sql.NullString{String: user.DiscordID, Valid: true}
+	appeal := generateRandomAppeal(generateBanCase(guild.DiscordID, user.DiscordID))
+	comment := db.AppealComment{
+		ID:              int64(utils.RandomInt(1, 1000)),
+		AppealID:        int64(utils.RandomInt(1, 1000)),
+		AuthorDiscordID: author,
+		Content:         utils.RandomString(20),
+	}
+	note := generateRandomMemberNote(guild.DiscordID, utils.RandomSnowflakeID().String())
+	note.AuthorDiscordID = author
+	memberEvent := db.MemberEvent{
+		ID:               int64(utils.RandomInt(1, 1000)),
+		GuildDiscordID:   guild.DiscordID,
+		Type:             raid.EventJoin,
+		MemberDiscordID:  user.DiscordID,
+		AccountCreatedAt: time.Now().Add(-time.Hour),
+		OccurredAt:       time.Now(),
+	}
+	verifiedMember := db.VerifiedMember{
+		GuildDiscordID:  guild.DiscordID,
+		MemberDiscordID: user.DiscordID,
+		VerifiedAt:      time.Now(),
+	}
+
+	buildOKStubs := func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+		store.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(user.DiscordID)).
+			Times(1).
+			Return(user, nil)
+		store.EXPECT().
+			GetUserGuilds(gomock.Any(), gomock.Eq(user.DiscordID)).
+			Times(1).
+			Return([]db.GetUserGuildsRow{{DiscordID: guild.DiscordID, Name: guild.Name}}, nil)
+		memStore.EXPECT().
+			GetUserSessions(gomock.Any(), gomock.Eq(user.DiscordID)).
+			Times(1).
+			Return([]memdb.Session{session}, nil)
+		store.EXPECT().
+			GetUserAuditLogs(gomock.Any(), gomock.Eq(auditLog.ActorDiscordID)).
+			Times(1).
+			Return([]db.AuditLog{auditLog}, nil)
+		store.EXPECT().
+			GetUserAppeals(gomock.Any(), gomock.Eq(user.DiscordID)).
+			Times(1).
+			Return([]db.Appeal{appeal}, nil)
+		store.EXPECT().
+			GetUserAppealComments(gomock.Any(), gomock.Eq(author)).
+			Times(1).
+			Return([]db.AppealComment{comment}, nil)
+		store.EXPECT().
+			GetAuthoredMemberNotes(gomock.Any(), gomock.Eq(author)).
+			Times(1).
+			Return([]db.MemberNote{note}, nil)
+		store.EXPECT().
+			GetUserMemberEvents(gomock.Any(), gomock.Eq(user.DiscordID)).
+			Times(1).
+			Return([]db.MemberEvent{memberEvent}, nil)
+		store.EXPECT().
+			GetUserVerifiedMembers(gomock.Any(), gomock.Eq(user.DiscordID)).
+			Times(1).
+			Return([]db.VerifiedMember{verifiedMember}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK/JSON",
+			buildStubs: buildOKStubs,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.NotContains(t, w.Body.String(), session.RefreshToken)
+
+				var export services.UserDataExport
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &export))
+				require.Equal(t, user.DiscordID, export.User.DiscordID)
+				require.Len(t, export.Guilds, 1)
+				require.Len(t, export.Sessions, 1)
+				require.Equal(t, session.ID.String(), export.Sessions[0].ID)
+				require.Len(t, export.AuditLog, 1)
+				require.Len(t, export.Appeals, 1)
+				require.Equal(t, appeal.ID, export.Appeals[0].ID)
+				require.Len(t, export.AppealComments, 1)
+				require.Len(t, export.MemberNotes, 1)
+				require.Len(t, export.MemberEvents, 1)
+				require.Len(t, export.VerifiedMembers, 1)
+			},
+		},
+		{
+			name:       "OK/ZIP",
+			query:      "?format=zip",
+			buildStubs: buildOKStubs,
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+
+				archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+				require.NoError(t, err)
+				var names []string
+				for _, f := range archive.File {
+					names = append(names, f.Name)
+				}
+				require.ElementsMatch(t, []string{
+					"user.json", "guilds.json", "sessions.json", "audit_log.json",
+					"appeals.json", "appeal_comments.json", "member_notes.json", "member_events.json",
+					"verified_members.json",
+				}, names)
+			},
+		},
+		{
+			name:  "BadRequest/Format",
+			query: "?format=xml",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/MemDBGetUserSessions",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserGuilds(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(nil, nil)
+				memStore.EXPECT().
+					GetUserSessions(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(nil, redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetAuthoredMemberNotes",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserGuilds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				memStore.EXPECT().
+					GetUserSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					GetUserAuditLogs(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					GetUserAppeals(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					GetUserAppealComments(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, nil)
+				store.EXPECT().
+					GetAuthoredMemberNotes(gomock.Any(), gomock.Eq(author)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			tokenMaker, _ := token2.NewPasetoMaker(utils.RandomString(32))
+			authMiddleware := middlewares.NewAuthMiddleware(tokenMaker)
+			userController := NewUserController(store, services.NewUserDataService(store, memStore))
+			router := gin.New()
+			router.GET("/api/v1/users/me/export", authMiddleware, userController.ExportUser)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/v1/users/me/export"+tc.query, nil)
+			require.NoError(t, err)
+			accessToken, _, err := tokenMaker.CreateToken(user.DiscordID, time.Minute)
+			require.NoError(t, err)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestUserController_DeleteUser(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	user := generateRandomUser()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "NoContent",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, w.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBExecTx",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExecTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			tokenMaker, _ := token2.NewPasetoMaker(utils.RandomString(32))
+			authMiddleware := middlewares.NewAuthMiddleware(tokenMaker)
+			userController := NewUserController(store, services.NewUserDataService(store, memStore))
+			router := gin.New()
+			router.DELETE("/api/v1/users/me", authMiddleware, userController.DeleteUser)
+
+			req, err := http.NewRequest(http.MethodDelete, "/api/v1/users/me", nil)
+			require.NoError(t, err)
+			accessToken, _, err := tokenMaker.CreateToken(user.DiscordID, time.Minute)
+			require.NoError(t, err)
+			req.Header.Set(middlewares.AuthorizationHeaderKey, fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, accessToken))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/verification"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+)
+
+// verificationChallengeDuration is how long the user has to solve the issued challenge
+const verificationChallengeDuration = 10 * time.Minute
+
+var (
+	errVerificationDisabled = errors.New("guild does not require verification")
+	errChallengeExpired     = errors.New("challenge not exists or expired")
+	errChallengeUnsolved    = errors.New("solution does not meet the challenge difficulty")
+	errRequirementsNotMet   = errors.New("account does not meet verification requirements of the guild")
+)
+
+type VerificationController struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewVerificationController(store db.Store, memStore memdb.Store) *VerificationController {
+	return &VerificationController{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+type ResponseVerification struct {
+	GuildDiscordID       string     `json:"guild_discord_id"`
+	GuildName            string     `json:"guild_name"`
+	GuildIcon            string     `json:"guild_icon"`
+	Verified             bool       `json:"verified"`
+	VerifiedAt           *time.Time `json:"verified_at"`
+	MinAccountAgeDays    int        `json:"min_account_age_days"`
+	RequireVerifiedEmail bool       `json:"require_verified_email"`
+	RequireAvatar        bool       `json:"require_avatar"`
+	// Unmet lists requirements the user does not meet, a challenge is issued only once all are met
+	Unmet     []string                     `json:"unmet"`
+	Challenge *memdb.VerificationChallenge `json:"challenge"`
+}
+
+type ResponseMemberVerification struct {
+	GuildDiscordID  string     `json:"guild_discord_id"`
+	MemberDiscordID string     `json:"member_discord_id"`
+	Verified        bool       `json:"verified"`
+	VerifiedAt      *time.Time `json:"verified_at"`
+	RoleID          string     `json:"role_id"`
+}
+
+// VerificationCommandData tells the bot which role to grant to the verified member
+type VerificationCommandData struct {
+	MemberDiscordID string `json:"member_discord_id"`
+	RoleID          string `json:"role_id"`
+}
+
+// GetVerification returns verification status of the user in the guild.
+// Users meeting the requirements get a fresh proof-of-work challenge, replacing the previous one.
+func (ctrl *VerificationController) GetVerification(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	guild, err := ctrl.store.GetGuild(c, uri.DiscordID)
+	if err != nil {
+		ctrl.verificationError(c, err)
+		return
+	}
+	config, err := ctrl.verificationConfig(c, uri.DiscordID)
+	if err != nil {
+		ctrl.verificationError(c, err)
+		return
+	}
+
+	res := ResponseVerification{
+		GuildDiscordID:       guild.DiscordID,
+		GuildName:            guild.Name,
+		GuildIcon:            guild.Icon,
+		MinAccountAgeDays:    config.MinAccountAgeDays,
+		RequireVerifiedEmail: config.RequireVerifiedEmail,
+		RequireAvatar:        config.RequireAvatar,
+		Unmet:                []string{},
+	}
+
+	verifiedMember, err := ctrl.store.GetVerifiedMember(c, db.GetVerifiedMemberParams{
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: payload.UserDiscordID,
+	})
+	switch {
+	case err == nil:
+		res.Verified = true
+		res.VerifiedAt = &verifiedMember.VerifiedAt
+		c.JSON(http.StatusOK, res)
+		return
+	case !errors.Is(err, sql.ErrNoRows):
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+	res.Unmet, err = ctrl.unmetRequirements(c, config, payload.UserDiscordID, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if len(res.Unmet) == 0 {
+		challenge := memdb.VerificationChallenge{
+			Challenge:  verification.NewChallenge(),
+			Difficulty: config.Difficulty,
+			ExpiresAt:  now.Add(verificationChallengeDuration),
+		}
+		err := ctrl.memStore.SetVerificationChallenge(c, uri.DiscordID, payload.UserDiscordID, challenge, verificationChallengeDuration)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		res.Challenge = &challenge
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// SubmitVerification accepts solution of the issued challenge, records the user as verified
+// and tells the bot to grant the verified role
+func (ctrl *VerificationController) SubmitVerification(c *gin.Context) {
+	var uri forms.RequireDiscordIDRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var form forms.SubmitVerificationJSON
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	payload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+	config, err := ctrl.verificationConfig(c, uri.DiscordID)
+	if err != nil {
+		ctrl.verificationError(c, err)
+		return
+	}
+
+	challenge, err := ctrl.memStore.GetVerificationChallenge(c, uri.DiscordID, payload.UserDiscordID)
+	if err != nil {
+		ctrl.verificationError(c, err)
+		return
+	}
+	// a newer challenge replaces the older one, solutions of the older one are rejected
+	if challenge.Challenge != form.Challenge {
+		c.JSON(http.StatusNotFound, errorResponse(errChallengeExpired))
+		return
+	}
+	if !verification.Verify(challenge.Challenge, challenge.Difficulty, form.Solution) {
+		c.JSON(http.StatusBadRequest, errorResponse(errChallengeUnsolved))
+		return
+	}
+
+	// the account may have changed since the challenge was issued
+	unmet, err := ctrl.unmetRequirements(c, config, payload.UserDiscordID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if len(unmet) > 0 {
+		c.JSON(http.StatusForbidden, gin.H{
+			"message": errRequirementsNotMet.Error(),
+			"unmet":   unmet,
+		})
+		return
+	}
+
+	// the challenge is consumed before recording, so a solution is accepted once
+	if err := ctrl.memStore.DeleteVerificationChallenge(c, uri.DiscordID, payload.UserDiscordID); err != nil {
+		ctrl.verificationError(c, err)
+		return
+	}
+
+	verifiedMember, err := ctrl.store.CreateVerifiedMember(c, db.CreateVerifiedMemberParams{
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: payload.UserDiscordID,
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			err := errors.New("guild not found")
+			c.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	res := newResponseMemberVerification(verifiedMember, config)
+	ctrl.grantRole(c, res)
+	c.JSON(http.StatusOK, res)
+}
+
+// GetMemberVerification tells the bot whether the member is verified,
+// so the role can be granted again to members who rejoined or were verified while the bot was offline
+func (ctrl *VerificationController) GetMemberVerification(c *gin.Context) {
+	var uri forms.MemberURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	config, err := ctrl.verificationConfig(c, uri.DiscordID)
+	if err != nil {
+		ctrl.verificationError(c, err)
+		return
+	}
+
+	verifiedMember, err := ctrl.store.GetVerifiedMember(c, db.GetVerifiedMemberParams{
+		GuildDiscordID:  uri.DiscordID,
+		MemberDiscordID: uri.MemberID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusOK, ResponseMemberVerification{
+			GuildDiscordID:  uri.DiscordID,
+			MemberDiscordID: uri.MemberID,
+			RoleID:          config.RoleID,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, newResponseMemberVerification(verifiedMember, config))
+}
+
+func newResponseMemberVerification(verifiedMember db.VerifiedMember, config objects.VerificationConfig) ResponseMemberVerification {
+	return ResponseMemberVerification{
+		GuildDiscordID:  verifiedMember.GuildDiscordID,
+		MemberDiscordID: verifiedMember.MemberDiscordID,
+		Verified:        true,
+		VerifiedAt:      &verifiedMember.VerifiedAt,
+		RoleID:          config.RoleID,
+	}
+}
+
+// verificationConfig returns config of the guild, errVerificationDisabled if the guild does not verify members
+func (ctrl *VerificationController) verificationConfig(c *gin.Context, guildDiscordID string) (objects.VerificationConfig, error) {
+	guildConfig, err := ctrl.store.GetGuildConfig(c, guildDiscordID)
+	if err != nil {
+		return objects.VerificationConfig{}, err
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return objects.VerificationConfig{}, err
+	}
+	if !guildConfigObj.Data.Verification.Enabled {
+		return objects.VerificationConfig{}, errVerificationDisabled
+	}
+	return guildConfigObj.Data.Verification, nil
+}
+
+func (ctrl *VerificationController) unmetRequirements(c *gin.Context, config objects.VerificationConfig, userDiscordID string, now time.Time) ([]string, error) {
+	user, err := ctrl.store.GetUser(c, userDiscordID)
+	if err != nil {
+		return nil, err
+	}
+	return verification.UnmetRequirements(config, verification.Applicant{
+		DiscordID: user.DiscordID,
+		Verified:  user.Verified,
+		Avatar:    user.Avatar,
+	}, now)
+}
+
+// grantRole pushes the command to the bot, members verified while no bot listens get the role once the bot asks
+func (ctrl *VerificationController) grantRole(c *gin.Context, res ResponseMemberVerification) {
+	data, _ := json.Marshal(VerificationCommandData{
+		MemberDiscordID: res.MemberDiscordID,
+		RoleID:          res.RoleID,
+	})
+	receivers, err := ctrl.memStore.PublishBotCommand(c, memdb.BotCommand{
+		ID:             fmt.Sprintf("verification_%s_%s", res.GuildDiscordID, res.MemberDiscordID),
+		Type:           memdb.BotCommandGrantRole,
+		GuildDiscordID: res.GuildDiscordID,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish verification command: %v", err.Error())
+	} else if receivers == 0 {
+		logrus.Warnf("No bot received verification command of guild %s", res.GuildDiscordID)
+	}
+
+	// verified members are shown like other member events, only to subscribers who can read cases
+	eventData, _ := json.Marshal(res)
+	err = ctrl.memStore.PublishGuildEvent(c, memdb.GuildEvent{
+		Type:           memdb.GuildEventMemberVerified,
+		GuildDiscordID: res.GuildDiscordID,
+		Capability:     token.CapabilityCasesRead,
+		Data:           eventData,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish verification event: %v", err.Error())
+	}
+}
+
+func (ctrl *VerificationController) verificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, errVerificationDisabled):
+		c.JSON(http.StatusNotFound, errorResponse(err))
+	case errors.Is(err, redis.Nil):
+		c.JSON(http.StatusNotFound, errorResponse(errChallengeExpired))
+	default:
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
@@ -0,0 +1,580 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/verification"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// generateDiscordUser returns verified user whose Discord account was created the days ago
+func generateDiscordUser(days int) db.User {
+	user := generateRandomUser()
+	createdAt := time.Now().AddDate(0, 0, -days).UnixMilli()
+	user.DiscordID = strconv.FormatInt((createdAt-1420070400000)<<22, 10)
+	user.Verified = true
+	return user
+}
+
+// setVerification switches verification with a difficulty which tests solve quickly
+func setVerification(enabled bool) func(config *objects.GuildConfig) {
+	return func(config *objects.GuildConfig) {
+		config.Data.Verification.Enabled = enabled
+		config.Data.Verification.RoleID = utils.RandomSnowflakeID().String()
+		config.Data.Verification.Difficulty = 8
+	}
+}
+
+// newVerificationRouter routes verification endpoints of the user
+func newVerificationRouter(store *mockdb.MockStore, memStore *mockmemdb.MockStore) *gin.Engine {
+	verificationController := NewVerificationController(store, memStore)
+	router := newAuthorizedRouter()
+	router.GET("/api/v1/users/me/verifications/:discord_id", verificationController.GetVerification)
+	router.POST("/api/v1/users/me/verifications/:discord_id", verificationController.SubmitVerification)
+	return router
+}
+
+func TestVerificationController_GetVerification(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateDiscordUser(30)
+	youngUser := generateDiscordUser(1)
+	verifiedMember := db.VerifiedMember{
+		GuildDiscordID:  guild.DiscordID,
+		MemberDiscordID: user.DiscordID,
+		VerifiedAt:      time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/ChallengeIssued",
+			user: user,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID, Name: guild.Name}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Eq(db.GetVerifiedMemberParams{
+						GuildDiscordID:  guild.DiscordID,
+						MemberDiscordID: user.DiscordID,
+					})).
+					Times(1).
+					Return(db.VerifiedMember{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(user, nil)
+				memStore.EXPECT().
+					SetVerificationChallenge(gomock.Any(), gomock.Eq(guild.DiscordID), gomock.Eq(user.DiscordID), gomock.Any(), gomock.Eq(verificationChallengeDuration)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseVerification
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, guild.Name, res.GuildName)
+				require.False(t, res.Verified)
+				require.Empty(t, res.Unmet)
+				require.NotNil(t, res.Challenge)
+				require.Equal(t, 8, res.Challenge.Difficulty)
+			},
+		},
+		{
+			name: "OK/AlreadyVerified",
+			user: user,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(verifiedMember, nil)
+				memStore.EXPECT().
+					SetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseVerification
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.True(t, res.Verified)
+				require.Nil(t, res.Challenge)
+			},
+		},
+		{
+			name: "OK/RequirementsNotMet",
+			user: youngUser,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifiedMember{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(youngUser, nil)
+				memStore.EXPECT().
+					SetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseVerification
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, []string{verification.RequirementAccountAge}, res.Unmet)
+				require.Nil(t, res.Challenge)
+			},
+		},
+		{
+			name: "NotFound/Disabled",
+			user: user,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetGuildRow{DiscordID: guild.DiscordID}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(false)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "NotFound/Guild",
+			user: user,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuild(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GetGuildRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			url := fmt.Sprintf("/api/v1/users/me/verifications/%s", guild.DiscordID)
+			w := serveAuthorized(t, newVerificationRouter(store, memStore), tc.user.DiscordID, http.MethodGet, url, nil)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestVerificationController_SubmitVerification(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	user := generateDiscordUser(30)
+	unverifiedEmailUser := user
+	unverifiedEmailUser.Verified = false
+	challenge := memdb.VerificationChallenge{
+		Challenge:  verification.NewChallenge(),
+		Difficulty: 8,
+		ExpiresAt:  time.Now().Add(verificationChallengeDuration),
+	}
+	verifiedMember := db.VerifiedMember{
+		GuildDiscordID:  guild.DiscordID,
+		MemberDiscordID: user.DiscordID,
+		VerifiedAt:      time.Now(),
+	}
+
+	formJSON, err := json.Marshal(forms.SubmitVerificationJSON{
+		Challenge: challenge.Challenge,
+		Solution:  verification.Solve(challenge.Challenge, challenge.Difficulty),
+	})
+	require.NoError(t, err)
+	staleChallengeJSON, err := json.Marshal(forms.SubmitVerificationJSON{
+		Challenge: verification.NewChallenge(),
+		Solution:  verification.Solve(challenge.Challenge, challenge.Difficulty),
+	})
+	require.NoError(t, err)
+
+	// unsolved is the first nonce whose hash does not meet the difficulty
+	unsolved := ""
+	for nonce := 0; verification.Verify(challenge.Challenge, challenge.Difficulty, unsolved); nonce++ {
+		unsolved = strconv.Itoa(nonce)
+	}
+	unsolvedJSON, err := json.Marshal(forms.SubmitVerificationJSON{
+		Challenge: challenge.Challenge,
+		Solution:  unsolved,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          []byte
+		buildStubs    func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				memStore.EXPECT().
+					GetVerificationChallenge(gomock.Any(), gomock.Eq(guild.DiscordID), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(user, nil)
+				memStore.EXPECT().
+					DeleteVerificationChallenge(gomock.Any(), gomock.Eq(guild.DiscordID), gomock.Eq(user.DiscordID)).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					CreateVerifiedMember(gomock.Any(), gomock.Eq(db.CreateVerifiedMemberParams{
+						GuildDiscordID:  guild.DiscordID,
+						MemberDiscordID: user.DiscordID,
+					})).
+					Times(1).
+					Return(verifiedMember, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, command memdb.BotCommand) (int64, error) {
+						require.Equal(t, memdb.BotCommandGrantRole, command.Type)
+						require.Equal(t, guild.DiscordID, command.GuildDiscordID)
+
+						var data VerificationCommandData
+						require.NoError(t, json.Unmarshal(command.Data, &data))
+						require.Equal(t, user.DiscordID, data.MemberDiscordID)
+						require.NotEmpty(t, data.RoleID)
+						return 1, nil
+					})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventMemberVerified, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseMemberVerification
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.True(t, res.Verified)
+				require.Equal(t, user.DiscordID, res.MemberDiscordID)
+			},
+		},
+		{
+			name: "NotFound/ChallengeExpired",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				memStore.EXPECT().
+					GetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(memdb.VerificationChallenge{}, redis.Nil)
+				store.EXPECT().
+					CreateVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "NotFound/StaleChallenge",
+			body: staleChallengeJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				memStore.EXPECT().
+					GetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					CreateVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "NotFound/ChallengeConsumed",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				memStore.EXPECT().
+					GetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				memStore.EXPECT().
+					DeleteVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(redis.Nil)
+				store.EXPECT().
+					CreateVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "BadRequest/Unsolved",
+			body: unsolvedJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				memStore.EXPECT().
+					GetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(challenge, nil)
+				memStore.EXPECT().
+					DeleteVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+		{
+			name: "Forbidden/RequirementsNotMet",
+			body: formJSON,
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				memStore.EXPECT().
+					GetVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(unverifiedEmailUser, nil)
+				memStore.EXPECT().
+					DeleteVerificationChallenge(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+
+				var res struct {
+					Unmet []string `json:"unmet"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.Equal(t, []string{verification.RequirementVerifiedEmail}, res.Unmet)
+			},
+		},
+		{
+			name: "BadRequest/NoChallenge",
+			body: []byte(`{"solution":"1"}`),
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			url := fmt.Sprintf("/api/v1/users/me/verifications/%s", guild.DiscordID)
+			w := serveAuthorized(t, newVerificationRouter(store, memStore), user.DiscordID, http.MethodPost, url, tc.body)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestVerificationController_GetMemberVerification(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guild := generateRandomGuild()
+	memberDiscordID := utils.RandomSnowflakeID().String()
+	verifiedMember := db.VerifiedMember{
+		GuildDiscordID:  guild.DiscordID,
+		MemberDiscordID: memberDiscordID,
+		VerifiedAt:      time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/Verified",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guild.DiscordID)).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Eq(db.GetVerifiedMemberParams{
+						GuildDiscordID:  guild.DiscordID,
+						MemberDiscordID: memberDiscordID,
+					})).
+					Times(1).
+					Return(verifiedMember, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseMemberVerification
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.True(t, res.Verified)
+				require.NotEmpty(t, res.RoleID)
+			},
+		},
+		{
+			name: "OK/NotVerified",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(true)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.VerifiedMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var res ResponseMemberVerification
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+				require.False(t, res.Verified)
+				require.Nil(t, res.VerifiedAt)
+			},
+		},
+		{
+			name: "NotFound/Disabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(generateGuildConfig(t, setVerification(false)), nil)
+				store.EXPECT().
+					GetVerifiedMember(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			verificationController := NewVerificationController(store, memStore)
+			router := gin.New()
+			router.GET("/api/v1/bot/guilds/:discord_id/members/:member_id/verification", verificationController.GetMemberVerification)
+
+			url := fmt.Sprintf("/api/v1/bot/guilds/%s/members/%s/verification", guild.DiscordID, memberDiscordID)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"errors"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type WellKnownController struct {
+	tokenMaker token.Maker
+}
+
+func NewWellKnownController(tokenMaker token.Maker) *WellKnownController {
+	return &WellKnownController{tokenMaker: tokenMaker}
+}
+
+// GetPaserk publishes public keys which can be used to verify access tokens offline
+func (ctrl *WellKnownController) GetPaserk(c *gin.Context) {
+	keySet, ok := ctrl.tokenMaker.(token.KeySet)
+	if !ok {
+		err := errors.New("tokens are signed with symmetric key")
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": keySet.PublicKeys()})
+}
+
+// GetJWKS publishes public keys of EdDSA signed JWT
+func (ctrl *WellKnownController) GetJWKS(c *gin.Context) {
+	jwkSet, ok := ctrl.tokenMaker.(token.JWKSet)
+	if !ok || len(jwkSet.JWKs()) == 0 {
+		err := errors.New("tokens are signed with symmetric key")
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": jwkSet.JWKs()})
+}
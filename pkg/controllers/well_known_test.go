@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWellKnownController_GetPaserk(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		newMaker      func(t *testing.T) token.Maker
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			newMaker: func(t *testing.T) token.Maker {
+				maker, err := token.NewPasetoV4PublicMaker(token.EncodePaserkSecret(secretKey), nil)
+				require.NoError(t, err)
+				return maker
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), token.EncodePaserkPublic(publicKey))
+				require.Contains(t, w.Body.String(), token.PaserkPublicID(publicKey))
+			},
+		},
+		{
+			name: "NotFound/SymmetricMaker",
+			newMaker: func(t *testing.T) token.Maker {
+				maker, err := token.NewPasetoMaker(utils.RandomString(32))
+				require.NoError(t, err)
+				return maker
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wellKnownController := NewWellKnownController(tc.newMaker(t))
+			router := gin.New()
+			router.GET("/.well-known/paserk", wellKnownController.GetPaserk)
+
+			req, err := http.NewRequest(http.MethodGet, "/.well-known/paserk", nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestWellKnownController_GetJWKS(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		newMaker      func(t *testing.T) token.Maker
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			newMaker: func(t *testing.T) token.Maker {
+				maker, err := token.NewEdDSAJWTMaker(base64.RawURLEncoding.EncodeToString(secretKey), nil)
+				require.NoError(t, err)
+				return maker
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Contains(t, w.Body.String(), base64.RawURLEncoding.EncodeToString(publicKey))
+				require.Contains(t, w.Body.String(), token.JWKThumbprint(publicKey))
+				require.Contains(t, w.Body.String(), `"alg":"EdDSA"`)
+			},
+		},
+		{
+			name: "NotFound/HS256Maker",
+			newMaker: func(t *testing.T) token.Maker {
+				maker, err := token.NewHS256JWTMaker(utils.RandomString(32))
+				require.NoError(t, err)
+				return maker
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+		{
+			name: "NotFound/PasetoMaker",
+			newMaker: func(t *testing.T) token.Maker {
+				maker, err := token.NewPasetoMaker(utils.RandomString(32))
+				require.NoError(t, err)
+				return maker
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wellKnownController := NewWellKnownController(tc.newMaker(t))
+			router := gin.New()
+			router.GET("/.well-known/jwks.json", wellKnownController.GetJWKS)
+
+			req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v9"
+	"github.com/google/uuid"
+	"strconv"
+	"time"
+)
+
+// HitAutomodRate records a message in the sliding window of the key and returns amount of messages within it.
+// Hits are shared by all backend replicas.
+func (r *Redis) HitAutomodRate(ctx context.Context, key string, at time.Time, window time.Duration) (int64, error) {
+	key = fmt.Sprintf("automod_rate_%s", key)
+
+	var count *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(at.Add(-window).UnixNano(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixNano()), Member: uuid.NewString()})
+		count = pipe.ZCard(ctx, key)
+		pipe.PExpire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
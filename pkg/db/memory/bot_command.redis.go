@@ -0,0 +1,41 @@
+package memdb
+
+import (
+	"context"
+)
+
+const botCommandsChannel = "bot_commands"
+
+// PublishBotCommand returns amount of bot connections which received the command
+func (r *Redis) PublishBotCommand(ctx context.Context, command BotCommand) (int64, error) {
+	return r.client.Publish(ctx, botCommandsChannel, &command).Result()
+}
+
+// SubscribeBotCommands listens to commands published by any backend replica.
+// Returned channel is closed after calling the close function or when ctx is done.
+func (r *Redis) SubscribeBotCommands(ctx context.Context) (<-chan BotCommand, func() error, error) {
+	pubsub := r.client.Subscribe(ctx, botCommandsChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, nil, err
+	}
+
+	commands := make(chan BotCommand)
+	go func() {
+		defer close(commands)
+		for msg := range pubsub.Channel() {
+			var command BotCommand
+			if err := command.UnmarshalBinary([]byte(msg.Payload)); err != nil {
+				continue
+			}
+			select {
+			case commands <- command:
+			case <-ctx.Done():
+				_ = pubsub.Close()
+				return
+			}
+		}
+	}()
+
+	return commands, pubsub.Close, nil
+}
@@ -0,0 +1,31 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (r *Redis) SetBotInviteFlow(ctx context.Context, state string, botInviteFlow BotInviteFlow, duration time.Duration) error {
+	key := fmt.Sprintf("bot_invite_state_%s", state)
+	return r.client.Set(ctx, key, &botInviteFlow, duration).Err()
+}
+
+func (r *Redis) GetBotInviteFlow(ctx context.Context, state string) (BotInviteFlow, error) {
+	key := fmt.Sprintf("bot_invite_state_%s", state)
+	c := r.client.Get(ctx, key)
+	if err := c.Err(); err != nil {
+		return BotInviteFlow{}, err
+	}
+
+	var botInviteFlow BotInviteFlow
+	if err := c.Scan(&botInviteFlow); err != nil {
+		return BotInviteFlow{}, err
+	}
+	return botInviteFlow, nil
+}
+
+func (r *Redis) DeleteBotInviteFlow(ctx context.Context, state string) error {
+	key := fmt.Sprintf("bot_invite_state_%s", state)
+	return r.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,43 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+)
+
+func guildEventsChannel(guildDiscordID string) string {
+	return fmt.Sprintf("guild_events_%s", guildDiscordID)
+}
+
+func (r *Redis) PublishGuildEvent(ctx context.Context, event GuildEvent) error {
+	return r.client.Publish(ctx, guildEventsChannel(event.GuildDiscordID), &event).Err()
+}
+
+// SubscribeGuildEvents listens to events of the guild published by any backend replica.
+// Returned channel is closed after calling the close function or when ctx is done.
+func (r *Redis) SubscribeGuildEvents(ctx context.Context, guildDiscordID string) (<-chan GuildEvent, func() error, error) {
+	pubsub := r.client.Subscribe(ctx, guildEventsChannel(guildDiscordID))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, nil, err
+	}
+
+	events := make(chan GuildEvent)
+	go func() {
+		defer close(events)
+		for msg := range pubsub.Channel() {
+			var event GuildEvent
+			if err := event.UnmarshalBinary([]byte(msg.Payload)); err != nil {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				_ = pubsub.Close()
+				return
+			}
+		}
+	}()
+
+	return events, pubsub.Close, nil
+}
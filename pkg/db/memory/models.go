@@ -3,6 +3,7 @@ package memdb
 import (
 	"encoding/json"
 	"github.com/google/uuid"
+	"time"
 )
 
 type Session struct {
@@ -12,6 +13,7 @@ type Session struct {
 	UserAgent    string    `json:"user_agent"`
 	ClientIp     string    `json:"client_ip"`
 	IsBlocked    bool      `json:"is_blocked"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 func (s *Session) MarshalBinary() ([]byte, error) {
@@ -22,9 +24,17 @@ func (s *Session) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, &s)
 }
 
+// Oauth2Flow of native clients is not consumed by the callback. It is claimed, marked completed
+// instead and exchanged for tokens once by the client holding the poll secret
 type Oauth2Flow struct {
-	Completed     bool  `json:"completed"`
-	UserDiscordID int64 `json:"user_discord_id"`
+	Completed     bool   `json:"completed"`
+	UserDiscordID string `json:"user_discord_id"`
+	Native        bool   `json:"native"`
+	PollSecret    string `json:"poll_secret"`
+	CodeVerifier  string `json:"code_verifier"`
+	RedirectURL   string `json:"redirect_url"`
+	// BrowserBinding is a hash of the cookie set to the browser which initiated the flow
+	BrowserBinding string `json:"browser_binding"`
 }
 
 func (f *Oauth2Flow) MarshalBinary() ([]byte, error) {
@@ -34,3 +44,109 @@ func (f *Oauth2Flow) MarshalBinary() ([]byte, error) {
 func (f *Oauth2Flow) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, &f)
 }
+
+type BotInviteFlow struct {
+	UserDiscordID  string `json:"user_discord_id"`
+	GuildDiscordID string `json:"guild_discord_id"`
+	Permissions    int64  `json:"permissions"`
+}
+
+func (f *BotInviteFlow) MarshalBinary() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func (f *BotInviteFlow) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &f)
+}
+
+// VerificationChallenge is proof-of-work issued to the user verifying in the guild, it is solved once
+type VerificationChallenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (v *VerificationChallenge) MarshalBinary() ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (v *VerificationChallenge) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &v)
+}
+
+// StreamTicket authorizes a single events stream connection of the user. It is used by browser
+// WebSocket and EventSource, which can not send Authorization header
+type StreamTicket struct {
+	UserDiscordID  string   `json:"user_discord_id"`
+	GuildDiscordID string   `json:"guild_discord_id"`
+	Capabilities   []string `json:"capabilities"`
+}
+
+func (t *StreamTicket) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *StreamTicket) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &t)
+}
+
+const (
+	GuildEventConfigUpdated  = "config_updated"
+	GuildEventSyncResult     = "sync_result"
+	GuildEventBotStatus      = "bot_status"
+	GuildEventOwnerChanged   = "owner_changed"
+	GuildEventCaseCreated    = "case_created"
+	GuildEventCaseUpdated    = "case_updated"
+	GuildEventAppealCreated  = "appeal_created"
+	GuildEventAppealDecided  = "appeal_decided"
+	GuildEventMemberVerified = "member_verified"
+	GuildEventRaidDetected   = "raid_detected"
+	GuildEventRaidResolved   = "raid_resolved"
+	GuildEventLockdown       = "lockdown"
+	GuildEventLockdownLifted = "lockdown_lifted"
+)
+
+type GuildEvent struct {
+	Type           string `json:"type"`
+	GuildDiscordID string `json:"guild_discord_id"`
+	// Capability is required from subscribers to receive the event,
+	// if empty the event is received by everyone who can read guild config
+	Capability string          `json:"capability,omitempty"`
+	Data       json.RawMessage `json:"data"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func (e *GuildEvent) MarshalBinary() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *GuildEvent) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &e)
+}
+
+const (
+	BotCommandUnmute       = "unmute"
+	BotCommandUnban        = "unban"
+	BotCommandGrantRole    = "grant_role"
+	BotCommandLockdown     = "lockdown"
+	BotCommandLiftLockdown = "lift_lockdown"
+)
+
+// BotCommand is an action the bot must perform in the guild
+type BotCommand struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type"`
+	GuildDiscordID string          `json:"guild_discord_id"`
+	Data           json.RawMessage `json:"data"`
+	// RequiresAck is set when the command is delivered again unless the bot acknowledges it
+	RequiresAck bool      `json:"requires_ack"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (c *BotCommand) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *BotCommand) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &c)
+}
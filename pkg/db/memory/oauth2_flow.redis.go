@@ -3,6 +3,7 @@ package memdb
 import (
 	"context"
 	"fmt"
+	"github.com/go-redis/redis/v9"
 	"time"
 )
 
@@ -25,7 +26,28 @@ func (r *Redis) GetOauth2Flow(ctx context.Context, state string) (Oauth2Flow, er
 	return oauth2flow, nil
 }
 
+// DeleteOauth2Flow consumes the state, redis.Nil is returned if it was already consumed
 func (r *Redis) DeleteOauth2Flow(ctx context.Context, state string) error {
 	key := fmt.Sprintf("state_%s", state)
-	return r.client.Del(ctx, key).Err()
+	deleted, err := r.client.Del(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return redis.Nil
+	}
+	return nil
+}
+
+// ClaimOauth2Flow claims the state of a native flow for a single callback, redis.Nil is returned if it was already claimed
+func (r *Redis) ClaimOauth2Flow(ctx context.Context, state string, duration time.Duration) error {
+	key := fmt.Sprintf("state_claim_%s", state)
+	claimed, err := r.client.SetNX(ctx, key, 1, duration).Result()
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return redis.Nil
+	}
+	return nil
 }
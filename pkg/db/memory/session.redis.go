@@ -2,14 +2,25 @@ package memdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/go-redis/redis/v9"
 	"github.com/google/uuid"
 	"time"
 )
 
+// SetSession also indexes the session by user, so all sessions of the user can be listed and revoked
 func (r *Redis) SetSession(ctx context.Context, session Session, duration time.Duration) (Session, error) {
 	key := fmt.Sprintf("session_%s", session.ID)
-	return session, r.client.Set(ctx, key, &session, duration).Err()
+	userKey := fmt.Sprintf("user_sessions_%s", session.DiscordID)
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, &session, duration)
+		pipe.SAdd(ctx, userKey, session.ID.String())
+		pipe.Expire(ctx, userKey, duration)
+		return nil
+	})
+	return session, err
 }
 
 func (r *Redis) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
@@ -25,3 +36,47 @@ func (r *Redis) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
 	}
 	return session, nil
 }
+
+// GetUserSessions returns active sessions of the user, expired ones are removed from the index
+func (r *Redis) GetUserSessions(ctx context.Context, discordID string) ([]Session, error) {
+	userKey := fmt.Sprintf("user_sessions_%s", discordID)
+	ids, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(ids))
+	for _, id := range ids {
+		c := r.client.Get(ctx, fmt.Sprintf("session_%s", id))
+		if errors.Is(c.Err(), redis.Nil) {
+			if err := r.client.SRem(ctx, userKey, id).Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+
+		var session Session
+		if err := c.Scan(&session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (r *Redis) DeleteUserSessions(ctx context.Context, discordID string) error {
+	userKey := fmt.Sprintf("user_sessions_%s", discordID)
+	ids, err := r.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey}
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("session_%s", id))
+	}
+	return r.client.Del(ctx, keys...).Err()
+}
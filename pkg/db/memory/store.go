@@ -11,8 +11,24 @@ type Store interface {
 	SetOauth2Flow(ctx context.Context, state string, oauth2Flow Oauth2Flow, duration time.Duration) error
 	GetOauth2Flow(ctx context.Context, state string) (Oauth2Flow, error)
 	DeleteOauth2Flow(ctx context.Context, state string) error
+	ClaimOauth2Flow(ctx context.Context, state string, duration time.Duration) error
+	SetBotInviteFlow(ctx context.Context, state string, botInviteFlow BotInviteFlow, duration time.Duration) error
+	GetBotInviteFlow(ctx context.Context, state string) (BotInviteFlow, error)
+	DeleteBotInviteFlow(ctx context.Context, state string) error
 	SetSession(ctx context.Context, session Session, duration time.Duration) (Session, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetUserSessions(ctx context.Context, discordID string) ([]Session, error)
+	DeleteUserSessions(ctx context.Context, discordID string) error
+	SetVerificationChallenge(ctx context.Context, guildDiscordID, userDiscordID string, challenge VerificationChallenge, duration time.Duration) error
+	GetVerificationChallenge(ctx context.Context, guildDiscordID, userDiscordID string) (VerificationChallenge, error)
+	DeleteVerificationChallenge(ctx context.Context, guildDiscordID, userDiscordID string) error
+	SetStreamTicket(ctx context.Context, ticket string, streamTicket StreamTicket, duration time.Duration) error
+	ConsumeStreamTicket(ctx context.Context, ticket string) (StreamTicket, error)
+	PublishGuildEvent(ctx context.Context, event GuildEvent) error
+	SubscribeGuildEvents(ctx context.Context, guildDiscordID string) (<-chan GuildEvent, func() error, error)
+	PublishBotCommand(ctx context.Context, command BotCommand) (int64, error)
+	SubscribeBotCommands(ctx context.Context) (<-chan BotCommand, func() error, error)
+	HitAutomodRate(ctx context.Context, key string, at time.Time, window time.Duration) (int64, error)
 }
 
 type Redis struct {
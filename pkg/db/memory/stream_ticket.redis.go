@@ -0,0 +1,27 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (r *Redis) SetStreamTicket(ctx context.Context, ticket string, streamTicket StreamTicket, duration time.Duration) error {
+	key := fmt.Sprintf("stream_ticket_%s", ticket)
+	return r.client.Set(ctx, key, &streamTicket, duration).Err()
+}
+
+// ConsumeStreamTicket returns the ticket and deletes it atomically, redis.Nil is returned if it was already consumed
+func (r *Redis) ConsumeStreamTicket(ctx context.Context, ticket string) (StreamTicket, error) {
+	key := fmt.Sprintf("stream_ticket_%s", ticket)
+	c := r.client.GetDel(ctx, key)
+	if err := c.Err(); err != nil {
+		return StreamTicket{}, err
+	}
+
+	var streamTicket StreamTicket
+	if err := c.Scan(&streamTicket); err != nil {
+		return StreamTicket{}, err
+	}
+	return streamTicket, nil
+}
@@ -0,0 +1,41 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v9"
+	"time"
+)
+
+// SetVerificationChallenge replaces the challenge previously issued to the user in the guild
+func (r *Redis) SetVerificationChallenge(ctx context.Context, guildDiscordID, userDiscordID string, challenge VerificationChallenge, duration time.Duration) error {
+	key := fmt.Sprintf("verification_%s_%s", guildDiscordID, userDiscordID)
+	return r.client.Set(ctx, key, &challenge, duration).Err()
+}
+
+func (r *Redis) GetVerificationChallenge(ctx context.Context, guildDiscordID, userDiscordID string) (VerificationChallenge, error) {
+	key := fmt.Sprintf("verification_%s_%s", guildDiscordID, userDiscordID)
+	c := r.client.Get(ctx, key)
+	if err := c.Err(); err != nil {
+		return VerificationChallenge{}, err
+	}
+
+	var challenge VerificationChallenge
+	if err := c.Scan(&challenge); err != nil {
+		return VerificationChallenge{}, err
+	}
+	return challenge, nil
+}
+
+// DeleteVerificationChallenge consumes the challenge, redis.Nil is returned if it was already consumed
+func (r *Redis) DeleteVerificationChallenge(ctx context.Context, guildDiscordID, userDiscordID string) error {
+	key := fmt.Sprintf("verification_%s_%s", guildDiscordID, userDiscordID)
+	deleted, err := r.client.Del(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return redis.Nil
+	}
+	return nil
+}
@@ -37,6 +37,49 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// ClaimOauth2Flow mocks base method.
+func (m *MockStore) ClaimOauth2Flow(arg0 context.Context, arg1 string, arg2 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOauth2Flow", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimOauth2Flow indicates an expected call of ClaimOauth2Flow.
+func (mr *MockStoreMockRecorder) ClaimOauth2Flow(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOauth2Flow", reflect.TypeOf((*MockStore)(nil).ClaimOauth2Flow), arg0, arg1, arg2)
+}
+
+// ConsumeStreamTicket mocks base method.
+func (m *MockStore) ConsumeStreamTicket(arg0 context.Context, arg1 string) (memdb.StreamTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeStreamTicket", arg0, arg1)
+	ret0, _ := ret[0].(memdb.StreamTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeStreamTicket indicates an expected call of ConsumeStreamTicket.
+func (mr *MockStoreMockRecorder) ConsumeStreamTicket(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeStreamTicket", reflect.TypeOf((*MockStore)(nil).ConsumeStreamTicket), arg0, arg1)
+}
+
+// DeleteBotInviteFlow mocks base method.
+func (m *MockStore) DeleteBotInviteFlow(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBotInviteFlow", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBotInviteFlow indicates an expected call of DeleteBotInviteFlow.
+func (mr *MockStoreMockRecorder) DeleteBotInviteFlow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBotInviteFlow", reflect.TypeOf((*MockStore)(nil).DeleteBotInviteFlow), arg0, arg1)
+}
+
 // DeleteOauth2Flow mocks base method.
 func (m *MockStore) DeleteOauth2Flow(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -51,6 +94,49 @@ func (mr *MockStoreMockRecorder) DeleteOauth2Flow(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOauth2Flow", reflect.TypeOf((*MockStore)(nil).DeleteOauth2Flow), arg0, arg1)
 }
 
+// DeleteUserSessions mocks base method.
+func (m *MockStore) DeleteUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockStoreMockRecorder) DeleteUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockStore)(nil).DeleteUserSessions), arg0, arg1)
+}
+
+// DeleteVerificationChallenge mocks base method.
+func (m *MockStore) DeleteVerificationChallenge(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVerificationChallenge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVerificationChallenge indicates an expected call of DeleteVerificationChallenge.
+func (mr *MockStoreMockRecorder) DeleteVerificationChallenge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVerificationChallenge", reflect.TypeOf((*MockStore)(nil).DeleteVerificationChallenge), arg0, arg1, arg2)
+}
+
+// GetBotInviteFlow mocks base method.
+func (m *MockStore) GetBotInviteFlow(arg0 context.Context, arg1 string) (memdb.BotInviteFlow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBotInviteFlow", arg0, arg1)
+	ret0, _ := ret[0].(memdb.BotInviteFlow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBotInviteFlow indicates an expected call of GetBotInviteFlow.
+func (mr *MockStoreMockRecorder) GetBotInviteFlow(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotInviteFlow", reflect.TypeOf((*MockStore)(nil).GetBotInviteFlow), arg0, arg1)
+}
+
 // GetOauth2Flow mocks base method.
 func (m *MockStore) GetOauth2Flow(arg0 context.Context, arg1 string) (memdb.Oauth2Flow, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +167,94 @@ func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
 }
 
+// GetUserSessions mocks base method.
+func (m *MockStore) GetUserSessions(arg0 context.Context, arg1 string) ([]memdb.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSessions", arg0, arg1)
+	ret0, _ := ret[0].([]memdb.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSessions indicates an expected call of GetUserSessions.
+func (mr *MockStoreMockRecorder) GetUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSessions", reflect.TypeOf((*MockStore)(nil).GetUserSessions), arg0, arg1)
+}
+
+// GetVerificationChallenge mocks base method.
+func (m *MockStore) GetVerificationChallenge(arg0 context.Context, arg1, arg2 string) (memdb.VerificationChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerificationChallenge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(memdb.VerificationChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVerificationChallenge indicates an expected call of GetVerificationChallenge.
+func (mr *MockStoreMockRecorder) GetVerificationChallenge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerificationChallenge", reflect.TypeOf((*MockStore)(nil).GetVerificationChallenge), arg0, arg1, arg2)
+}
+
+// HitAutomodRate mocks base method.
+func (m *MockStore) HitAutomodRate(arg0 context.Context, arg1 string, arg2 time.Time, arg3 time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HitAutomodRate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HitAutomodRate indicates an expected call of HitAutomodRate.
+func (mr *MockStoreMockRecorder) HitAutomodRate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HitAutomodRate", reflect.TypeOf((*MockStore)(nil).HitAutomodRate), arg0, arg1, arg2, arg3)
+}
+
+// PublishBotCommand mocks base method.
+func (m *MockStore) PublishBotCommand(arg0 context.Context, arg1 memdb.BotCommand) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishBotCommand", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishBotCommand indicates an expected call of PublishBotCommand.
+func (mr *MockStoreMockRecorder) PublishBotCommand(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishBotCommand", reflect.TypeOf((*MockStore)(nil).PublishBotCommand), arg0, arg1)
+}
+
+// PublishGuildEvent mocks base method.
+func (m *MockStore) PublishGuildEvent(arg0 context.Context, arg1 memdb.GuildEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishGuildEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishGuildEvent indicates an expected call of PublishGuildEvent.
+func (mr *MockStoreMockRecorder) PublishGuildEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishGuildEvent", reflect.TypeOf((*MockStore)(nil).PublishGuildEvent), arg0, arg1)
+}
+
+// SetBotInviteFlow mocks base method.
+func (m *MockStore) SetBotInviteFlow(arg0 context.Context, arg1 string, arg2 memdb.BotInviteFlow, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBotInviteFlow", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBotInviteFlow indicates an expected call of SetBotInviteFlow.
+func (mr *MockStoreMockRecorder) SetBotInviteFlow(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBotInviteFlow", reflect.TypeOf((*MockStore)(nil).SetBotInviteFlow), arg0, arg1, arg2, arg3)
+}
+
 // SetOauth2Flow mocks base method.
 func (m *MockStore) SetOauth2Flow(arg0 context.Context, arg1 string, arg2 memdb.Oauth2Flow, arg3 time.Duration) error {
 	m.ctrl.T.Helper()
@@ -109,3 +283,63 @@ func (mr *MockStoreMockRecorder) SetSession(arg0, arg1, arg2 interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSession", reflect.TypeOf((*MockStore)(nil).SetSession), arg0, arg1, arg2)
 }
+
+// SetStreamTicket mocks base method.
+func (m *MockStore) SetStreamTicket(arg0 context.Context, arg1 string, arg2 memdb.StreamTicket, arg3 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStreamTicket", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStreamTicket indicates an expected call of SetStreamTicket.
+func (mr *MockStoreMockRecorder) SetStreamTicket(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStreamTicket", reflect.TypeOf((*MockStore)(nil).SetStreamTicket), arg0, arg1, arg2, arg3)
+}
+
+// SetVerificationChallenge mocks base method.
+func (m *MockStore) SetVerificationChallenge(arg0 context.Context, arg1, arg2 string, arg3 memdb.VerificationChallenge, arg4 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVerificationChallenge", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVerificationChallenge indicates an expected call of SetVerificationChallenge.
+func (mr *MockStoreMockRecorder) SetVerificationChallenge(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVerificationChallenge", reflect.TypeOf((*MockStore)(nil).SetVerificationChallenge), arg0, arg1, arg2, arg3, arg4)
+}
+
+// SubscribeBotCommands mocks base method.
+func (m *MockStore) SubscribeBotCommands(arg0 context.Context) (<-chan memdb.BotCommand, func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeBotCommands", arg0)
+	ret0, _ := ret[0].(<-chan memdb.BotCommand)
+	ret1, _ := ret[1].(func() error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SubscribeBotCommands indicates an expected call of SubscribeBotCommands.
+func (mr *MockStoreMockRecorder) SubscribeBotCommands(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBotCommands", reflect.TypeOf((*MockStore)(nil).SubscribeBotCommands), arg0)
+}
+
+// SubscribeGuildEvents mocks base method.
+func (m *MockStore) SubscribeGuildEvents(arg0 context.Context, arg1 string) (<-chan memdb.GuildEvent, func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeGuildEvents", arg0, arg1)
+	ret0, _ := ret[0].(<-chan memdb.GuildEvent)
+	ret1, _ := ret[1].(func() error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SubscribeGuildEvents indicates an expected call of SubscribeGuildEvents.
+func (mr *MockStoreMockRecorder) SubscribeGuildEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeGuildEvents", reflect.TypeOf((*MockStore)(nil).SubscribeGuildEvents), arg0, arg1)
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_guild;
+DROP TABLE IF EXISTS guild_config;
+DROP TABLE IF EXISTS guild;
+DROP TABLE IF EXISTS "user";
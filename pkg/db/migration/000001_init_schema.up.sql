@@ -0,0 +1,39 @@
+CREATE TABLE "user"
+(
+    id            bigserial PRIMARY KEY,
+    discord_id    varchar     NOT NULL UNIQUE,
+    username      varchar     NOT NULL,
+    discriminator varchar     NOT NULL,
+    verified      boolean     NOT NULL,
+    email         varchar     NOT NULL,
+    avatar        varchar     NOT NULL,
+    banner        varchar     NOT NULL,
+    accent_color  bigint      NOT NULL,
+    created_at    timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "user".accent_color IS 'color encoded as an integer representation of hexadecimal color code';
+
+CREATE TABLE guild
+(
+    id               bigserial PRIMARY KEY,
+    discord_id       varchar NOT NULL UNIQUE,
+    owner_discord_id varchar NOT NULL,
+    name             varchar NOT NULL,
+    icon             varchar NOT NULL
+);
+
+CREATE TABLE guild_config
+(
+    id         bigint PRIMARY KEY REFERENCES guild (id) ON DELETE CASCADE,
+    json       jsonb       NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE user_guild
+(
+    account_discord_id varchar NOT NULL REFERENCES "user" (discord_id) ON DELETE CASCADE,
+    guild_discord_id   varchar NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    permissions        bigint  NOT NULL,
+    PRIMARY KEY (guild_discord_id, account_discord_id)
+);
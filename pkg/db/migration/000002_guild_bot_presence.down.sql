@@ -0,0 +1,3 @@
+ALTER TABLE guild
+    DROP COLUMN IF EXISTS bot_joined_at,
+    DROP COLUMN IF EXISTS bot_present;
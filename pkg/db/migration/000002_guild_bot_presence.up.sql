@@ -0,0 +1,3 @@
+ALTER TABLE guild
+    ADD COLUMN bot_present   boolean NOT NULL DEFAULT false,
+    ADD COLUMN bot_joined_at timestamptz;
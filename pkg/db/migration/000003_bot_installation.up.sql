@@ -0,0 +1,10 @@
+CREATE TABLE bot_installation
+(
+    id                   bigserial PRIMARY KEY,
+    guild_discord_id     varchar     NOT NULL,
+    installer_discord_id varchar     NOT NULL REFERENCES "user" (discord_id) ON DELETE CASCADE,
+    permissions          bigint      NOT NULL,
+    installed_at         timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON bot_installation (guild_discord_id);
@@ -0,0 +1,12 @@
+CREATE TABLE api_key
+(
+    id           bigserial PRIMARY KEY,
+    name         varchar     NOT NULL,
+    prefix       varchar     NOT NULL UNIQUE,
+    hashed_key   bytea       NOT NULL,
+    scopes       text[]      NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT (now()),
+    expires_at   timestamptz,
+    revoked_at   timestamptz,
+    last_used_at timestamptz
+);
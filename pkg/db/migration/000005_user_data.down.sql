@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS audit_log;
+
+ALTER TABLE "user"
+    DROP COLUMN IF EXISTS last_login_at;
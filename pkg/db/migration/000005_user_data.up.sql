@@ -0,0 +1,19 @@
+ALTER TABLE "user"
+    ADD COLUMN last_login_at timestamptz NOT NULL DEFAULT (now());
+
+CREATE INDEX ON "user" (last_login_at);
+
+CREATE TABLE audit_log
+(
+    id               bigserial PRIMARY KEY,
+    actor_discord_id varchar,
+    guild_discord_id varchar     NOT NULL,
+    action           varchar     NOT NULL,
+    data             jsonb       NOT NULL DEFAULT ('{}'),
+    created_at       timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN audit_log.actor_discord_id IS 'null if the actor deleted the account';
+
+CREATE INDEX ON audit_log (actor_discord_id);
+CREATE INDEX ON audit_log (guild_discord_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE guild
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS orphaned_at;
@@ -0,0 +1,9 @@
+ALTER TABLE guild
+    ADD COLUMN orphaned_at timestamptz,
+    ADD COLUMN archived_at timestamptz;
+
+COMMENT ON COLUMN guild.owner_discord_id IS 'empty if the current owner is unknown';
+COMMENT ON COLUMN guild.orphaned_at IS 'set when neither the owner nor any user relation of the guild is left';
+
+CREATE INDEX ON guild (orphaned_at);
+CREATE INDEX ON guild (archived_at);
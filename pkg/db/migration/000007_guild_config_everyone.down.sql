@@ -0,0 +1,6 @@
+UPDATE guild_config
+SET json = jsonb_set(json #- '{permissions,everyone_can_read}', '{permissions,read}', '17592186044415')
+WHERE (json -> 'permissions' ->> 'everyone_can_read')::boolean;
+
+UPDATE guild_config
+SET json = json #- '{permissions,everyone_can_read}';
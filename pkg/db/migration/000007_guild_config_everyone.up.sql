@@ -0,0 +1,4 @@
+-- read permissions of 0xfffffffffff used to mean @everyone
+UPDATE guild_config
+SET json = jsonb_set(jsonb_set(json, '{permissions,everyone_can_read}', 'true'), '{permissions,read}', '0')
+WHERE (json -> 'permissions' ->> 'read')::bigint = 17592186044415;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS moderation_case;
+DROP TABLE IF EXISTS moderation_case_counter;
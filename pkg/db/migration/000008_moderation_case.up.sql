@@ -0,0 +1,26 @@
+CREATE TABLE moderation_case_counter
+(
+    guild_discord_id varchar PRIMARY KEY REFERENCES guild (discord_id) ON DELETE CASCADE,
+    last_case_number bigint NOT NULL
+);
+
+CREATE TABLE moderation_case
+(
+    id                   bigserial PRIMARY KEY,
+    guild_discord_id     varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    case_number          bigint      NOT NULL,
+    action               varchar     NOT NULL CHECK (action IN ('warn', 'mute', 'kick', 'ban', 'unban')),
+    target_discord_id    varchar     NOT NULL,
+    moderator_discord_id varchar     NOT NULL,
+    reason               varchar     NOT NULL DEFAULT (''),
+    duration_seconds     bigint,
+    expires_at           timestamptz,
+    created_at           timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE (guild_discord_id, case_number)
+);
+
+COMMENT ON COLUMN moderation_case.case_number IS 'sequential number of the case within the guild';
+COMMENT ON COLUMN moderation_case.duration_seconds IS 'null for permanent or instant actions';
+
+CREATE INDEX ON moderation_case (guild_discord_id, target_discord_id);
+CREATE INDEX ON moderation_case (guild_discord_id, moderator_discord_id);
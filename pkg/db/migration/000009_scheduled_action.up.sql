@@ -0,0 +1,18 @@
+CREATE TABLE scheduled_action
+(
+    id                bigserial PRIMARY KEY,
+    guild_discord_id  varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    case_id           bigint      NOT NULL UNIQUE REFERENCES moderation_case (id) ON DELETE CASCADE,
+    action            varchar     NOT NULL CHECK (action IN ('unmute', 'unban')),
+    target_discord_id varchar     NOT NULL,
+    run_at            timestamptz NOT NULL,
+    status            varchar     NOT NULL DEFAULT ('pending') CHECK (status IN ('pending', 'done', 'cancelled')),
+    attempts          int         NOT NULL DEFAULT (0),
+    completed_at      timestamptz,
+    created_at        timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN scheduled_action.attempts IS 'dispatches postponed because no bot was listening';
+
+CREATE INDEX ON scheduled_action (run_at) WHERE status = 'pending';
+CREATE INDEX ON scheduled_action (guild_discord_id, target_discord_id) WHERE status = 'pending';
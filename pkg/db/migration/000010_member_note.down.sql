@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS member_note_revision;
+DROP TABLE IF EXISTS member_note;
+DROP TABLE IF EXISTS guild_member;
@@ -0,0 +1,36 @@
+CREATE TABLE guild_member
+(
+    guild_discord_id  varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    member_discord_id varchar     NOT NULL,
+    first_seen_at     timestamptz NOT NULL,
+    last_seen_at      timestamptz NOT NULL,
+    PRIMARY KEY (guild_discord_id, member_discord_id)
+);
+
+CREATE TABLE member_note
+(
+    id                bigserial PRIMARY KEY,
+    guild_discord_id  varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    member_discord_id varchar     NOT NULL,
+    author_discord_id varchar     NOT NULL,
+    content           varchar     NOT NULL,
+    created_at        timestamptz NOT NULL DEFAULT (now()),
+    updated_at        timestamptz NOT NULL DEFAULT (now()),
+    deleted_at        timestamptz
+);
+
+COMMENT ON COLUMN member_note.deleted_at IS 'deleted notes are kept for their revision history';
+
+CREATE INDEX ON member_note (guild_discord_id, member_discord_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE member_note_revision
+(
+    id                bigserial PRIMARY KEY,
+    note_id           bigint      NOT NULL REFERENCES member_note (id) ON DELETE CASCADE,
+    action            varchar     NOT NULL CHECK (action IN ('create', 'edit', 'delete')),
+    content           varchar     NOT NULL,
+    editor_discord_id varchar     NOT NULL,
+    created_at        timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON member_note_revision (note_id);
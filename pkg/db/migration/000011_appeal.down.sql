@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS appeal_comment;
+DROP TABLE IF EXISTS appeal;
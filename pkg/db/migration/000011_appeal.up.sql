@@ -0,0 +1,30 @@
+CREATE TABLE appeal
+(
+    id                  bigserial PRIMARY KEY,
+    guild_discord_id    varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    case_id             bigint      NOT NULL REFERENCES moderation_case (id) ON DELETE CASCADE,
+    user_discord_id     varchar     NOT NULL,
+    answers             jsonb       NOT NULL,
+    status              varchar     NOT NULL DEFAULT ('pending') CHECK (status IN ('pending', 'accepted', 'denied')),
+    reviewer_discord_id varchar,
+    decision_reason     varchar     NOT NULL DEFAULT (''),
+    decided_at          timestamptz,
+    created_at          timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN appeal.case_id IS 'the ban case being appealed';
+
+CREATE UNIQUE INDEX ON appeal (case_id) WHERE status = 'pending';
+CREATE INDEX ON appeal (guild_discord_id, status);
+CREATE INDEX ON appeal (user_discord_id);
+
+CREATE TABLE appeal_comment
+(
+    id                bigserial PRIMARY KEY,
+    appeal_id         bigint      NOT NULL REFERENCES appeal (id) ON DELETE CASCADE,
+    author_discord_id varchar     NOT NULL,
+    content           varchar     NOT NULL,
+    created_at        timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON appeal_comment (appeal_id);
@@ -0,0 +1,7 @@
+CREATE TABLE verified_member
+(
+    guild_discord_id  varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    member_discord_id varchar     NOT NULL,
+    verified_at       timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY (guild_discord_id, member_discord_id)
+);
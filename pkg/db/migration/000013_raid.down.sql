@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS raid_incident;
+DROP TABLE IF EXISTS member_event;
@@ -0,0 +1,30 @@
+CREATE TABLE member_event
+(
+    id                 bigserial PRIMARY KEY,
+    guild_discord_id   varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    type               varchar     NOT NULL CHECK (type IN ('join', 'leave')),
+    member_discord_id  varchar     NOT NULL,
+    username           varchar     NOT NULL DEFAULT (''),
+    account_created_at timestamptz NOT NULL,
+    occurred_at        timestamptz NOT NULL
+);
+
+CREATE INDEX ON member_event (guild_discord_id, occurred_at);
+
+CREATE TABLE raid_incident
+(
+    id                     bigserial PRIMARY KEY,
+    guild_discord_id       varchar     NOT NULL REFERENCES guild (discord_id) ON DELETE CASCADE,
+    status                 varchar     NOT NULL DEFAULT ('open') CHECK (status IN ('open', 'resolved')),
+    detection              jsonb       NOT NULL,
+    lockdown_requested     boolean     NOT NULL DEFAULT (false),
+    started_at             timestamptz NOT NULL DEFAULT (now()),
+    last_detected_at       timestamptz NOT NULL DEFAULT (now()),
+    resolved_by_discord_id varchar,
+    resolved_at            timestamptz
+);
+
+COMMENT ON COLUMN raid_incident.detection IS 'the latest detection of the incident';
+COMMENT ON COLUMN raid_incident.resolved_by_discord_id IS 'null when the incident was closed after going quiet';
+
+CREATE UNIQUE INDEX ON raid_incident (guild_discord_id) WHERE status = 'open';
@@ -0,0 +1,15 @@
+CREATE TABLE guild_lockdown
+(
+    guild_discord_id    varchar     PRIMARY KEY REFERENCES guild (discord_id) ON DELETE CASCADE,
+    scope               varchar     NOT NULL CHECK (scope IN ('all', 'channels')),
+    channel_discord_ids text[]      NOT NULL DEFAULT ('{}'),
+    reason              varchar     NOT NULL,
+    actor_discord_id    varchar,
+    incident_id         bigint      REFERENCES raid_incident (id) ON DELETE SET NULL,
+    lifts_at            timestamptz,
+    created_at          timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN guild_lockdown.actor_discord_id IS 'null when the lockdown was raised by raid detection';
+
+CREATE INDEX ON guild_lockdown (lifts_at) WHERE lifts_at IS NOT NULL;
@@ -0,0 +1,8 @@
+UPDATE scheduled_action
+SET status = 'pending'
+WHERE status = 'dispatched';
+
+ALTER TABLE scheduled_action
+    DROP COLUMN IF EXISTS dispatched_at,
+    DROP CONSTRAINT scheduled_action_status_check,
+    ADD CONSTRAINT scheduled_action_status_check CHECK (status IN ('pending', 'done', 'cancelled'));
@@ -0,0 +1,8 @@
+ALTER TABLE scheduled_action
+    DROP CONSTRAINT scheduled_action_status_check,
+    ADD CONSTRAINT scheduled_action_status_check CHECK (status IN ('pending', 'dispatched', 'done', 'cancelled')),
+    ADD COLUMN dispatched_at timestamptz;
+
+COMMENT ON COLUMN scheduled_action.dispatched_at IS 'dispatched actions are delivered again unless the bot acknowledges them in time';
+
+CREATE INDEX ON scheduled_action (dispatched_at) WHERE status = 'dispatched';
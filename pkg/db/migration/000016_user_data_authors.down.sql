@@ -0,0 +1,24 @@
+DROP INDEX IF EXISTS appeal_comment_author_discord_id_idx;
+DROP INDEX IF EXISTS member_note_author_discord_id_idx;
+DROP INDEX IF EXISTS member_note_revision_editor_discord_id_idx;
+
+DELETE
+FROM appeal_comment
+WHERE author_discord_id IS NULL;
+
+DELETE
+FROM member_note_revision
+WHERE editor_discord_id IS NULL;
+
+DELETE
+FROM member_note
+WHERE author_discord_id IS NULL;
+
+ALTER TABLE appeal_comment
+    ALTER COLUMN author_discord_id SET NOT NULL;
+
+ALTER TABLE member_note
+    ALTER COLUMN author_discord_id SET NOT NULL;
+
+ALTER TABLE member_note_revision
+    ALTER COLUMN editor_discord_id SET NOT NULL;
@@ -0,0 +1,16 @@
+ALTER TABLE appeal_comment
+    ALTER COLUMN author_discord_id DROP NOT NULL;
+
+ALTER TABLE member_note
+    ALTER COLUMN author_discord_id DROP NOT NULL;
+
+ALTER TABLE member_note_revision
+    ALTER COLUMN editor_discord_id DROP NOT NULL;
+
+COMMENT ON COLUMN appeal_comment.author_discord_id IS 'null if the author deleted the account';
+COMMENT ON COLUMN member_note.author_discord_id IS 'null if the author deleted the account';
+COMMENT ON COLUMN member_note_revision.editor_discord_id IS 'null if the editor deleted the account';
+
+CREATE INDEX ON appeal_comment (author_discord_id);
+CREATE INDEX ON member_note (author_discord_id);
+CREATE INDEX ON member_note_revision (editor_discord_id);
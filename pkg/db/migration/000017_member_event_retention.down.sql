@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS member_event_occurred_at_idx;
+DROP INDEX IF EXISTS member_event_member_discord_id_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX ON member_event (occurred_at);
+CREATE INDEX ON member_event (member_discord_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS verified_member_member_discord_id_idx;
@@ -0,0 +1 @@
+CREATE INDEX ON verified_member (member_discord_id);
@@ -0,0 +1 @@
+COMMENT ON COLUMN raid_incident.resolved_by_discord_id IS 'null when the incident was closed after going quiet or the resolver deleted the account';
@@ -0,0 +1 @@
+COMMENT ON COLUMN guild_lockdown.actor_discord_id IS 'null when the lockdown was raised by raid detection';
@@ -0,0 +1,4 @@
+ALTER TABLE api_key
+    ADD COLUMN guild_discord_ids text[] NOT NULL DEFAULT ('{}');
+
+COMMENT ON COLUMN api_key.guild_discord_ids IS 'guilds the key may act in, empty means every guild';
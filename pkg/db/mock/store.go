@@ -6,7 +6,9 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
 	gomock "github.com/golang/mock/gomock"
@@ -35,138 +37,1054 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AckScheduledAction mocks base method.
+func (m *MockStore) AckScheduledAction(arg0 context.Context, arg1 int64) (db.ScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AckScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AckScheduledAction indicates an expected call of AckScheduledAction.
+func (mr *MockStoreMockRecorder) AckScheduledAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckScheduledAction", reflect.TypeOf((*MockStore)(nil).AckScheduledAction), arg0, arg1)
+}
+
+// AnonymizeAppealComments mocks base method.
+func (m *MockStore) AnonymizeAppealComments(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeAppealComments", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeAppealComments indicates an expected call of AnonymizeAppealComments.
+func (mr *MockStoreMockRecorder) AnonymizeAppealComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeAppealComments", reflect.TypeOf((*MockStore)(nil).AnonymizeAppealComments), arg0, arg1)
+}
+
+// AnonymizeAppealReviewers mocks base method.
+func (m *MockStore) AnonymizeAppealReviewers(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeAppealReviewers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeAppealReviewers indicates an expected call of AnonymizeAppealReviewers.
+func (mr *MockStoreMockRecorder) AnonymizeAppealReviewers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeAppealReviewers", reflect.TypeOf((*MockStore)(nil).AnonymizeAppealReviewers), arg0, arg1)
+}
+
+// AnonymizeAuditLogs mocks base method.
+func (m *MockStore) AnonymizeAuditLogs(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeAuditLogs indicates an expected call of AnonymizeAuditLogs.
+func (mr *MockStoreMockRecorder) AnonymizeAuditLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeAuditLogs", reflect.TypeOf((*MockStore)(nil).AnonymizeAuditLogs), arg0, arg1)
+}
+
+// AnonymizeGuildLockdownActors mocks base method.
+func (m *MockStore) AnonymizeGuildLockdownActors(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeGuildLockdownActors", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeGuildLockdownActors indicates an expected call of AnonymizeGuildLockdownActors.
+func (mr *MockStoreMockRecorder) AnonymizeGuildLockdownActors(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeGuildLockdownActors", reflect.TypeOf((*MockStore)(nil).AnonymizeGuildLockdownActors), arg0, arg1)
+}
+
+// AnonymizeMemberNoteRevisions mocks base method.
+func (m *MockStore) AnonymizeMemberNoteRevisions(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeMemberNoteRevisions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeMemberNoteRevisions indicates an expected call of AnonymizeMemberNoteRevisions.
+func (mr *MockStoreMockRecorder) AnonymizeMemberNoteRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeMemberNoteRevisions", reflect.TypeOf((*MockStore)(nil).AnonymizeMemberNoteRevisions), arg0, arg1)
+}
+
+// AnonymizeMemberNotes mocks base method.
+func (m *MockStore) AnonymizeMemberNotes(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeMemberNotes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeMemberNotes indicates an expected call of AnonymizeMemberNotes.
+func (mr *MockStoreMockRecorder) AnonymizeMemberNotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeMemberNotes", reflect.TypeOf((*MockStore)(nil).AnonymizeMemberNotes), arg0, arg1)
+}
+
+// AnonymizeRaidIncidentResolvers mocks base method.
+func (m *MockStore) AnonymizeRaidIncidentResolvers(arg0 context.Context, arg1 sql.NullString) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeRaidIncidentResolvers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeRaidIncidentResolvers indicates an expected call of AnonymizeRaidIncidentResolvers.
+func (mr *MockStoreMockRecorder) AnonymizeRaidIncidentResolvers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeRaidIncidentResolvers", reflect.TypeOf((*MockStore)(nil).AnonymizeRaidIncidentResolvers), arg0, arg1)
+}
+
+// ArchiveOrphanedGuilds mocks base method.
+func (m *MockStore) ArchiveOrphanedGuilds(arg0 context.Context, arg1 sql.NullTime) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveOrphanedGuilds", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveOrphanedGuilds indicates an expected call of ArchiveOrphanedGuilds.
+func (mr *MockStoreMockRecorder) ArchiveOrphanedGuilds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveOrphanedGuilds", reflect.TypeOf((*MockStore)(nil).ArchiveOrphanedGuilds), arg0, arg1)
+}
+
+// CancelCaseScheduledAction mocks base method.
+func (m *MockStore) CancelCaseScheduledAction(arg0 context.Context, arg1 int64) (db.ScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelCaseScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelCaseScheduledAction indicates an expected call of CancelCaseScheduledAction.
+func (mr *MockStoreMockRecorder) CancelCaseScheduledAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelCaseScheduledAction", reflect.TypeOf((*MockStore)(nil).CancelCaseScheduledAction), arg0, arg1)
+}
+
+// CancelTargetScheduledActions mocks base method.
+func (m *MockStore) CancelTargetScheduledActions(arg0 context.Context, arg1 db.CancelTargetScheduledActionsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTargetScheduledActions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTargetScheduledActions indicates an expected call of CancelTargetScheduledActions.
+func (mr *MockStoreMockRecorder) CancelTargetScheduledActions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTargetScheduledActions", reflect.TypeOf((*MockStore)(nil).CancelTargetScheduledActions), arg0, arg1)
+}
+
+// ClaimDueScheduledActions mocks base method.
+func (m *MockStore) ClaimDueScheduledActions(arg0 context.Context, arg1 db.ClaimDueScheduledActionsParams) ([]db.ScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueScheduledActions", arg0, arg1)
+	ret0, _ := ret[0].([]db.ScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueScheduledActions indicates an expected call of ClaimDueScheduledActions.
+func (mr *MockStoreMockRecorder) ClaimDueScheduledActions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueScheduledActions", reflect.TypeOf((*MockStore)(nil).ClaimDueScheduledActions), arg0, arg1)
+}
+
+// CreateApiKey mocks base method.
+func (m *MockStore) CreateApiKey(arg0 context.Context, arg1 db.CreateApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockStoreMockRecorder) CreateApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockStore)(nil).CreateApiKey), arg0, arg1)
+}
+
+// CreateAppeal mocks base method.
+func (m *MockStore) CreateAppeal(arg0 context.Context, arg1 db.CreateAppealParams) (db.Appeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppeal", arg0, arg1)
+	ret0, _ := ret[0].(db.Appeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppeal indicates an expected call of CreateAppeal.
+func (mr *MockStoreMockRecorder) CreateAppeal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppeal", reflect.TypeOf((*MockStore)(nil).CreateAppeal), arg0, arg1)
+}
+
+// CreateAppealComment mocks base method.
+func (m *MockStore) CreateAppealComment(arg0 context.Context, arg1 db.CreateAppealCommentParams) (db.AppealComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppealComment", arg0, arg1)
+	ret0, _ := ret[0].(db.AppealComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAppealComment indicates an expected call of CreateAppealComment.
+func (mr *MockStoreMockRecorder) CreateAppealComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppealComment", reflect.TypeOf((*MockStore)(nil).CreateAppealComment), arg0, arg1)
+}
+
+// CreateAuditLog mocks base method.
+func (m *MockStore) CreateAuditLog(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockStoreMockRecorder) CreateAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockStore)(nil).CreateAuditLog), arg0, arg1)
+}
+
+// CreateBotInstallation mocks base method.
+func (m *MockStore) CreateBotInstallation(arg0 context.Context, arg1 db.CreateBotInstallationParams) (db.BotInstallation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBotInstallation", arg0, arg1)
+	ret0, _ := ret[0].(db.BotInstallation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBotInstallation indicates an expected call of CreateBotInstallation.
+func (mr *MockStoreMockRecorder) CreateBotInstallation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBotInstallation", reflect.TypeOf((*MockStore)(nil).CreateBotInstallation), arg0, arg1)
+}
+
+// CreateIncidentGuildLockdown mocks base method.
+func (m *MockStore) CreateIncidentGuildLockdown(arg0 context.Context, arg1 db.CreateIncidentGuildLockdownParams) (db.GuildLockdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIncidentGuildLockdown", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildLockdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIncidentGuildLockdown indicates an expected call of CreateIncidentGuildLockdown.
+func (mr *MockStoreMockRecorder) CreateIncidentGuildLockdown(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncidentGuildLockdown", reflect.TypeOf((*MockStore)(nil).CreateIncidentGuildLockdown), arg0, arg1)
+}
+
+// CreateMemberEvent mocks base method.
+func (m *MockStore) CreateMemberEvent(arg0 context.Context, arg1 db.CreateMemberEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMemberEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMemberEvent indicates an expected call of CreateMemberEvent.
+func (mr *MockStoreMockRecorder) CreateMemberEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMemberEvent", reflect.TypeOf((*MockStore)(nil).CreateMemberEvent), arg0, arg1)
+}
+
+// CreateMemberNote mocks base method.
+func (m *MockStore) CreateMemberNote(arg0 context.Context, arg1 db.CreateMemberNoteParams) (db.MemberNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMemberNote", arg0, arg1)
+	ret0, _ := ret[0].(db.MemberNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMemberNote indicates an expected call of CreateMemberNote.
+func (mr *MockStoreMockRecorder) CreateMemberNote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMemberNote", reflect.TypeOf((*MockStore)(nil).CreateMemberNote), arg0, arg1)
+}
+
+// CreateMemberNoteRevision mocks base method.
+func (m *MockStore) CreateMemberNoteRevision(arg0 context.Context, arg1 db.CreateMemberNoteRevisionParams) (db.MemberNoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMemberNoteRevision", arg0, arg1)
+	ret0, _ := ret[0].(db.MemberNoteRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMemberNoteRevision indicates an expected call of CreateMemberNoteRevision.
+func (mr *MockStoreMockRecorder) CreateMemberNoteRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMemberNoteRevision", reflect.TypeOf((*MockStore)(nil).CreateMemberNoteRevision), arg0, arg1)
+}
+
+// CreateModerationCase mocks base method.
+func (m *MockStore) CreateModerationCase(arg0 context.Context, arg1 db.CreateModerationCaseParams) (db.ModerationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModerationCase", arg0, arg1)
+	ret0, _ := ret[0].(db.ModerationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateModerationCase indicates an expected call of CreateModerationCase.
+func (mr *MockStoreMockRecorder) CreateModerationCase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModerationCase", reflect.TypeOf((*MockStore)(nil).CreateModerationCase), arg0, arg1)
+}
+
 // CreateOrUpdateGuild mocks base method.
 func (m *MockStore) CreateOrUpdateGuild(arg0 context.Context, arg1 db.CreateOrUpdateGuildParams) (db.Guild, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateGuild", arg0, arg1)
-	ret0, _ := ret[0].(db.Guild)
+	ret := m.ctrl.Call(m, "CreateOrUpdateGuild", arg0, arg1)
+	ret0, _ := ret[0].(db.Guild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateGuild indicates an expected call of CreateOrUpdateGuild.
+func (mr *MockStoreMockRecorder) CreateOrUpdateGuild(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateGuild", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateGuild), arg0, arg1)
+}
+
+// CreateOrUpdateGuildConfig mocks base method.
+func (m *MockStore) CreateOrUpdateGuildConfig(arg0 context.Context, arg1 db.CreateOrUpdateGuildConfigParams) (db.GuildConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateGuildConfig", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateGuildConfig indicates an expected call of CreateOrUpdateGuildConfig.
+func (mr *MockStoreMockRecorder) CreateOrUpdateGuildConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateGuildConfig", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateGuildConfig), arg0, arg1)
+}
+
+// CreateOrUpdateUser mocks base method.
+func (m *MockStore) CreateOrUpdateUser(arg0 context.Context, arg1 db.CreateOrUpdateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateUser indicates an expected call of CreateOrUpdateUser.
+func (mr *MockStoreMockRecorder) CreateOrUpdateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateUser", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateUser), arg0, arg1)
+}
+
+// CreateOrUpdateUserGuildRel mocks base method.
+func (m *MockStore) CreateOrUpdateUserGuildRel(arg0 context.Context, arg1 db.CreateOrUpdateUserGuildRelParams) (db.UserGuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateUserGuildRel", arg0, arg1)
+	ret0, _ := ret[0].(db.UserGuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateUserGuildRel indicates an expected call of CreateOrUpdateUserGuildRel.
+func (mr *MockStoreMockRecorder) CreateOrUpdateUserGuildRel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateUserGuildRel", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateUserGuildRel), arg0, arg1)
+}
+
+// CreateRaidIncident mocks base method.
+func (m *MockStore) CreateRaidIncident(arg0 context.Context, arg1 db.CreateRaidIncidentParams) (db.RaidIncident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRaidIncident", arg0, arg1)
+	ret0, _ := ret[0].(db.RaidIncident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRaidIncident indicates an expected call of CreateRaidIncident.
+func (mr *MockStoreMockRecorder) CreateRaidIncident(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRaidIncident", reflect.TypeOf((*MockStore)(nil).CreateRaidIncident), arg0, arg1)
+}
+
+// CreateUserGuildRel mocks base method.
+func (m *MockStore) CreateUserGuildRel(arg0 context.Context, arg1 db.CreateUserGuildRelParams) (db.UserGuild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserGuildRel", arg0, arg1)
+	ret0, _ := ret[0].(db.UserGuild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserGuildRel indicates an expected call of CreateUserGuildRel.
+func (mr *MockStoreMockRecorder) CreateUserGuildRel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserGuildRel", reflect.TypeOf((*MockStore)(nil).CreateUserGuildRel), arg0, arg1)
+}
+
+// CreateVerifiedMember mocks base method.
+func (m *MockStore) CreateVerifiedMember(arg0 context.Context, arg1 db.CreateVerifiedMemberParams) (db.VerifiedMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifiedMember", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifiedMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifiedMember indicates an expected call of CreateVerifiedMember.
+func (mr *MockStoreMockRecorder) CreateVerifiedMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifiedMember", reflect.TypeOf((*MockStore)(nil).CreateVerifiedMember), arg0, arg1)
+}
+
+// DecideAppeal mocks base method.
+func (m *MockStore) DecideAppeal(arg0 context.Context, arg1 db.DecideAppealParams) (db.Appeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideAppeal", arg0, arg1)
+	ret0, _ := ret[0].(db.Appeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideAppeal indicates an expected call of DecideAppeal.
+func (mr *MockStoreMockRecorder) DecideAppeal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideAppeal", reflect.TypeOf((*MockStore)(nil).DecideAppeal), arg0, arg1)
+}
+
+// DeleteArchivedGuilds mocks base method.
+func (m *MockStore) DeleteArchivedGuilds(arg0 context.Context, arg1 sql.NullTime) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteArchivedGuilds", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteArchivedGuilds indicates an expected call of DeleteArchivedGuilds.
+func (mr *MockStoreMockRecorder) DeleteArchivedGuilds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteArchivedGuilds", reflect.TypeOf((*MockStore)(nil).DeleteArchivedGuilds), arg0, arg1)
+}
+
+// DeleteExpiredGuildLockdowns mocks base method.
+func (m *MockStore) DeleteExpiredGuildLockdowns(arg0 context.Context, arg1 db.DeleteExpiredGuildLockdownsParams) ([]db.GuildLockdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredGuildLockdowns", arg0, arg1)
+	ret0, _ := ret[0].([]db.GuildLockdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredGuildLockdowns indicates an expected call of DeleteExpiredGuildLockdowns.
+func (mr *MockStoreMockRecorder) DeleteExpiredGuildLockdowns(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredGuildLockdowns", reflect.TypeOf((*MockStore)(nil).DeleteExpiredGuildLockdowns), arg0, arg1)
+}
+
+// DeleteGuildLockdown mocks base method.
+func (m *MockStore) DeleteGuildLockdown(arg0 context.Context, arg1 string) (db.GuildLockdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGuildLockdown", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildLockdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteGuildLockdown indicates an expected call of DeleteGuildLockdown.
+func (mr *MockStoreMockRecorder) DeleteGuildLockdown(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGuildLockdown", reflect.TypeOf((*MockStore)(nil).DeleteGuildLockdown), arg0, arg1)
+}
+
+// DeleteMemberEventsBefore mocks base method.
+func (m *MockStore) DeleteMemberEventsBefore(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMemberEventsBefore", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMemberEventsBefore indicates an expected call of DeleteMemberEventsBefore.
+func (mr *MockStoreMockRecorder) DeleteMemberEventsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMemberEventsBefore", reflect.TypeOf((*MockStore)(nil).DeleteMemberEventsBefore), arg0, arg1)
+}
+
+// DeleteMemberNote mocks base method.
+func (m *MockStore) DeleteMemberNote(arg0 context.Context, arg1 db.DeleteMemberNoteParams) (db.MemberNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMemberNote", arg0, arg1)
+	ret0, _ := ret[0].(db.MemberNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMemberNote indicates an expected call of DeleteMemberNote.
+func (mr *MockStoreMockRecorder) DeleteMemberNote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMemberNote", reflect.TypeOf((*MockStore)(nil).DeleteMemberNote), arg0, arg1)
+}
+
+// DeleteStaleUserGuildRels mocks base method.
+func (m *MockStore) DeleteStaleUserGuildRels(arg0 context.Context, arg1 db.DeleteStaleUserGuildRelsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleUserGuildRels", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleUserGuildRels indicates an expected call of DeleteStaleUserGuildRels.
+func (mr *MockStoreMockRecorder) DeleteStaleUserGuildRels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleUserGuildRels", reflect.TypeOf((*MockStore)(nil).DeleteStaleUserGuildRels), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStore) DeleteUser(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteUserAppeals mocks base method.
+func (m *MockStore) DeleteUserAppeals(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserAppeals", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserAppeals indicates an expected call of DeleteUserAppeals.
+func (mr *MockStoreMockRecorder) DeleteUserAppeals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserAppeals", reflect.TypeOf((*MockStore)(nil).DeleteUserAppeals), arg0, arg1)
+}
+
+// DeleteUserGuildRel mocks base method.
+func (m *MockStore) DeleteUserGuildRel(arg0 context.Context, arg1 db.DeleteUserGuildRelParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGuildRel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserGuildRel indicates an expected call of DeleteUserGuildRel.
+func (mr *MockStoreMockRecorder) DeleteUserGuildRel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGuildRel", reflect.TypeOf((*MockStore)(nil).DeleteUserGuildRel), arg0, arg1)
+}
+
+// DeleteUserGuildRels mocks base method.
+func (m *MockStore) DeleteUserGuildRels(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserGuildRels", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserGuildRels indicates an expected call of DeleteUserGuildRels.
+func (mr *MockStoreMockRecorder) DeleteUserGuildRels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserGuildRels", reflect.TypeOf((*MockStore)(nil).DeleteUserGuildRels), arg0, arg1)
+}
+
+// DeleteUserMemberEvents mocks base method.
+func (m *MockStore) DeleteUserMemberEvents(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserMemberEvents", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserMemberEvents indicates an expected call of DeleteUserMemberEvents.
+func (mr *MockStoreMockRecorder) DeleteUserMemberEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserMemberEvents", reflect.TypeOf((*MockStore)(nil).DeleteUserMemberEvents), arg0, arg1)
+}
+
+// DeleteUserVerifiedMembers mocks base method.
+func (m *MockStore) DeleteUserVerifiedMembers(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserVerifiedMembers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserVerifiedMembers indicates an expected call of DeleteUserVerifiedMembers.
+func (mr *MockStoreMockRecorder) DeleteUserVerifiedMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserVerifiedMembers", reflect.TypeOf((*MockStore)(nil).DeleteUserVerifiedMembers), arg0, arg1)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(arg0 context.Context, arg1 func(*db.Queries) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), arg0, arg1)
+}
+
+// FlagOrphanedGuilds mocks base method.
+func (m *MockStore) FlagOrphanedGuilds(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagOrphanedGuilds", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlagOrphanedGuilds indicates an expected call of FlagOrphanedGuilds.
+func (mr *MockStoreMockRecorder) FlagOrphanedGuilds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagOrphanedGuilds", reflect.TypeOf((*MockStore)(nil).FlagOrphanedGuilds), arg0)
+}
+
+// GetApiKeyByPrefix mocks base method.
+func (m *MockStore) GetApiKeyByPrefix(arg0 context.Context, arg1 string) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeyByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKeyByPrefix indicates an expected call of GetApiKeyByPrefix.
+func (mr *MockStoreMockRecorder) GetApiKeyByPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeyByPrefix", reflect.TypeOf((*MockStore)(nil).GetApiKeyByPrefix), arg0, arg1)
+}
+
+// GetApiKeys mocks base method.
+func (m *MockStore) GetApiKeys(arg0 context.Context) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeys", arg0)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKeys indicates an expected call of GetApiKeys.
+func (mr *MockStoreMockRecorder) GetApiKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeys", reflect.TypeOf((*MockStore)(nil).GetApiKeys), arg0)
+}
+
+// GetAppeal mocks base method.
+func (m *MockStore) GetAppeal(arg0 context.Context, arg1 db.GetAppealParams) (db.Appeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppeal", arg0, arg1)
+	ret0, _ := ret[0].(db.Appeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppeal indicates an expected call of GetAppeal.
+func (mr *MockStoreMockRecorder) GetAppeal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppeal", reflect.TypeOf((*MockStore)(nil).GetAppeal), arg0, arg1)
+}
+
+// GetAppealComments mocks base method.
+func (m *MockStore) GetAppealComments(arg0 context.Context, arg1 int64) ([]db.AppealComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppealComments", arg0, arg1)
+	ret0, _ := ret[0].([]db.AppealComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppealComments indicates an expected call of GetAppealComments.
+func (mr *MockStoreMockRecorder) GetAppealComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppealComments", reflect.TypeOf((*MockStore)(nil).GetAppealComments), arg0, arg1)
+}
+
+// GetAuthoredMemberNotes mocks base method.
+func (m *MockStore) GetAuthoredMemberNotes(arg0 context.Context, arg1 sql.NullString) ([]db.MemberNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthoredMemberNotes", arg0, arg1)
+	ret0, _ := ret[0].([]db.MemberNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthoredMemberNotes indicates an expected call of GetAuthoredMemberNotes.
+func (mr *MockStoreMockRecorder) GetAuthoredMemberNotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthoredMemberNotes", reflect.TypeOf((*MockStore)(nil).GetAuthoredMemberNotes), arg0, arg1)
+}
+
+// GetGuild mocks base method.
+func (m *MockStore) GetGuild(arg0 context.Context, arg1 string) (db.GetGuildRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuild", arg0, arg1)
+	ret0, _ := ret[0].(db.GetGuildRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuild indicates an expected call of GetGuild.
+func (mr *MockStoreMockRecorder) GetGuild(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuild", reflect.TypeOf((*MockStore)(nil).GetGuild), arg0, arg1)
+}
+
+// GetGuildAppeals mocks base method.
+func (m *MockStore) GetGuildAppeals(arg0 context.Context, arg1 db.GetGuildAppealsParams) ([]db.Appeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuildAppeals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Appeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuildAppeals indicates an expected call of GetGuildAppeals.
+func (mr *MockStoreMockRecorder) GetGuildAppeals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildAppeals", reflect.TypeOf((*MockStore)(nil).GetGuildAppeals), arg0, arg1)
+}
+
+// GetGuildConfig mocks base method.
+func (m *MockStore) GetGuildConfig(arg0 context.Context, arg1 string) (db.GuildConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuildConfig", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuildConfig indicates an expected call of GetGuildConfig.
+func (mr *MockStoreMockRecorder) GetGuildConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildConfig", reflect.TypeOf((*MockStore)(nil).GetGuildConfig), arg0, arg1)
+}
+
+// GetGuildLockdown mocks base method.
+func (m *MockStore) GetGuildLockdown(arg0 context.Context, arg1 string) (db.GuildLockdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuildLockdown", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildLockdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuildLockdown indicates an expected call of GetGuildLockdown.
+func (mr *MockStoreMockRecorder) GetGuildLockdown(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildLockdown", reflect.TypeOf((*MockStore)(nil).GetGuildLockdown), arg0, arg1)
+}
+
+// GetGuildMember mocks base method.
+func (m *MockStore) GetGuildMember(arg0 context.Context, arg1 db.GetGuildMemberParams) (db.GuildMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuildMember", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuildMember indicates an expected call of GetGuildMember.
+func (mr *MockStoreMockRecorder) GetGuildMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildMember", reflect.TypeOf((*MockStore)(nil).GetGuildMember), arg0, arg1)
+}
+
+// GetGuildMemberEvents mocks base method.
+func (m *MockStore) GetGuildMemberEvents(arg0 context.Context, arg1 db.GetGuildMemberEventsParams) ([]db.MemberEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuildMemberEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.MemberEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuildMemberEvents indicates an expected call of GetGuildMemberEvents.
+func (mr *MockStoreMockRecorder) GetGuildMemberEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildMemberEvents", reflect.TypeOf((*MockStore)(nil).GetGuildMemberEvents), arg0, arg1)
+}
+
+// GetGuildsConfigs mocks base method.
+func (m *MockStore) GetGuildsConfigs(arg0 context.Context) ([]db.GuildConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuildsConfigs", arg0)
+	ret0, _ := ret[0].([]db.GuildConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuildsConfigs indicates an expected call of GetGuildsConfigs.
+func (mr *MockStoreMockRecorder) GetGuildsConfigs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildsConfigs", reflect.TypeOf((*MockStore)(nil).GetGuildsConfigs), arg0)
+}
+
+// GetInactiveUsers mocks base method.
+func (m *MockStore) GetInactiveUsers(arg0 context.Context, arg1 db.GetInactiveUsersParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInactiveUsers", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInactiveUsers indicates an expected call of GetInactiveUsers.
+func (mr *MockStoreMockRecorder) GetInactiveUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInactiveUsers", reflect.TypeOf((*MockStore)(nil).GetInactiveUsers), arg0, arg1)
+}
+
+// GetLatestCaseAppeal mocks base method.
+func (m *MockStore) GetLatestCaseAppeal(arg0 context.Context, arg1 int64) (db.Appeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestCaseAppeal", arg0, arg1)
+	ret0, _ := ret[0].(db.Appeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestCaseAppeal indicates an expected call of GetLatestCaseAppeal.
+func (mr *MockStoreMockRecorder) GetLatestCaseAppeal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestCaseAppeal", reflect.TypeOf((*MockStore)(nil).GetLatestCaseAppeal), arg0, arg1)
+}
+
+// GetMemberNote mocks base method.
+func (m *MockStore) GetMemberNote(arg0 context.Context, arg1 db.GetMemberNoteParams) (db.MemberNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberNote", arg0, arg1)
+	ret0, _ := ret[0].(db.MemberNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberNote indicates an expected call of GetMemberNote.
+func (mr *MockStoreMockRecorder) GetMemberNote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberNote", reflect.TypeOf((*MockStore)(nil).GetMemberNote), arg0, arg1)
+}
+
+// GetMemberNoteRevisions mocks base method.
+func (m *MockStore) GetMemberNoteRevisions(arg0 context.Context, arg1 int64) ([]db.MemberNoteRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberNoteRevisions", arg0, arg1)
+	ret0, _ := ret[0].([]db.MemberNoteRevision)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrUpdateGuild indicates an expected call of CreateOrUpdateGuild.
-func (mr *MockStoreMockRecorder) CreateOrUpdateGuild(arg0, arg1 interface{}) *gomock.Call {
+// GetMemberNoteRevisions indicates an expected call of GetMemberNoteRevisions.
+func (mr *MockStoreMockRecorder) GetMemberNoteRevisions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateGuild", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateGuild), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberNoteRevisions", reflect.TypeOf((*MockStore)(nil).GetMemberNoteRevisions), arg0, arg1)
 }
 
-// CreateOrUpdateGuildConfig mocks base method.
-func (m *MockStore) CreateOrUpdateGuildConfig(arg0 context.Context, arg1 db.CreateOrUpdateGuildConfigParams) (db.GuildConfig, error) {
+// GetMemberNotes mocks base method.
+func (m *MockStore) GetMemberNotes(arg0 context.Context, arg1 db.GetMemberNotesParams) ([]db.MemberNote, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateGuildConfig", arg0, arg1)
-	ret0, _ := ret[0].(db.GuildConfig)
+	ret := m.ctrl.Call(m, "GetMemberNotes", arg0, arg1)
+	ret0, _ := ret[0].([]db.MemberNote)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrUpdateGuildConfig indicates an expected call of CreateOrUpdateGuildConfig.
-func (mr *MockStoreMockRecorder) CreateOrUpdateGuildConfig(arg0, arg1 interface{}) *gomock.Call {
+// GetMemberNotes indicates an expected call of GetMemberNotes.
+func (mr *MockStoreMockRecorder) GetMemberNotes(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateGuildConfig", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateGuildConfig), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberNotes", reflect.TypeOf((*MockStore)(nil).GetMemberNotes), arg0, arg1)
 }
 
-// CreateOrUpdateUser mocks base method.
-func (m *MockStore) CreateOrUpdateUser(arg0 context.Context, arg1 db.CreateOrUpdateUserParams) (db.User, error) {
+// GetModerationCase mocks base method.
+func (m *MockStore) GetModerationCase(arg0 context.Context, arg1 db.GetModerationCaseParams) (db.ModerationCase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "GetModerationCase", arg0, arg1)
+	ret0, _ := ret[0].(db.ModerationCase)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrUpdateUser indicates an expected call of CreateOrUpdateUser.
-func (mr *MockStoreMockRecorder) CreateOrUpdateUser(arg0, arg1 interface{}) *gomock.Call {
+// GetModerationCase indicates an expected call of GetModerationCase.
+func (mr *MockStoreMockRecorder) GetModerationCase(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateUser", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationCase", reflect.TypeOf((*MockStore)(nil).GetModerationCase), arg0, arg1)
 }
 
-// CreateOrUpdateUserGuildRel mocks base method.
-func (m *MockStore) CreateOrUpdateUserGuildRel(arg0 context.Context, arg1 db.CreateOrUpdateUserGuildRelParams) (db.UserGuild, error) {
+// GetModerationCaseByID mocks base method.
+func (m *MockStore) GetModerationCaseByID(arg0 context.Context, arg1 int64) (db.ModerationCase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrUpdateUserGuildRel", arg0, arg1)
-	ret0, _ := ret[0].(db.UserGuild)
+	ret := m.ctrl.Call(m, "GetModerationCaseByID", arg0, arg1)
+	ret0, _ := ret[0].(db.ModerationCase)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateOrUpdateUserGuildRel indicates an expected call of CreateOrUpdateUserGuildRel.
-func (mr *MockStoreMockRecorder) CreateOrUpdateUserGuildRel(arg0, arg1 interface{}) *gomock.Call {
+// GetModerationCaseByID indicates an expected call of GetModerationCaseByID.
+func (mr *MockStoreMockRecorder) GetModerationCaseByID(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateUserGuildRel", reflect.TypeOf((*MockStore)(nil).CreateOrUpdateUserGuildRel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationCaseByID", reflect.TypeOf((*MockStore)(nil).GetModerationCaseByID), arg0, arg1)
 }
 
-// CreateUserGuildRel mocks base method.
-func (m *MockStore) CreateUserGuildRel(arg0 context.Context, arg1 db.CreateUserGuildRelParams) (db.UserGuild, error) {
+// GetModerationCases mocks base method.
+func (m *MockStore) GetModerationCases(arg0 context.Context, arg1 db.GetModerationCasesParams) ([]db.ModerationCase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUserGuildRel", arg0, arg1)
-	ret0, _ := ret[0].(db.UserGuild)
+	ret := m.ctrl.Call(m, "GetModerationCases", arg0, arg1)
+	ret0, _ := ret[0].([]db.ModerationCase)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUserGuildRel indicates an expected call of CreateUserGuildRel.
-func (mr *MockStoreMockRecorder) CreateUserGuildRel(arg0, arg1 interface{}) *gomock.Call {
+// GetModerationCases indicates an expected call of GetModerationCases.
+func (mr *MockStoreMockRecorder) GetModerationCases(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserGuildRel", reflect.TypeOf((*MockStore)(nil).CreateUserGuildRel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationCases", reflect.TypeOf((*MockStore)(nil).GetModerationCases), arg0, arg1)
 }
 
-// ExecTx mocks base method.
-func (m *MockStore) ExecTx(arg0 context.Context, arg1 func(*db.Queries) error) error {
+// GetOpenRaidIncident mocks base method.
+func (m *MockStore) GetOpenRaidIncident(arg0 context.Context, arg1 string) (db.RaidIncident, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecTx", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetOpenRaidIncident", arg0, arg1)
+	ret0, _ := ret[0].(db.RaidIncident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ExecTx indicates an expected call of ExecTx.
-func (mr *MockStoreMockRecorder) ExecTx(arg0, arg1 interface{}) *gomock.Call {
+// GetOpenRaidIncident indicates an expected call of GetOpenRaidIncident.
+func (mr *MockStoreMockRecorder) GetOpenRaidIncident(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenRaidIncident", reflect.TypeOf((*MockStore)(nil).GetOpenRaidIncident), arg0, arg1)
 }
 
-// GetGuild mocks base method.
-func (m *MockStore) GetGuild(arg0 context.Context, arg1 string) (db.GetGuildRow, error) {
+// GetRaidIncident mocks base method.
+func (m *MockStore) GetRaidIncident(arg0 context.Context, arg1 db.GetRaidIncidentParams) (db.RaidIncident, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGuild", arg0, arg1)
-	ret0, _ := ret[0].(db.GetGuildRow)
+	ret := m.ctrl.Call(m, "GetRaidIncident", arg0, arg1)
+	ret0, _ := ret[0].(db.RaidIncident)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGuild indicates an expected call of GetGuild.
-func (mr *MockStoreMockRecorder) GetGuild(arg0, arg1 interface{}) *gomock.Call {
+// GetRaidIncident indicates an expected call of GetRaidIncident.
+func (mr *MockStoreMockRecorder) GetRaidIncident(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuild", reflect.TypeOf((*MockStore)(nil).GetGuild), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRaidIncident", reflect.TypeOf((*MockStore)(nil).GetRaidIncident), arg0, arg1)
 }
 
-// GetGuildConfig mocks base method.
-func (m *MockStore) GetGuildConfig(arg0 context.Context, arg1 string) (db.GuildConfig, error) {
+// GetRaidIncidents mocks base method.
+func (m *MockStore) GetRaidIncidents(arg0 context.Context, arg1 db.GetRaidIncidentsParams) ([]db.RaidIncident, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGuildConfig", arg0, arg1)
-	ret0, _ := ret[0].(db.GuildConfig)
+	ret := m.ctrl.Call(m, "GetRaidIncidents", arg0, arg1)
+	ret0, _ := ret[0].([]db.RaidIncident)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGuildConfig indicates an expected call of GetGuildConfig.
-func (mr *MockStoreMockRecorder) GetGuildConfig(arg0, arg1 interface{}) *gomock.Call {
+// GetRaidIncidents indicates an expected call of GetRaidIncidents.
+func (mr *MockStoreMockRecorder) GetRaidIncidents(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildConfig", reflect.TypeOf((*MockStore)(nil).GetGuildConfig), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRaidIncidents", reflect.TypeOf((*MockStore)(nil).GetRaidIncidents), arg0, arg1)
 }
 
-// GetGuildsConfigs mocks base method.
-func (m *MockStore) GetGuildsConfigs(arg0 context.Context) ([]db.GuildConfig, error) {
+// GetScheduledAction mocks base method.
+func (m *MockStore) GetScheduledAction(arg0 context.Context, arg1 int64) (db.ScheduledAction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGuildsConfigs", arg0)
-	ret0, _ := ret[0].([]db.GuildConfig)
+	ret := m.ctrl.Call(m, "GetScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledAction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetGuildsConfigs indicates an expected call of GetGuildsConfigs.
-func (mr *MockStoreMockRecorder) GetGuildsConfigs(arg0 interface{}) *gomock.Call {
+// GetScheduledAction indicates an expected call of GetScheduledAction.
+func (mr *MockStoreMockRecorder) GetScheduledAction(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuildsConfigs", reflect.TypeOf((*MockStore)(nil).GetGuildsConfigs), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledAction", reflect.TypeOf((*MockStore)(nil).GetScheduledAction), arg0, arg1)
+}
+
+// GetTargetLatestBanCases mocks base method.
+func (m *MockStore) GetTargetLatestBanCases(arg0 context.Context, arg1 string) ([]db.ModerationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetLatestBanCases", arg0, arg1)
+	ret0, _ := ret[0].([]db.ModerationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetLatestBanCases indicates an expected call of GetTargetLatestBanCases.
+func (mr *MockStoreMockRecorder) GetTargetLatestBanCases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetLatestBanCases", reflect.TypeOf((*MockStore)(nil).GetTargetLatestBanCases), arg0, arg1)
+}
+
+// GetTargetLatestCase mocks base method.
+func (m *MockStore) GetTargetLatestCase(arg0 context.Context, arg1 db.GetTargetLatestCaseParams) (db.ModerationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetLatestCase", arg0, arg1)
+	ret0, _ := ret[0].(db.ModerationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetLatestCase indicates an expected call of GetTargetLatestCase.
+func (mr *MockStoreMockRecorder) GetTargetLatestCase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetLatestCase", reflect.TypeOf((*MockStore)(nil).GetTargetLatestCase), arg0, arg1)
+}
+
+// GetTargetModerationCases mocks base method.
+func (m *MockStore) GetTargetModerationCases(arg0 context.Context, arg1 db.GetTargetModerationCasesParams) ([]db.ModerationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetModerationCases", arg0, arg1)
+	ret0, _ := ret[0].([]db.ModerationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetModerationCases indicates an expected call of GetTargetModerationCases.
+func (mr *MockStoreMockRecorder) GetTargetModerationCases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetModerationCases", reflect.TypeOf((*MockStore)(nil).GetTargetModerationCases), arg0, arg1)
 }
 
 // GetUser mocks base method.
@@ -184,6 +1102,51 @@ func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
 }
 
+// GetUserAppealComments mocks base method.
+func (m *MockStore) GetUserAppealComments(arg0 context.Context, arg1 sql.NullString) ([]db.AppealComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAppealComments", arg0, arg1)
+	ret0, _ := ret[0].([]db.AppealComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAppealComments indicates an expected call of GetUserAppealComments.
+func (mr *MockStoreMockRecorder) GetUserAppealComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAppealComments", reflect.TypeOf((*MockStore)(nil).GetUserAppealComments), arg0, arg1)
+}
+
+// GetUserAppeals mocks base method.
+func (m *MockStore) GetUserAppeals(arg0 context.Context, arg1 string) ([]db.Appeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAppeals", arg0, arg1)
+	ret0, _ := ret[0].([]db.Appeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAppeals indicates an expected call of GetUserAppeals.
+func (mr *MockStoreMockRecorder) GetUserAppeals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAppeals", reflect.TypeOf((*MockStore)(nil).GetUserAppeals), arg0, arg1)
+}
+
+// GetUserAuditLogs mocks base method.
+func (m *MockStore) GetUserAuditLogs(arg0 context.Context, arg1 sql.NullString) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAuditLogs indicates an expected call of GetUserAuditLogs.
+func (mr *MockStoreMockRecorder) GetUserAuditLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAuditLogs", reflect.TypeOf((*MockStore)(nil).GetUserAuditLogs), arg0, arg1)
+}
+
 // GetUserGuild mocks base method.
 func (m *MockStore) GetUserGuild(arg0 context.Context, arg1 db.GetUserGuildParams) (db.GetUserGuildRow, error) {
 	m.ctrl.T.Helper()
@@ -229,6 +1192,199 @@ func (mr *MockStoreMockRecorder) GetUserGuilds(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGuilds", reflect.TypeOf((*MockStore)(nil).GetUserGuilds), arg0, arg1)
 }
 
+// GetUserMemberEvents mocks base method.
+func (m *MockStore) GetUserMemberEvents(arg0 context.Context, arg1 string) ([]db.MemberEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserMemberEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.MemberEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserMemberEvents indicates an expected call of GetUserMemberEvents.
+func (mr *MockStoreMockRecorder) GetUserMemberEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserMemberEvents", reflect.TypeOf((*MockStore)(nil).GetUserMemberEvents), arg0, arg1)
+}
+
+// GetUserVerifiedMembers mocks base method.
+func (m *MockStore) GetUserVerifiedMembers(arg0 context.Context, arg1 string) ([]db.VerifiedMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserVerifiedMembers", arg0, arg1)
+	ret0, _ := ret[0].([]db.VerifiedMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserVerifiedMembers indicates an expected call of GetUserVerifiedMembers.
+func (mr *MockStoreMockRecorder) GetUserVerifiedMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVerifiedMembers", reflect.TypeOf((*MockStore)(nil).GetUserVerifiedMembers), arg0, arg1)
+}
+
+// GetVerifiedMember mocks base method.
+func (m *MockStore) GetVerifiedMember(arg0 context.Context, arg1 db.GetVerifiedMemberParams) (db.VerifiedMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerifiedMember", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifiedMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVerifiedMember indicates an expected call of GetVerifiedMember.
+func (mr *MockStoreMockRecorder) GetVerifiedMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerifiedMember", reflect.TypeOf((*MockStore)(nil).GetVerifiedMember), arg0, arg1)
+}
+
+// PostponeScheduledAction mocks base method.
+func (m *MockStore) PostponeScheduledAction(arg0 context.Context, arg1 db.PostponeScheduledActionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostponeScheduledAction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostponeScheduledAction indicates an expected call of PostponeScheduledAction.
+func (mr *MockStoreMockRecorder) PostponeScheduledAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostponeScheduledAction", reflect.TypeOf((*MockStore)(nil).PostponeScheduledAction), arg0, arg1)
+}
+
+// RecordGuildMemberSeen mocks base method.
+func (m *MockStore) RecordGuildMemberSeen(arg0 context.Context, arg1 db.RecordGuildMemberSeenParams) (db.GuildMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordGuildMemberSeen", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordGuildMemberSeen indicates an expected call of RecordGuildMemberSeen.
+func (mr *MockStoreMockRecorder) RecordGuildMemberSeen(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordGuildMemberSeen", reflect.TypeOf((*MockStore)(nil).RecordGuildMemberSeen), arg0, arg1)
+}
+
+// ResolveRaidIncident mocks base method.
+func (m *MockStore) ResolveRaidIncident(arg0 context.Context, arg1 db.ResolveRaidIncidentParams) (db.RaidIncident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRaidIncident", arg0, arg1)
+	ret0, _ := ret[0].(db.RaidIncident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveRaidIncident indicates an expected call of ResolveRaidIncident.
+func (mr *MockStoreMockRecorder) ResolveRaidIncident(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRaidIncident", reflect.TypeOf((*MockStore)(nil).ResolveRaidIncident), arg0, arg1)
+}
+
+// RevokeApiKey mocks base method.
+func (m *MockStore) RevokeApiKey(arg0 context.Context, arg1 string) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockStoreMockRecorder) RevokeApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockStore)(nil).RevokeApiKey), arg0, arg1)
+}
+
+// RotateApiKey mocks base method.
+func (m *MockStore) RotateApiKey(arg0 context.Context, arg1 db.RotateApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateApiKey indicates an expected call of RotateApiKey.
+func (mr *MockStoreMockRecorder) RotateApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateApiKey", reflect.TypeOf((*MockStore)(nil).RotateApiKey), arg0, arg1)
+}
+
+// ScheduleCaseAction mocks base method.
+func (m *MockStore) ScheduleCaseAction(arg0 context.Context, arg1 db.ScheduleCaseActionParams) (db.ScheduledAction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleCaseAction", arg0, arg1)
+	ret0, _ := ret[0].(db.ScheduledAction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleCaseAction indicates an expected call of ScheduleCaseAction.
+func (mr *MockStoreMockRecorder) ScheduleCaseAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleCaseAction", reflect.TypeOf((*MockStore)(nil).ScheduleCaseAction), arg0, arg1)
+}
+
+// SetGuildBotJoined mocks base method.
+func (m *MockStore) SetGuildBotJoined(arg0 context.Context, arg1 db.SetGuildBotJoinedParams) (db.Guild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGuildBotJoined", arg0, arg1)
+	ret0, _ := ret[0].(db.Guild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetGuildBotJoined indicates an expected call of SetGuildBotJoined.
+func (mr *MockStoreMockRecorder) SetGuildBotJoined(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGuildBotJoined", reflect.TypeOf((*MockStore)(nil).SetGuildBotJoined), arg0, arg1)
+}
+
+// SetGuildBotLeft mocks base method.
+func (m *MockStore) SetGuildBotLeft(arg0 context.Context, arg1 string) (db.Guild, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGuildBotLeft", arg0, arg1)
+	ret0, _ := ret[0].(db.Guild)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetGuildBotLeft indicates an expected call of SetGuildBotLeft.
+func (mr *MockStoreMockRecorder) SetGuildBotLeft(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGuildBotLeft", reflect.TypeOf((*MockStore)(nil).SetGuildBotLeft), arg0, arg1)
+}
+
+// SetGuildLockdown mocks base method.
+func (m *MockStore) SetGuildLockdown(arg0 context.Context, arg1 db.SetGuildLockdownParams) (db.GuildLockdown, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetGuildLockdown", arg0, arg1)
+	ret0, _ := ret[0].(db.GuildLockdown)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetGuildLockdown indicates an expected call of SetGuildLockdown.
+func (mr *MockStoreMockRecorder) SetGuildLockdown(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetGuildLockdown", reflect.TypeOf((*MockStore)(nil).SetGuildLockdown), arg0, arg1)
+}
+
+// TouchApiKey mocks base method.
+func (m *MockStore) TouchApiKey(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchApiKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchApiKey indicates an expected call of TouchApiKey.
+func (mr *MockStoreMockRecorder) TouchApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchApiKey", reflect.TypeOf((*MockStore)(nil).TouchApiKey), arg0, arg1)
+}
+
 // TryCreateGuildConfig mocks base method.
 func (m *MockStore) TryCreateGuildConfig(arg0 context.Context, arg1 db.TryCreateGuildConfigParams) (db.GuildConfig, error) {
 	m.ctrl.T.Helper()
@@ -257,3 +1413,63 @@ func (mr *MockStoreMockRecorder) UpdateGuildConfig(arg0, arg1 interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGuildConfig", reflect.TypeOf((*MockStore)(nil).UpdateGuildConfig), arg0, arg1)
 }
+
+// UpdateGuildOwner mocks base method.
+func (m *MockStore) UpdateGuildOwner(arg0 context.Context, arg1 db.UpdateGuildOwnerParams) (db.UpdateGuildOwnerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGuildOwner", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateGuildOwnerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGuildOwner indicates an expected call of UpdateGuildOwner.
+func (mr *MockStoreMockRecorder) UpdateGuildOwner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGuildOwner", reflect.TypeOf((*MockStore)(nil).UpdateGuildOwner), arg0, arg1)
+}
+
+// UpdateMemberNote mocks base method.
+func (m *MockStore) UpdateMemberNote(arg0 context.Context, arg1 db.UpdateMemberNoteParams) (db.MemberNote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberNote", arg0, arg1)
+	ret0, _ := ret[0].(db.MemberNote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemberNote indicates an expected call of UpdateMemberNote.
+func (mr *MockStoreMockRecorder) UpdateMemberNote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberNote", reflect.TypeOf((*MockStore)(nil).UpdateMemberNote), arg0, arg1)
+}
+
+// UpdateModerationCaseExpiry mocks base method.
+func (m *MockStore) UpdateModerationCaseExpiry(arg0 context.Context, arg1 db.UpdateModerationCaseExpiryParams) (db.ModerationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateModerationCaseExpiry", arg0, arg1)
+	ret0, _ := ret[0].(db.ModerationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateModerationCaseExpiry indicates an expected call of UpdateModerationCaseExpiry.
+func (mr *MockStoreMockRecorder) UpdateModerationCaseExpiry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateModerationCaseExpiry", reflect.TypeOf((*MockStore)(nil).UpdateModerationCaseExpiry), arg0, arg1)
+}
+
+// UpdateRaidIncidentDetection mocks base method.
+func (m *MockStore) UpdateRaidIncidentDetection(arg0 context.Context, arg1 db.UpdateRaidIncidentDetectionParams) (db.RaidIncident, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRaidIncidentDetection", arg0, arg1)
+	ret0, _ := ret[0].(db.RaidIncident)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRaidIncidentDetection indicates an expected call of UpdateRaidIncidentDetection.
+func (mr *MockStoreMockRecorder) UpdateRaidIncidentDetection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRaidIncidentDetection", reflect.TypeOf((*MockStore)(nil).UpdateRaidIncidentDetection), arg0, arg1)
+}
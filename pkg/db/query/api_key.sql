@@ -0,0 +1,35 @@
+-- name: CreateApiKey :one
+INSERT INTO api_key (name, prefix, hashed_key, scopes, guild_discord_ids, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetApiKeyByPrefix :one
+SELECT *
+FROM api_key
+WHERE prefix = $1
+LIMIT 1;
+
+-- name: GetApiKeys :many
+SELECT *
+FROM api_key
+ORDER BY id;
+
+-- name: RotateApiKey :one
+UPDATE api_key
+SET prefix     = $2,
+    hashed_key = $3
+WHERE prefix = $1
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeApiKey :one
+UPDATE api_key
+SET revoked_at = now()
+WHERE prefix = $1
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchApiKey :exec
+UPDATE api_key
+SET last_used_at = now()
+WHERE id = $1;
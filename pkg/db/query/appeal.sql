@@ -0,0 +1,78 @@
+-- name: CreateAppeal :one
+INSERT INTO appeal (guild_discord_id, case_id, user_discord_id, answers)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetAppeal :one
+SELECT *
+FROM appeal
+WHERE id = $1
+  AND guild_discord_id = $2
+LIMIT 1;
+
+-- name: GetLatestCaseAppeal :one
+SELECT *
+FROM appeal
+WHERE case_id = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetUserAppeals :many
+SELECT *
+FROM appeal
+WHERE user_discord_id = $1
+ORDER BY id DESC;
+
+-- name: GetGuildAppeals :many
+-- empty status matches every appeal, zero before_id starts from the newest appeal
+SELECT *
+FROM appeal
+WHERE guild_discord_id = sqlc.arg(guild_discord_id)
+  AND (sqlc.arg(status)::varchar = '' OR status = sqlc.arg(status))
+  AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: DecideAppeal :one
+UPDATE appeal
+SET status              = $3,
+    reviewer_discord_id = $4,
+    decision_reason     = $5,
+    decided_at          = now()
+WHERE id = $1
+  AND guild_discord_id = $2
+  AND status = 'pending'
+RETURNING *;
+
+-- name: CreateAppealComment :one
+INSERT INTO appeal_comment (appeal_id, author_discord_id, content)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetAppealComments :many
+SELECT *
+FROM appeal_comment
+WHERE appeal_id = $1
+ORDER BY id;
+
+-- name: GetUserAppealComments :many
+SELECT *
+FROM appeal_comment
+WHERE author_discord_id = $1
+ORDER BY id;
+
+-- name: DeleteUserAppeals :exec
+-- comments of the deleted appeals are removed by cascade
+DELETE
+FROM appeal
+WHERE user_discord_id = $1;
+
+-- name: AnonymizeAppealReviewers :exec
+UPDATE appeal
+SET reviewer_discord_id = NULL
+WHERE reviewer_discord_id = $1;
+
+-- name: AnonymizeAppealComments :exec
+UPDATE appeal_comment
+SET author_discord_id = NULL
+WHERE author_discord_id = $1;
@@ -0,0 +1,15 @@
+-- name: AnonymizeAuditLogs :exec
+UPDATE audit_log
+SET actor_discord_id = NULL
+WHERE actor_discord_id = $1;
+
+-- name: CreateAuditLog :one
+INSERT INTO audit_log (actor_discord_id, guild_discord_id, action, data)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUserAuditLogs :many
+SELECT *
+FROM audit_log
+WHERE actor_discord_id = $1
+ORDER BY created_at;
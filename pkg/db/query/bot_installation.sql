@@ -0,0 +1,4 @@
+-- name: CreateBotInstallation :one
+INSERT INTO bot_installation (guild_discord_id, installer_discord_id, permissions)
+VALUES ($1, $2, $3)
+RETURNING *;
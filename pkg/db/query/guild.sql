@@ -1,16 +1,71 @@
 -- name: CreateOrUpdateGuild :one
+-- owner only changes when the member is the owner, or when the recorded owner is no longer one
 INSERT INTO guild (discord_id, name, icon, owner_discord_id)
-VALUES ($1, $2, $3, $4)
+VALUES (sqlc.arg(discord_id), sqlc.arg(name), sqlc.arg(icon), sqlc.arg(owner_discord_id))
+ON CONFLICT (discord_id) DO UPDATE
+    SET name             = sqlc.arg(name),
+        icon             = sqlc.arg(icon),
+        owner_discord_id = CASE
+                               WHEN sqlc.arg(owner_discord_id) <> '' THEN sqlc.arg(owner_discord_id)
+                               WHEN guild.owner_discord_id = sqlc.arg(member_discord_id) THEN ''
+                               ELSE guild.owner_discord_id
+            END,
+        orphaned_at      = NULL,
+        archived_at      = NULL
+RETURNING *;
+
+-- name: SetGuildBotJoined :one
+INSERT INTO guild (discord_id, name, icon, owner_discord_id, bot_present, bot_joined_at)
+VALUES ($1, $2, $3, $4, true, now())
 ON CONFLICT (discord_id) DO UPDATE
     SET name             = $2,
         icon             = $3,
-        owner_discord_id = $4
+        owner_discord_id = $4,
+        bot_present      = true,
+        bot_joined_at    = coalesce(guild.bot_joined_at, now()),
+        orphaned_at      = NULL,
+        archived_at      = NULL
+RETURNING *;
+
+-- name: SetGuildBotLeft :one
+UPDATE guild
+SET bot_present   = false,
+    bot_joined_at = NULL
+WHERE discord_id = $1
 RETURNING *;
 
+-- name: UpdateGuildOwner :one
+UPDATE guild g
+SET owner_discord_id = $2
+FROM guild prev
+WHERE g.discord_id = $1
+  AND prev.id = g.id
+RETURNING g.*, prev.owner_discord_id AS previous_owner_discord_id;
+
+-- name: FlagOrphanedGuilds :execrows
+UPDATE guild g
+SET orphaned_at = now()
+WHERE g.orphaned_at IS NULL
+  AND NOT g.bot_present
+  AND NOT EXISTS(SELECT 1 FROM "user" u WHERE u.discord_id = g.owner_discord_id)
+  AND NOT EXISTS(SELECT 1 FROM user_guild ug WHERE ug.guild_discord_id = g.discord_id);
+
+-- name: ArchiveOrphanedGuilds :execrows
+UPDATE guild
+SET archived_at = now()
+WHERE archived_at IS NULL
+  AND orphaned_at < $1;
+
+-- name: DeleteArchivedGuilds :execrows
+DELETE
+FROM guild
+WHERE archived_at < $1;
+
 -- name: GetGuild :one
 SELECT g.*,
        (gc.json::json -> 'permissions' ->> 'read')::bigint AS config_read,
-       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit
+       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit,
+       coalesce((gc.json::json -> 'permissions' ->> 'everyone_can_read')::boolean, false) AS config_everyone_can_read
 FROM guild g
          INNER JOIN guild_config gc ON gc.id = g.id
 WHERE discord_id = $1
@@ -23,8 +78,11 @@ SELECT coalesce(g.id, 0),
        coalesce(g.owner_discord_id, '0'),
        coalesce(g.icon, '#'),
        coalesce(g.name, ''),
+       coalesce(g.bot_present, false)                      AS bot_present,
+       g.bot_joined_at,
        (gc.json::json -> 'permissions' ->> 'read')::bigint AS config_read,
-       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit
+       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit,
+       coalesce((gc.json::json -> 'permissions' ->> 'everyone_can_read')::boolean, false) AS config_everyone_can_read
 FROM user_guild ug
          LEFT OUTER JOIN guild g ON g.discord_id = ug.guild_discord_id
          INNER JOIN guild_config gc ON gc.id = g.id
@@ -39,8 +97,11 @@ SELECT coalesce(g.id, 0),
        coalesce(g.owner_discord_id, '0'),
        coalesce(g.icon, '#'),
        coalesce(g.name, ''),
+       coalesce(g.bot_present, false)                      AS bot_present,
+       g.bot_joined_at,
        (gc.json::json -> 'permissions' ->> 'read')::bigint AS config_read,
-       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit
+       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit,
+       coalesce((gc.json::json -> 'permissions' ->> 'everyone_can_read')::boolean, false) AS config_everyone_can_read
 FROM user_guild ug
          LEFT OUTER JOIN guild g ON g.discord_id = ug.guild_discord_id
          INNER JOIN guild_config gc ON gc.id = g.id
@@ -0,0 +1,56 @@
+-- name: SetGuildLockdown :one
+-- locking an already locked guild replaces its lockdown
+INSERT INTO guild_lockdown (guild_discord_id, scope, channel_discord_ids, reason, actor_discord_id, lifts_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (guild_discord_id) DO UPDATE
+    SET scope               = excluded.scope,
+        channel_discord_ids = excluded.channel_discord_ids,
+        reason              = excluded.reason,
+        actor_discord_id    = excluded.actor_discord_id,
+        incident_id         = NULL,
+        lifts_at            = excluded.lifts_at,
+        created_at          = now()
+RETURNING *;
+
+-- name: CreateIncidentGuildLockdown :one
+-- lockdowns of raid detection never replace the existing one
+INSERT INTO guild_lockdown (guild_discord_id, scope, reason, incident_id)
+VALUES ($1, 'all', $2, $3)
+ON CONFLICT (guild_discord_id) DO NOTHING
+RETURNING *;
+
+-- name: GetGuildLockdown :one
+SELECT *
+FROM guild_lockdown
+WHERE guild_discord_id = $1
+LIMIT 1;
+
+-- name: DeleteGuildLockdown :one
+DELETE
+FROM guild_lockdown
+WHERE guild_discord_id = $1
+RETURNING *;
+
+-- name: DeleteExpiredGuildLockdowns :many
+-- rows are locked with SKIP LOCKED, so every replica lifts different lockdowns,
+-- audit logs of the lifts are written by the same statement
+WITH lifted AS (
+    DELETE
+        FROM guild_lockdown
+            WHERE guild_discord_id IN (SELECT guild_discord_id
+                                       FROM guild_lockdown
+                                       WHERE lifts_at <= sqlc.arg(now)::timestamptz
+                                       ORDER BY lifts_at
+                                       LIMIT sqlc.arg(max_results) FOR UPDATE SKIP LOCKED)
+        RETURNING *),
+     audit AS (
+         INSERT INTO audit_log (guild_discord_id, action, data)
+             SELECT guild_discord_id, 'guild.lockdown_lift', row_to_json(lifted)::jsonb
+             FROM lifted)
+SELECT *
+FROM lifted;
+
+-- name: AnonymizeGuildLockdownActors :exec
+UPDATE guild_lockdown
+SET actor_discord_id = NULL
+WHERE actor_discord_id = $1;
@@ -0,0 +1,15 @@
+-- name: RecordGuildMemberSeen :one
+-- reports may arrive out of order, so seen times only ever widen
+INSERT INTO guild_member (guild_discord_id, member_discord_id, first_seen_at, last_seen_at)
+VALUES (sqlc.arg(guild_discord_id), sqlc.arg(member_discord_id), sqlc.arg(seen_at), sqlc.arg(seen_at))
+ON CONFLICT (guild_discord_id, member_discord_id) DO UPDATE
+    SET first_seen_at = LEAST(guild_member.first_seen_at, excluded.first_seen_at),
+        last_seen_at  = GREATEST(guild_member.last_seen_at, excluded.last_seen_at)
+RETURNING *;
+
+-- name: GetGuildMember :one
+SELECT *
+FROM guild_member
+WHERE guild_discord_id = $1
+  AND member_discord_id = $2
+LIMIT 1;
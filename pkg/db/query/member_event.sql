@@ -0,0 +1,27 @@
+-- name: CreateMemberEvent :exec
+INSERT INTO member_event (guild_discord_id, type, member_discord_id, username, account_created_at, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: GetGuildMemberEvents :many
+SELECT *
+FROM member_event
+WHERE guild_discord_id = sqlc.arg(guild_discord_id)
+  AND occurred_at >= sqlc.arg(since)
+  AND occurred_at < sqlc.arg(until)
+ORDER BY occurred_at;
+
+-- name: GetUserMemberEvents :many
+SELECT *
+FROM member_event
+WHERE member_discord_id = $1
+ORDER BY occurred_at;
+
+-- name: DeleteUserMemberEvents :exec
+DELETE
+FROM member_event
+WHERE member_discord_id = $1;
+
+-- name: DeleteMemberEventsBefore :execrows
+DELETE
+FROM member_event
+WHERE occurred_at < $1;
@@ -0,0 +1,68 @@
+-- name: CreateMemberNote :one
+INSERT INTO member_note (guild_discord_id, member_discord_id, author_discord_id, content)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetMemberNote :one
+-- deleted notes are returned too, so their history stays readable
+SELECT *
+FROM member_note
+WHERE id = $1
+  AND guild_discord_id = $2
+  AND member_discord_id = $3
+LIMIT 1;
+
+-- name: GetMemberNotes :many
+SELECT *
+FROM member_note
+WHERE guild_discord_id = $1
+  AND member_discord_id = $2
+  AND deleted_at IS NULL
+ORDER BY id DESC;
+
+-- name: UpdateMemberNote :one
+UPDATE member_note
+SET content    = $4,
+    updated_at = now()
+WHERE id = $1
+  AND guild_discord_id = $2
+  AND member_discord_id = $3
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteMemberNote :one
+UPDATE member_note
+SET deleted_at = now()
+WHERE id = $1
+  AND guild_discord_id = $2
+  AND member_discord_id = $3
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateMemberNoteRevision :one
+INSERT INTO member_note_revision (note_id, action, content, editor_discord_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetMemberNoteRevisions :many
+SELECT *
+FROM member_note_revision
+WHERE note_id = $1
+ORDER BY id;
+
+-- name: GetAuthoredMemberNotes :many
+-- deleted notes are returned too, they are still stored
+SELECT *
+FROM member_note
+WHERE author_discord_id = $1
+ORDER BY id;
+
+-- name: AnonymizeMemberNotes :exec
+UPDATE member_note
+SET author_discord_id = NULL
+WHERE author_discord_id = $1;
+
+-- name: AnonymizeMemberNoteRevisions :exec
+UPDATE member_note_revision
+SET editor_discord_id = NULL
+WHERE editor_discord_id = $1;
@@ -0,0 +1,79 @@
+-- name: CreateModerationCase :one
+-- case number is taken from the per-guild counter, so concurrent cases never share a number
+WITH counter AS (
+    INSERT INTO moderation_case_counter (guild_discord_id, last_case_number)
+        VALUES (sqlc.arg(guild_discord_id), 1)
+        ON CONFLICT (guild_discord_id) DO UPDATE
+            SET last_case_number = moderation_case_counter.last_case_number + 1
+        RETURNING last_case_number)
+INSERT
+INTO moderation_case (guild_discord_id, case_number, action, target_discord_id, moderator_discord_id,
+                      reason, duration_seconds, expires_at)
+SELECT sqlc.arg(guild_discord_id),
+       counter.last_case_number,
+       sqlc.arg(action),
+       sqlc.arg(target_discord_id),
+       sqlc.arg(moderator_discord_id),
+       sqlc.arg(reason),
+       sqlc.arg(duration_seconds),
+       sqlc.arg(expires_at)
+FROM counter
+RETURNING *;
+
+-- name: GetModerationCase :one
+SELECT *
+FROM moderation_case
+WHERE guild_discord_id = $1
+  AND case_number = $2
+LIMIT 1;
+
+-- name: GetModerationCases :many
+-- empty filters match every case, zero before_case_number starts from the newest case
+SELECT *
+FROM moderation_case
+WHERE guild_discord_id = sqlc.arg(guild_discord_id)
+  AND (sqlc.arg(action)::varchar = '' OR action = sqlc.arg(action))
+  AND (sqlc.arg(target_discord_id)::varchar = '' OR target_discord_id = sqlc.arg(target_discord_id))
+  AND (sqlc.arg(moderator_discord_id)::varchar = '' OR moderator_discord_id = sqlc.arg(moderator_discord_id))
+  AND (sqlc.arg(before_case_number)::bigint = 0 OR case_number < sqlc.arg(before_case_number))
+ORDER BY case_number DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: UpdateModerationCaseExpiry :one
+UPDATE moderation_case
+SET duration_seconds = $2,
+    expires_at       = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: GetTargetModerationCases :many
+SELECT *
+FROM moderation_case
+WHERE guild_discord_id = $1
+  AND target_discord_id = $2
+  AND created_at > $3
+ORDER BY case_number;
+
+-- name: GetModerationCaseByID :one
+SELECT *
+FROM moderation_case
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetTargetLatestBanCases :many
+-- the newest ban or unban case of the target in each guild tells whether the target is banned there
+SELECT DISTINCT ON (guild_discord_id) *
+FROM moderation_case
+WHERE target_discord_id = $1
+  AND action IN ('ban', 'unban')
+ORDER BY guild_discord_id, case_number DESC;
+
+-- name: GetTargetLatestCase :one
+-- the newest case of the target among the actions, older cases are superseded by it
+SELECT *
+FROM moderation_case
+WHERE guild_discord_id = sqlc.arg(guild_discord_id)
+  AND target_discord_id = sqlc.arg(target_discord_id)
+  AND action = ANY (sqlc.arg(actions)::varchar[])
+ORDER BY case_number DESC
+LIMIT 1;
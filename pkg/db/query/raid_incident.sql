@@ -0,0 +1,49 @@
+-- name: CreateRaidIncident :one
+INSERT INTO raid_incident (guild_discord_id, detection, lockdown_requested)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetOpenRaidIncident :one
+SELECT *
+FROM raid_incident
+WHERE guild_discord_id = $1
+  AND status = 'open'
+LIMIT 1;
+
+-- name: UpdateRaidIncidentDetection :one
+UPDATE raid_incident
+SET detection        = $2,
+    last_detected_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ResolveRaidIncident :one
+UPDATE raid_incident
+SET status                 = 'resolved',
+    resolved_by_discord_id = $3,
+    resolved_at            = now()
+WHERE id = $1
+  AND guild_discord_id = $2
+  AND status = 'open'
+RETURNING *;
+
+-- name: GetRaidIncident :one
+SELECT *
+FROM raid_incident
+WHERE id = $1
+  AND guild_discord_id = $2
+LIMIT 1;
+
+-- name: GetRaidIncidents :many
+-- zero before_id starts from the newest incident
+SELECT *
+FROM raid_incident
+WHERE guild_discord_id = sqlc.arg(guild_discord_id)
+  AND (sqlc.arg(before_id)::bigint = 0 OR id < sqlc.arg(before_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: AnonymizeRaidIncidentResolvers :exec
+UPDATE raid_incident
+SET resolved_by_discord_id = NULL
+WHERE resolved_by_discord_id = $1;
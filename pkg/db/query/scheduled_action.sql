@@ -0,0 +1,67 @@
+-- name: ScheduleCaseAction :one
+-- rescheduling revives cancelled actions, executed actions are never scheduled again
+INSERT INTO scheduled_action (guild_discord_id, case_id, action, target_discord_id, run_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (case_id) DO UPDATE
+    SET run_at        = excluded.run_at,
+        status        = 'pending',
+        attempts      = 0,
+        dispatched_at = NULL
+WHERE scheduled_action.status <> 'done'
+RETURNING *;
+
+-- name: CancelCaseScheduledAction :one
+UPDATE scheduled_action
+SET status       = 'cancelled',
+    completed_at = now()
+WHERE case_id = $1
+  AND status IN ('pending', 'dispatched')
+RETURNING *;
+
+-- name: CancelTargetScheduledActions :execrows
+UPDATE scheduled_action
+SET status       = 'cancelled',
+    completed_at = now()
+WHERE guild_discord_id = $1
+  AND target_discord_id = $2
+  AND action = $3
+  AND status IN ('pending', 'dispatched');
+
+-- name: ClaimDueScheduledActions :many
+-- claimed rows are skipped by other replicas, dispatched actions are claimed again if the bot
+-- did not acknowledge them before the deadline
+UPDATE scheduled_action
+SET status        = 'dispatched',
+    dispatched_at = sqlc.arg(now)
+WHERE id IN (SELECT id
+             FROM scheduled_action
+             WHERE (status = 'pending' AND run_at <= sqlc.arg(now))
+                OR (status = 'dispatched' AND dispatched_at <= sqlc.arg(ack_deadline))
+             ORDER BY run_at
+             LIMIT sqlc.arg(max_results) FOR UPDATE SKIP LOCKED)
+RETURNING *;
+
+-- name: GetScheduledAction :one
+SELECT *
+FROM scheduled_action
+WHERE id = $1
+LIMIT 1;
+
+-- name: AckScheduledAction :one
+-- acknowledging twice is harmless, actions cancelled or rescheduled meanwhile are left untouched
+UPDATE scheduled_action
+SET status       = 'done',
+    completed_at = COALESCE(completed_at, now())
+WHERE id = $1
+  AND status IN ('dispatched', 'done')
+RETURNING *;
+
+-- name: PostponeScheduledAction :exec
+-- hands the claimed action back, so it does not wait for the acknowledgement deadline
+UPDATE scheduled_action
+SET status        = 'pending',
+    dispatched_at = NULL,
+    run_at        = $2,
+    attempts      = attempts + 1
+WHERE id = $1
+  AND status = 'dispatched';
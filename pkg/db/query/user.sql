@@ -8,12 +8,25 @@ ON CONFLICT (discord_id) DO UPDATE
         verified      = $4,
         email         = $5,
         avatar        = $6,
-        banner        = $8,
-        accent_color  = $9
+        banner        = $7,
+        accent_color  = $8,
+        last_login_at = now()
 RETURNING *;
 
+-- name: DeleteUser :execrows
+DELETE
+FROM "user"
+WHERE discord_id = $1;
+
+-- name: GetInactiveUsers :many
+SELECT discord_id
+FROM "user"
+WHERE last_login_at < $1
+ORDER BY last_login_at
+LIMIT $2;
+
 -- name: GetUser :one
 SELECT *
 FROM "user"
 WHERE discord_id = $1
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
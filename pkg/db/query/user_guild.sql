@@ -10,6 +10,23 @@ INSERT INTO user_guild (guild_discord_id, account_discord_id, permissions)
 VALUES ($1, $2, $3)
 RETURNING *;
 
+-- name: DeleteUserGuildRels :exec
+DELETE
+FROM user_guild
+WHERE account_discord_id = $1;
+
+-- name: DeleteStaleUserGuildRels :exec
+DELETE
+FROM user_guild
+WHERE account_discord_id = sqlc.arg(account_discord_id)
+  AND NOT (guild_discord_id = ANY (sqlc.arg(guild_discord_ids)::varchar[]));
+
+-- name: DeleteUserGuildRel :exec
+DELETE
+FROM user_guild
+WHERE account_discord_id = $1
+  AND guild_discord_id = $2;
+
 -- name: GetUserGuildRel :one
 SELECT *
 FROM user_guild
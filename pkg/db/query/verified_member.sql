@@ -0,0 +1,25 @@
+-- name: CreateVerifiedMember :one
+-- verifying again keeps the time of the first verification
+INSERT INTO verified_member (guild_discord_id, member_discord_id)
+VALUES ($1, $2)
+ON CONFLICT (guild_discord_id, member_discord_id) DO UPDATE
+    SET verified_at = verified_member.verified_at
+RETURNING *;
+
+-- name: GetVerifiedMember :one
+SELECT *
+FROM verified_member
+WHERE guild_discord_id = $1
+  AND member_discord_id = $2
+LIMIT 1;
+
+-- name: GetUserVerifiedMembers :many
+SELECT *
+FROM verified_member
+WHERE member_discord_id = $1
+ORDER BY verified_at;
+
+-- name: DeleteUserVerifiedMembers :exec
+DELETE
+FROM verified_member
+WHERE member_discord_id = $1;
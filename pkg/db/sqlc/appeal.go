@@ -0,0 +1,8 @@
+package db
+
+// appeal statuses, stored in appeal.status
+const (
+	AppealStatusPending  = "pending"
+	AppealStatusAccepted = "accepted"
+	AppealStatusDenied   = "denied"
+)
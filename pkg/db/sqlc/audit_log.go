@@ -0,0 +1,13 @@
+package db
+
+// audit log actions, stored in audit_log.action
+const (
+	AuditActionGuildConfigOverwrite = "guild_config.overwrite"
+	AuditActionGuildOwnerTransfer   = "guild.owner_transfer"
+	AuditActionCaseReschedule       = "case.reschedule"
+	AuditActionCaseCancelExpiry     = "case.cancel_expiry"
+	AuditActionAppealDecide         = "appeal.decide"
+	AuditActionRaidResolve          = "raid.resolve"
+	AuditActionGuildLockdown        = "guild.lockdown"
+	AuditActionGuildLockdownLift    = "guild.lockdown_lift"
+)
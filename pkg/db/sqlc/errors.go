@@ -0,0 +1,18 @@
+package db
+
+import (
+	"errors"
+	"github.com/lib/pq"
+)
+
+// IsForeignKeyViolation reports whether the error was caused by a reference to a missing row
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// IsUniqueViolation reports whether the error was caused by a duplicate of a unique key
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -7,31 +7,56 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
+const archiveOrphanedGuilds = `-- name: ArchiveOrphanedGuilds :execrows
+UPDATE guild
+SET archived_at = now()
+WHERE archived_at IS NULL
+  AND orphaned_at < $1
+`
+
+func (q *Queries) ArchiveOrphanedGuilds(ctx context.Context, orphanedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveOrphanedGuilds, orphanedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createOrUpdateGuild = `-- name: CreateOrUpdateGuild :one
 INSERT INTO guild (discord_id, name, icon, owner_discord_id)
 VALUES ($1, $2, $3, $4)
 ON CONFLICT (discord_id) DO UPDATE
     SET name             = $2,
         icon             = $3,
-        owner_discord_id = $4
-RETURNING id, discord_id, owner_discord_id, name, icon
+        owner_discord_id = CASE
+                               WHEN $4 <> '' THEN $4
+                               WHEN guild.owner_discord_id = $5 THEN ''
+                               ELSE guild.owner_discord_id
+            END,
+        orphaned_at      = NULL,
+        archived_at      = NULL
+RETURNING id, discord_id, owner_discord_id, name, icon, bot_present, bot_joined_at, orphaned_at, archived_at
 `
 
 type CreateOrUpdateGuildParams struct {
-	DiscordID      string `json:"discord_id"`
-	Name           string `json:"name"`
-	Icon           string `json:"icon"`
-	OwnerDiscordID string `json:"owner_discord_id"`
+	DiscordID       string `json:"discord_id"`
+	Name            string `json:"name"`
+	Icon            string `json:"icon"`
+	OwnerDiscordID  string `json:"owner_discord_id"`
+	MemberDiscordID string `json:"member_discord_id"`
 }
 
+// owner only changes when the member is the owner, or when the recorded owner is no longer one
 func (q *Queries) CreateOrUpdateGuild(ctx context.Context, arg CreateOrUpdateGuildParams) (Guild, error) {
 	row := q.db.QueryRowContext(ctx, createOrUpdateGuild,
 		arg.DiscordID,
 		arg.Name,
 		arg.Icon,
 		arg.OwnerDiscordID,
+		arg.MemberDiscordID,
 	)
 	var i Guild
 	err := row.Scan(
@@ -40,14 +65,50 @@ func (q *Queries) CreateOrUpdateGuild(ctx context.Context, arg CreateOrUpdateGui
 		&i.OwnerDiscordID,
 		&i.Name,
 		&i.Icon,
+		&i.BotPresent,
+		&i.BotJoinedAt,
+		&i.OrphanedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
+const deleteArchivedGuilds = `-- name: DeleteArchivedGuilds :execrows
+DELETE
+FROM guild
+WHERE archived_at < $1
+`
+
+func (q *Queries) DeleteArchivedGuilds(ctx context.Context, archivedAt sql.NullTime) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteArchivedGuilds, archivedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const flagOrphanedGuilds = `-- name: FlagOrphanedGuilds :execrows
+UPDATE guild g
+SET orphaned_at = now()
+WHERE g.orphaned_at IS NULL
+  AND NOT g.bot_present
+  AND NOT EXISTS(SELECT 1 FROM "user" u WHERE u.discord_id = g.owner_discord_id)
+  AND NOT EXISTS(SELECT 1 FROM user_guild ug WHERE ug.guild_discord_id = g.discord_id)
+`
+
+func (q *Queries) FlagOrphanedGuilds(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, flagOrphanedGuilds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getGuild = `-- name: GetGuild :one
-SELECT g.id, g.discord_id, g.owner_discord_id, g.name, g.icon,
+SELECT g.id, g.discord_id, g.owner_discord_id, g.name, g.icon, g.bot_present, g.bot_joined_at, g.orphaned_at, g.archived_at,
        (gc.json::json -> 'permissions' ->> 'read')::bigint AS config_read,
-       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit
+       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit,
+       coalesce((gc.json::json -> 'permissions' ->> 'everyone_can_read')::boolean, false) AS config_everyone_can_read
 FROM guild g
          INNER JOIN guild_config gc ON gc.id = g.id
 WHERE discord_id = $1
@@ -55,13 +116,18 @@ LIMIT 1
 `
 
 type GetGuildRow struct {
-	ID             int64  `json:"id"`
-	DiscordID      string `json:"discord_id"`
-	OwnerDiscordID string `json:"owner_discord_id"`
-	Name           string `json:"name"`
-	Icon           string `json:"icon"`
-	ConfigRead     int64  `json:"config_read"`
-	ConfigEdit     int64  `json:"config_edit"`
+	ID                    int64        `json:"id"`
+	DiscordID             string       `json:"discord_id"`
+	OwnerDiscordID        string       `json:"owner_discord_id"`
+	Name                  string       `json:"name"`
+	Icon                  string       `json:"icon"`
+	BotPresent            bool         `json:"bot_present"`
+	BotJoinedAt           sql.NullTime `json:"bot_joined_at"`
+	OrphanedAt            sql.NullTime `json:"orphaned_at"`
+	ArchivedAt            sql.NullTime `json:"archived_at"`
+	ConfigRead            int64        `json:"config_read"`
+	ConfigEdit            int64        `json:"config_edit"`
+	ConfigEveryoneCanRead bool         `json:"config_everyone_can_read"`
 }
 
 func (q *Queries) GetGuild(ctx context.Context, discordID string) (GetGuildRow, error) {
@@ -73,8 +139,13 @@ func (q *Queries) GetGuild(ctx context.Context, discordID string) (GetGuildRow,
 		&i.OwnerDiscordID,
 		&i.Name,
 		&i.Icon,
+		&i.BotPresent,
+		&i.BotJoinedAt,
+		&i.OrphanedAt,
+		&i.ArchivedAt,
 		&i.ConfigRead,
 		&i.ConfigEdit,
+		&i.ConfigEveryoneCanRead,
 	)
 	return i, err
 }
@@ -86,8 +157,11 @@ SELECT coalesce(g.id, 0),
        coalesce(g.owner_discord_id, '0'),
        coalesce(g.icon, '#'),
        coalesce(g.name, ''),
+       coalesce(g.bot_present, false)                      AS bot_present,
+       g.bot_joined_at,
        (gc.json::json -> 'permissions' ->> 'read')::bigint AS config_read,
-       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit
+       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit,
+       coalesce((gc.json::json -> 'permissions' ->> 'everyone_can_read')::boolean, false) AS config_everyone_can_read
 FROM user_guild ug
          LEFT OUTER JOIN guild g ON g.discord_id = ug.guild_discord_id
          INNER JOIN guild_config gc ON gc.id = g.id
@@ -102,14 +176,17 @@ type GetUserGuildParams struct {
 }
 
 type GetUserGuildRow struct {
-	ID             int64  `json:"id"`
-	DiscordID      string `json:"discord_id"`
-	Permissions    int64  `json:"permissions"`
-	OwnerDiscordID string `json:"owner_discord_id"`
-	Icon           string `json:"icon"`
-	Name           string `json:"name"`
-	ConfigRead     int64  `json:"config_read"`
-	ConfigEdit     int64  `json:"config_edit"`
+	ID                    int64        `json:"id"`
+	DiscordID             string       `json:"discord_id"`
+	Permissions           int64        `json:"permissions"`
+	OwnerDiscordID        string       `json:"owner_discord_id"`
+	Icon                  string       `json:"icon"`
+	Name                  string       `json:"name"`
+	BotPresent            bool         `json:"bot_present"`
+	BotJoinedAt           sql.NullTime `json:"bot_joined_at"`
+	ConfigRead            int64        `json:"config_read"`
+	ConfigEdit            int64        `json:"config_edit"`
+	ConfigEveryoneCanRead bool         `json:"config_everyone_can_read"`
 }
 
 func (q *Queries) GetUserGuild(ctx context.Context, arg GetUserGuildParams) (GetUserGuildRow, error) {
@@ -122,8 +199,11 @@ func (q *Queries) GetUserGuild(ctx context.Context, arg GetUserGuildParams) (Get
 		&i.OwnerDiscordID,
 		&i.Icon,
 		&i.Name,
+		&i.BotPresent,
+		&i.BotJoinedAt,
 		&i.ConfigRead,
 		&i.ConfigEdit,
+		&i.ConfigEveryoneCanRead,
 	)
 	return i, err
 }
@@ -135,8 +215,11 @@ SELECT coalesce(g.id, 0),
        coalesce(g.owner_discord_id, '0'),
        coalesce(g.icon, '#'),
        coalesce(g.name, ''),
+       coalesce(g.bot_present, false)                      AS bot_present,
+       g.bot_joined_at,
        (gc.json::json -> 'permissions' ->> 'read')::bigint AS config_read,
-       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit
+       (gc.json::json -> 'permissions' ->> 'edit')::bigint AS config_edit,
+       coalesce((gc.json::json -> 'permissions' ->> 'everyone_can_read')::boolean, false) AS config_everyone_can_read
 FROM user_guild ug
          LEFT OUTER JOIN guild g ON g.discord_id = ug.guild_discord_id
          INNER JOIN guild_config gc ON gc.id = g.id
@@ -144,14 +227,17 @@ WHERE ug.account_discord_id = $1
 `
 
 type GetUserGuildsRow struct {
-	ID             int64  `json:"id"`
-	DiscordID      string `json:"discord_id"`
-	Permissions    int64  `json:"permissions"`
-	OwnerDiscordID string `json:"owner_discord_id"`
-	Icon           string `json:"icon"`
-	Name           string `json:"name"`
-	ConfigRead     int64  `json:"config_read"`
-	ConfigEdit     int64  `json:"config_edit"`
+	ID                    int64        `json:"id"`
+	DiscordID             string       `json:"discord_id"`
+	Permissions           int64        `json:"permissions"`
+	OwnerDiscordID        string       `json:"owner_discord_id"`
+	Icon                  string       `json:"icon"`
+	Name                  string       `json:"name"`
+	BotPresent            bool         `json:"bot_present"`
+	BotJoinedAt           sql.NullTime `json:"bot_joined_at"`
+	ConfigRead            int64        `json:"config_read"`
+	ConfigEdit            int64        `json:"config_edit"`
+	ConfigEveryoneCanRead bool         `json:"config_everyone_can_read"`
 }
 
 func (q *Queries) GetUserGuilds(ctx context.Context, accountDiscordID string) ([]GetUserGuildsRow, error) {
@@ -170,8 +256,11 @@ func (q *Queries) GetUserGuilds(ctx context.Context, accountDiscordID string) ([
 			&i.OwnerDiscordID,
 			&i.Icon,
 			&i.Name,
+			&i.BotPresent,
+			&i.BotJoinedAt,
 			&i.ConfigRead,
 			&i.ConfigEdit,
+			&i.ConfigEveryoneCanRead,
 		); err != nil {
 			return nil, err
 		}
@@ -185,3 +274,114 @@ func (q *Queries) GetUserGuilds(ctx context.Context, accountDiscordID string) ([
 	}
 	return items, nil
 }
+
+const setGuildBotJoined = `-- name: SetGuildBotJoined :one
+INSERT INTO guild (discord_id, name, icon, owner_discord_id, bot_present, bot_joined_at)
+VALUES ($1, $2, $3, $4, true, now())
+ON CONFLICT (discord_id) DO UPDATE
+    SET name             = $2,
+        icon             = $3,
+        owner_discord_id = $4,
+        bot_present      = true,
+        bot_joined_at    = coalesce(guild.bot_joined_at, now())
+RETURNING id, discord_id, owner_discord_id, name, icon, bot_present, bot_joined_at, orphaned_at, archived_at
+`
+
+type SetGuildBotJoinedParams struct {
+	DiscordID      string `json:"discord_id"`
+	Name           string `json:"name"`
+	Icon           string `json:"icon"`
+	OwnerDiscordID string `json:"owner_discord_id"`
+}
+
+func (q *Queries) SetGuildBotJoined(ctx context.Context, arg SetGuildBotJoinedParams) (Guild, error) {
+	row := q.db.QueryRowContext(ctx, setGuildBotJoined,
+		arg.DiscordID,
+		arg.Name,
+		arg.Icon,
+		arg.OwnerDiscordID,
+	)
+	var i Guild
+	err := row.Scan(
+		&i.ID,
+		&i.DiscordID,
+		&i.OwnerDiscordID,
+		&i.Name,
+		&i.Icon,
+		&i.BotPresent,
+		&i.BotJoinedAt,
+		&i.OrphanedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const setGuildBotLeft = `-- name: SetGuildBotLeft :one
+UPDATE guild
+SET bot_present   = false,
+    bot_joined_at = NULL
+WHERE discord_id = $1
+RETURNING id, discord_id, owner_discord_id, name, icon, bot_present, bot_joined_at, orphaned_at, archived_at
+`
+
+func (q *Queries) SetGuildBotLeft(ctx context.Context, discordID string) (Guild, error) {
+	row := q.db.QueryRowContext(ctx, setGuildBotLeft, discordID)
+	var i Guild
+	err := row.Scan(
+		&i.ID,
+		&i.DiscordID,
+		&i.OwnerDiscordID,
+		&i.Name,
+		&i.Icon,
+		&i.BotPresent,
+		&i.BotJoinedAt,
+		&i.OrphanedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const updateGuildOwner = `-- name: UpdateGuildOwner :one
+UPDATE guild g
+SET owner_discord_id = $2
+FROM guild prev
+WHERE g.discord_id = $1
+  AND prev.id = g.id
+RETURNING g.id, g.discord_id, g.owner_discord_id, g.name, g.icon, g.bot_present, g.bot_joined_at, g.orphaned_at, g.archived_at, prev.owner_discord_id AS previous_owner_discord_id
+`
+
+type UpdateGuildOwnerParams struct {
+	DiscordID      string `json:"discord_id"`
+	OwnerDiscordID string `json:"owner_discord_id"`
+}
+
+type UpdateGuildOwnerRow struct {
+	ID                     int64        `json:"id"`
+	DiscordID              string       `json:"discord_id"`
+	OwnerDiscordID         string       `json:"owner_discord_id"`
+	Name                   string       `json:"name"`
+	Icon                   string       `json:"icon"`
+	BotPresent             bool         `json:"bot_present"`
+	BotJoinedAt            sql.NullTime `json:"bot_joined_at"`
+	OrphanedAt             sql.NullTime `json:"orphaned_at"`
+	ArchivedAt             sql.NullTime `json:"archived_at"`
+	PreviousOwnerDiscordID string       `json:"previous_owner_discord_id"`
+}
+
+func (q *Queries) UpdateGuildOwner(ctx context.Context, arg UpdateGuildOwnerParams) (UpdateGuildOwnerRow, error) {
+	row := q.db.QueryRowContext(ctx, updateGuildOwner, arg.DiscordID, arg.OwnerDiscordID)
+	var i UpdateGuildOwnerRow
+	err := row.Scan(
+		&i.ID,
+		&i.DiscordID,
+		&i.OwnerDiscordID,
+		&i.Name,
+		&i.Icon,
+		&i.BotPresent,
+		&i.BotJoinedAt,
+		&i.OrphanedAt,
+		&i.ArchivedAt,
+		&i.PreviousOwnerDiscordID,
+	)
+	return i, err
+}
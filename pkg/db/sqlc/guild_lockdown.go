@@ -0,0 +1,7 @@
+package db
+
+// lockdown scopes, stored in guild_lockdown.scope
+const (
+	LockdownScopeAll      = "all"
+	LockdownScopeChannels = "channels"
+)
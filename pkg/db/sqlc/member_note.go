@@ -0,0 +1,8 @@
+package db
+
+// member note revision actions, stored in member_note_revision.action
+const (
+	NoteRevisionCreate = "create"
+	NoteRevisionEdit   = "edit"
+	NoteRevisionDelete = "delete"
+)
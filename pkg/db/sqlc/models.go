@@ -5,16 +5,78 @@
 package db
 
 import (
+	"database/sql"
 	"encoding/json"
 	"time"
 )
 
-type Guild struct {
+type ApiKey struct {
+	ID         int64        `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	HashedKey  []byte       `json:"hashed_key"`
+	Scopes     []string     `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	// guilds the key may act in, empty means every guild
+	GuildDiscordIDs []string `json:"guild_discord_ids"`
+}
+
+type Appeal struct {
 	ID             int64  `json:"id"`
-	DiscordID      string `json:"discord_id"`
-	OwnerDiscordID string `json:"owner_discord_id"`
-	Name           string `json:"name"`
-	Icon           string `json:"icon"`
+	GuildDiscordID string `json:"guild_discord_id"`
+	// the ban case being appealed
+	CaseID            int64           `json:"case_id"`
+	UserDiscordID     string          `json:"user_discord_id"`
+	Answers           json.RawMessage `json:"answers"`
+	Status            string          `json:"status"`
+	ReviewerDiscordID sql.NullString  `json:"reviewer_discord_id"`
+	DecisionReason    string          `json:"decision_reason"`
+	DecidedAt         sql.NullTime    `json:"decided_at"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+type AppealComment struct {
+	ID       int64 `json:"id"`
+	AppealID int64 `json:"appeal_id"`
+	// null if the author deleted the account
+	AuthorDiscordID sql.NullString `json:"author_discord_id"`
+	Content         string         `json:"content"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type AuditLog struct {
+	ID int64 `json:"id"`
+	// null if the actor deleted the account
+	ActorDiscordID sql.NullString  `json:"actor_discord_id"`
+	GuildDiscordID string          `json:"guild_discord_id"`
+	Action         string          `json:"action"`
+	Data           json.RawMessage `json:"data"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type BotInstallation struct {
+	ID                 int64     `json:"id"`
+	GuildDiscordID     string    `json:"guild_discord_id"`
+	InstallerDiscordID string    `json:"installer_discord_id"`
+	Permissions        int64     `json:"permissions"`
+	InstalledAt        time.Time `json:"installed_at"`
+}
+
+type Guild struct {
+	ID        int64  `json:"id"`
+	DiscordID string `json:"discord_id"`
+	// empty if the current owner is unknown
+	OwnerDiscordID string       `json:"owner_discord_id"`
+	Name           string       `json:"name"`
+	Icon           string       `json:"icon"`
+	BotPresent     bool         `json:"bot_present"`
+	BotJoinedAt    sql.NullTime `json:"bot_joined_at"`
+	// set when neither the owner nor any user relation of the guild is left
+	OrphanedAt sql.NullTime `json:"orphaned_at"`
+	ArchivedAt sql.NullTime `json:"archived_at"`
 }
 
 type GuildConfig struct {
@@ -23,6 +85,108 @@ type GuildConfig struct {
 	CreatedAt time.Time       `json:"created_at"`
 }
 
+type GuildLockdown struct {
+	GuildDiscordID    string   `json:"guild_discord_id"`
+	Scope             string   `json:"scope"`
+	ChannelDiscordIDs []string `json:"channel_discord_ids"`
+	Reason            string   `json:"reason"`
+	// null when the lockdown was raised by raid detection or the actor deleted the account
+	ActorDiscordID sql.NullString `json:"actor_discord_id"`
+	IncidentID     sql.NullInt64  `json:"incident_id"`
+	LiftsAt        sql.NullTime   `json:"lifts_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type GuildMember struct {
+	GuildDiscordID  string    `json:"guild_discord_id"`
+	MemberDiscordID string    `json:"member_discord_id"`
+	FirstSeenAt     time.Time `json:"first_seen_at"`
+	LastSeenAt      time.Time `json:"last_seen_at"`
+}
+
+type MemberEvent struct {
+	ID               int64     `json:"id"`
+	GuildDiscordID   string    `json:"guild_discord_id"`
+	Type             string    `json:"type"`
+	MemberDiscordID  string    `json:"member_discord_id"`
+	Username         string    `json:"username"`
+	AccountCreatedAt time.Time `json:"account_created_at"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
+
+type MemberNote struct {
+	ID              int64  `json:"id"`
+	GuildDiscordID  string `json:"guild_discord_id"`
+	MemberDiscordID string `json:"member_discord_id"`
+	// null if the author deleted the account
+	AuthorDiscordID sql.NullString `json:"author_discord_id"`
+	Content         string         `json:"content"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	// deleted notes are kept for their revision history
+	DeletedAt sql.NullTime `json:"deleted_at"`
+}
+
+type MemberNoteRevision struct {
+	ID      int64  `json:"id"`
+	NoteID  int64  `json:"note_id"`
+	Action  string `json:"action"`
+	Content string `json:"content"`
+	// null if the editor deleted the account
+	EditorDiscordID sql.NullString `json:"editor_discord_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type ModerationCase struct {
+	ID             int64  `json:"id"`
+	GuildDiscordID string `json:"guild_discord_id"`
+	// sequential number of the case within the guild
+	CaseNumber         int64  `json:"case_number"`
+	Action             string `json:"action"`
+	TargetDiscordID    string `json:"target_discord_id"`
+	ModeratorDiscordID string `json:"moderator_discord_id"`
+	Reason             string `json:"reason"`
+	// null for permanent or instant actions
+	DurationSeconds sql.NullInt64 `json:"duration_seconds"`
+	ExpiresAt       sql.NullTime  `json:"expires_at"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+type ModerationCaseCounter struct {
+	GuildDiscordID string `json:"guild_discord_id"`
+	LastCaseNumber int64  `json:"last_case_number"`
+}
+
+type RaidIncident struct {
+	ID             int64  `json:"id"`
+	GuildDiscordID string `json:"guild_discord_id"`
+	Status         string `json:"status"`
+	// the latest detection of the incident
+	Detection         json.RawMessage `json:"detection"`
+	LockdownRequested bool            `json:"lockdown_requested"`
+	StartedAt         time.Time       `json:"started_at"`
+	LastDetectedAt    time.Time       `json:"last_detected_at"`
+	// null when the incident was closed after going quiet or the resolver deleted the account
+	ResolvedByDiscordID sql.NullString `json:"resolved_by_discord_id"`
+	ResolvedAt          sql.NullTime   `json:"resolved_at"`
+}
+
+type ScheduledAction struct {
+	ID              int64     `json:"id"`
+	GuildDiscordID  string    `json:"guild_discord_id"`
+	CaseID          int64     `json:"case_id"`
+	Action          string    `json:"action"`
+	TargetDiscordID string    `json:"target_discord_id"`
+	RunAt           time.Time `json:"run_at"`
+	Status          string    `json:"status"`
+	// dispatches postponed because no bot was listening
+	Attempts    int32        `json:"attempts"`
+	CompletedAt sql.NullTime `json:"completed_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	// dispatched actions are delivered again unless the bot acknowledges them in time
+	DispatchedAt sql.NullTime `json:"dispatched_at"`
+}
+
 type User struct {
 	ID            int64  `json:"id"`
 	DiscordID     string `json:"discord_id"`
@@ -35,6 +199,7 @@ type User struct {
 	// color encoded as an integer representation of hexadecimal color code
 	AccentColor int64     `json:"accent_color"`
 	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
 }
 
 type UserGuild struct {
@@ -42,3 +207,9 @@ type UserGuild struct {
 	GuildDiscordID   string `json:"guild_discord_id"`
 	Permissions      int64  `json:"permissions"`
 }
+
+type VerifiedMember struct {
+	GuildDiscordID  string    `json:"guild_discord_id"`
+	MemberDiscordID string    `json:"member_discord_id"`
+	VerifiedAt      time.Time `json:"verified_at"`
+}
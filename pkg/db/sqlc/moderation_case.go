@@ -0,0 +1,10 @@
+package db
+
+// moderation case actions, stored in moderation_case.action
+const (
+	CaseActionWarn  = "warn"
+	CaseActionMute  = "mute"
+	CaseActionKick  = "kick"
+	CaseActionBan   = "ban"
+	CaseActionUnban = "unban"
+)
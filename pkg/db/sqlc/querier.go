@@ -6,23 +6,125 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	// acknowledging twice is harmless, actions cancelled or rescheduled meanwhile are left untouched
+	AckScheduledAction(ctx context.Context, id int64) (ScheduledAction, error)
+	AnonymizeAppealComments(ctx context.Context, authorDiscordID sql.NullString) error
+	AnonymizeAppealReviewers(ctx context.Context, reviewerDiscordID sql.NullString) error
+	AnonymizeAuditLogs(ctx context.Context, actorDiscordID sql.NullString) error
+	AnonymizeGuildLockdownActors(ctx context.Context, actorDiscordID sql.NullString) error
+	AnonymizeMemberNoteRevisions(ctx context.Context, editorDiscordID sql.NullString) error
+	AnonymizeMemberNotes(ctx context.Context, authorDiscordID sql.NullString) error
+	AnonymizeRaidIncidentResolvers(ctx context.Context, resolvedByDiscordID sql.NullString) error
+	ArchiveOrphanedGuilds(ctx context.Context, orphanedAt sql.NullTime) (int64, error)
+	CancelCaseScheduledAction(ctx context.Context, caseID int64) (ScheduledAction, error)
+	CancelTargetScheduledActions(ctx context.Context, arg CancelTargetScheduledActionsParams) (int64, error)
+	// claimed rows are skipped by other replicas, dispatched actions are claimed again if the bot
+	// did not acknowledge them before the deadline
+	ClaimDueScheduledActions(ctx context.Context, arg ClaimDueScheduledActionsParams) ([]ScheduledAction, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateAppeal(ctx context.Context, arg CreateAppealParams) (Appeal, error)
+	CreateAppealComment(ctx context.Context, arg CreateAppealCommentParams) (AppealComment, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
+	CreateBotInstallation(ctx context.Context, arg CreateBotInstallationParams) (BotInstallation, error)
+	// lockdowns of raid detection never replace the existing one
+	CreateIncidentGuildLockdown(ctx context.Context, arg CreateIncidentGuildLockdownParams) (GuildLockdown, error)
+	CreateMemberEvent(ctx context.Context, arg CreateMemberEventParams) error
+	CreateMemberNote(ctx context.Context, arg CreateMemberNoteParams) (MemberNote, error)
+	CreateMemberNoteRevision(ctx context.Context, arg CreateMemberNoteRevisionParams) (MemberNoteRevision, error)
+	CreateModerationCase(ctx context.Context, arg CreateModerationCaseParams) (ModerationCase, error)
 	CreateOrUpdateGuild(ctx context.Context, arg CreateOrUpdateGuildParams) (Guild, error)
 	CreateOrUpdateGuildConfig(ctx context.Context, arg CreateOrUpdateGuildConfigParams) (GuildConfig, error)
 	CreateOrUpdateUser(ctx context.Context, arg CreateOrUpdateUserParams) (User, error)
 	CreateOrUpdateUserGuildRel(ctx context.Context, arg CreateOrUpdateUserGuildRelParams) (UserGuild, error)
+	CreateRaidIncident(ctx context.Context, arg CreateRaidIncidentParams) (RaidIncident, error)
 	CreateUserGuildRel(ctx context.Context, arg CreateUserGuildRelParams) (UserGuild, error)
+	// verifying again keeps the time of the first verification
+	CreateVerifiedMember(ctx context.Context, arg CreateVerifiedMemberParams) (VerifiedMember, error)
+	DecideAppeal(ctx context.Context, arg DecideAppealParams) (Appeal, error)
+	DeleteArchivedGuilds(ctx context.Context, archivedAt sql.NullTime) (int64, error)
+	// rows are locked with SKIP LOCKED, so every replica lifts different lockdowns,
+	// audit logs of the lifts are written by the same statement
+	DeleteExpiredGuildLockdowns(ctx context.Context, arg DeleteExpiredGuildLockdownsParams) ([]GuildLockdown, error)
+	DeleteGuildLockdown(ctx context.Context, guildDiscordID string) (GuildLockdown, error)
+	DeleteMemberEventsBefore(ctx context.Context, occurredAt time.Time) (int64, error)
+	DeleteMemberNote(ctx context.Context, arg DeleteMemberNoteParams) (MemberNote, error)
+	DeleteStaleUserGuildRels(ctx context.Context, arg DeleteStaleUserGuildRelsParams) error
+	DeleteUser(ctx context.Context, discordID string) (int64, error)
+	// comments of the deleted appeals are removed by cascade
+	DeleteUserAppeals(ctx context.Context, userDiscordID string) error
+	DeleteUserGuildRel(ctx context.Context, arg DeleteUserGuildRelParams) error
+	DeleteUserGuildRels(ctx context.Context, accountDiscordID string) error
+	DeleteUserMemberEvents(ctx context.Context, memberDiscordID string) error
+	DeleteUserVerifiedMembers(ctx context.Context, memberDiscordID string) error
+	FlagOrphanedGuilds(ctx context.Context) (int64, error)
+	GetApiKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetApiKeys(ctx context.Context) ([]ApiKey, error)
+	GetAppeal(ctx context.Context, arg GetAppealParams) (Appeal, error)
+	GetAppealComments(ctx context.Context, appealID int64) ([]AppealComment, error)
+	// deleted notes are returned too, they are still stored
+	GetAuthoredMemberNotes(ctx context.Context, authorDiscordID sql.NullString) ([]MemberNote, error)
 	GetGuild(ctx context.Context, discordID string) (GetGuildRow, error)
+	// empty status matches every appeal, zero before_id starts from the newest appeal
+	GetGuildAppeals(ctx context.Context, arg GetGuildAppealsParams) ([]Appeal, error)
 	GetGuildConfig(ctx context.Context, discordID string) (GuildConfig, error)
+	GetGuildLockdown(ctx context.Context, guildDiscordID string) (GuildLockdown, error)
+	GetGuildMember(ctx context.Context, arg GetGuildMemberParams) (GuildMember, error)
+	GetGuildMemberEvents(ctx context.Context, arg GetGuildMemberEventsParams) ([]MemberEvent, error)
 	GetGuildsConfigs(ctx context.Context) ([]GuildConfig, error)
+	GetInactiveUsers(ctx context.Context, arg GetInactiveUsersParams) ([]string, error)
+	GetLatestCaseAppeal(ctx context.Context, caseID int64) (Appeal, error)
+	// deleted notes are returned too, so their history stays readable
+	GetMemberNote(ctx context.Context, arg GetMemberNoteParams) (MemberNote, error)
+	GetMemberNoteRevisions(ctx context.Context, noteID int64) ([]MemberNoteRevision, error)
+	GetMemberNotes(ctx context.Context, arg GetMemberNotesParams) ([]MemberNote, error)
+	GetModerationCase(ctx context.Context, arg GetModerationCaseParams) (ModerationCase, error)
+	GetModerationCaseByID(ctx context.Context, id int64) (ModerationCase, error)
+	GetModerationCases(ctx context.Context, arg GetModerationCasesParams) ([]ModerationCase, error)
+	GetOpenRaidIncident(ctx context.Context, guildDiscordID string) (RaidIncident, error)
+	GetRaidIncident(ctx context.Context, arg GetRaidIncidentParams) (RaidIncident, error)
+	// zero before_id starts from the newest incident
+	GetRaidIncidents(ctx context.Context, arg GetRaidIncidentsParams) ([]RaidIncident, error)
+	GetScheduledAction(ctx context.Context, id int64) (ScheduledAction, error)
+	// the newest ban or unban case of the target in each guild tells whether the target is banned there
+	GetTargetLatestBanCases(ctx context.Context, targetDiscordID string) ([]ModerationCase, error)
+	// the newest case of the target among the actions, older cases are superseded by it
+	GetTargetLatestCase(ctx context.Context, arg GetTargetLatestCaseParams) (ModerationCase, error)
+	GetTargetModerationCases(ctx context.Context, arg GetTargetModerationCasesParams) ([]ModerationCase, error)
 	GetUser(ctx context.Context, discordID string) (User, error)
+	GetUserAppealComments(ctx context.Context, authorDiscordID sql.NullString) ([]AppealComment, error)
+	GetUserAppeals(ctx context.Context, userDiscordID string) ([]Appeal, error)
+	GetUserAuditLogs(ctx context.Context, actorDiscordID sql.NullString) ([]AuditLog, error)
 	GetUserGuild(ctx context.Context, arg GetUserGuildParams) (GetUserGuildRow, error)
 	GetUserGuildRel(ctx context.Context, arg GetUserGuildRelParams) (UserGuild, error)
 	GetUserGuilds(ctx context.Context, accountDiscordID string) ([]GetUserGuildsRow, error)
+	GetUserMemberEvents(ctx context.Context, memberDiscordID string) ([]MemberEvent, error)
+	GetUserVerifiedMembers(ctx context.Context, memberDiscordID string) ([]VerifiedMember, error)
+	GetVerifiedMember(ctx context.Context, arg GetVerifiedMemberParams) (VerifiedMember, error)
+	// hands the claimed action back, so it does not wait for the acknowledgement deadline
+	PostponeScheduledAction(ctx context.Context, arg PostponeScheduledActionParams) error
+	// reports may arrive out of order, so seen times only ever widen
+	RecordGuildMemberSeen(ctx context.Context, arg RecordGuildMemberSeenParams) (GuildMember, error)
+	ResolveRaidIncident(ctx context.Context, arg ResolveRaidIncidentParams) (RaidIncident, error)
+	RevokeApiKey(ctx context.Context, prefix string) (ApiKey, error)
+	RotateApiKey(ctx context.Context, arg RotateApiKeyParams) (ApiKey, error)
+	// rescheduling revives cancelled actions, executed actions are never scheduled again
+	ScheduleCaseAction(ctx context.Context, arg ScheduleCaseActionParams) (ScheduledAction, error)
+	SetGuildBotJoined(ctx context.Context, arg SetGuildBotJoinedParams) (Guild, error)
+	SetGuildBotLeft(ctx context.Context, discordID string) (Guild, error)
+	// locking an already locked guild replaces its lockdown
+	SetGuildLockdown(ctx context.Context, arg SetGuildLockdownParams) (GuildLockdown, error)
+	TouchApiKey(ctx context.Context, id int64) error
 	TryCreateGuildConfig(ctx context.Context, arg TryCreateGuildConfigParams) (GuildConfig, error)
 	UpdateGuildConfig(ctx context.Context, arg UpdateGuildConfigParams) error
+	UpdateGuildOwner(ctx context.Context, arg UpdateGuildOwnerParams) (UpdateGuildOwnerRow, error)
+	UpdateMemberNote(ctx context.Context, arg UpdateMemberNoteParams) (MemberNote, error)
+	UpdateModerationCaseExpiry(ctx context.Context, arg UpdateModerationCaseExpiryParams) (ModerationCase, error)
+	UpdateRaidIncidentDetection(ctx context.Context, arg UpdateRaidIncidentDetectionParams) (RaidIncident, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,7 @@
+package db
+
+// raid incident statuses, stored in raid_incident.status
+const (
+	RaidIncidentStatusOpen     = "open"
+	RaidIncidentStatusResolved = "resolved"
+)
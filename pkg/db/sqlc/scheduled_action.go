@@ -0,0 +1,39 @@
+package db
+
+// scheduled actions, stored in scheduled_action.action
+const (
+	ScheduledActionUnmute = "unmute"
+	ScheduledActionUnban  = "unban"
+)
+
+// scheduled action statuses, stored in scheduled_action.status
+const (
+	ScheduledActionStatusPending    = "pending"
+	ScheduledActionStatusDispatched = "dispatched"
+	ScheduledActionStatusDone       = "done"
+	ScheduledActionStatusCancelled  = "cancelled"
+)
+
+// LiftingAction returns scheduled action which lifts punishment of the case action.
+// Unban cases map to unban as well, so recording one supersedes a pending unban.
+func LiftingAction(caseAction string) (string, bool) {
+	switch caseAction {
+	case CaseActionMute:
+		return ScheduledActionUnmute, true
+	case CaseActionBan, CaseActionUnban:
+		return ScheduledActionUnban, true
+	}
+	return "", false
+}
+
+// LiftedActions returns case actions whose punishment is lifted by the scheduled action,
+// the newest case among them decides whether the target is punished
+func LiftedActions(liftingAction string) []string {
+	switch liftingAction {
+	case ScheduledActionUnmute:
+		return []string{CaseActionMute}
+	case ScheduledActionUnban:
+		return []string{CaseActionBan, CaseActionUnban}
+	}
+	return nil
+}
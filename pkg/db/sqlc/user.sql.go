@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createOrUpdateUser = `-- name: CreateOrUpdateUser :one
@@ -19,9 +20,10 @@ ON CONFLICT (discord_id) DO UPDATE
         verified      = $4,
         email         = $5,
         avatar        = $6,
-        banner        = $8,
-        accent_color  = $9
-RETURNING id, discord_id, username, discriminator, verified, email, avatar, banner, accent_color, created_at
+        banner        = $7,
+        accent_color  = $8,
+        last_login_at = now()
+RETURNING id, discord_id, username, discriminator, verified, email, avatar, banner, accent_color, created_at, last_login_at
 `
 
 type CreateOrUpdateUserParams struct {
@@ -33,7 +35,6 @@ type CreateOrUpdateUserParams struct {
 	Avatar        string `json:"avatar"`
 	Banner        string `json:"banner"`
 	AccentColor   int64  `json:"accent_color"`
-	AccentColor_2 int64  `json:"accent_color_2"`
 }
 
 func (q *Queries) CreateOrUpdateUser(ctx context.Context, arg CreateOrUpdateUserParams) (User, error) {
@@ -46,7 +47,6 @@ func (q *Queries) CreateOrUpdateUser(ctx context.Context, arg CreateOrUpdateUser
 		arg.Avatar,
 		arg.Banner,
 		arg.AccentColor,
-		arg.AccentColor_2,
 	)
 	var i User
 	err := row.Scan(
@@ -60,12 +60,63 @@ func (q *Queries) CreateOrUpdateUser(ctx context.Context, arg CreateOrUpdateUser
 		&i.Banner,
 		&i.AccentColor,
 		&i.CreatedAt,
+		&i.LastLoginAt,
 	)
 	return i, err
 }
 
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE
+FROM "user"
+WHERE discord_id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, discordID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUser, discordID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getInactiveUsers = `-- name: GetInactiveUsers :many
+SELECT discord_id
+FROM "user"
+WHERE last_login_at < $1
+ORDER BY last_login_at
+LIMIT $2
+`
+
+type GetInactiveUsersParams struct {
+	LastLoginAt time.Time `json:"last_login_at"`
+	Limit       int32     `json:"limit"`
+}
+
+func (q *Queries) GetInactiveUsers(ctx context.Context, arg GetInactiveUsersParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getInactiveUsers, arg.LastLoginAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var discord_id string
+		if err := rows.Scan(&discord_id); err != nil {
+			return nil, err
+		}
+		items = append(items, discord_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUser = `-- name: GetUser :one
-SELECT id, discord_id, username, discriminator, verified, email, avatar, banner, accent_color, created_at
+SELECT id, discord_id, username, discriminator, verified, email, avatar, banner, accent_color, created_at, last_login_at
 FROM "user"
 WHERE discord_id = $1
 LIMIT 1
@@ -85,6 +136,7 @@ func (q *Queries) GetUser(ctx context.Context, discordID string) (User, error) {
 		&i.Banner,
 		&i.AccentColor,
 		&i.CreatedAt,
+		&i.LastLoginAt,
 	)
 	return i, err
 }
@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const createOrUpdateUserGuildRel = `-- name: CreateOrUpdateUserGuildRel :one
@@ -49,6 +51,51 @@ func (q *Queries) CreateUserGuildRel(ctx context.Context, arg CreateUserGuildRel
 	return i, err
 }
 
+const deleteStaleUserGuildRels = `-- name: DeleteStaleUserGuildRels :exec
+DELETE
+FROM user_guild
+WHERE account_discord_id = $1
+  AND NOT (guild_discord_id = ANY ($2::varchar[]))
+`
+
+type DeleteStaleUserGuildRelsParams struct {
+	AccountDiscordID string   `json:"account_discord_id"`
+	GuildDiscordIds  []string `json:"guild_discord_ids"`
+}
+
+func (q *Queries) DeleteStaleUserGuildRels(ctx context.Context, arg DeleteStaleUserGuildRelsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteStaleUserGuildRels, arg.AccountDiscordID, pq.Array(arg.GuildDiscordIds))
+	return err
+}
+
+const deleteUserGuildRel = `-- name: DeleteUserGuildRel :exec
+DELETE
+FROM user_guild
+WHERE account_discord_id = $1
+  AND guild_discord_id = $2
+`
+
+type DeleteUserGuildRelParams struct {
+	AccountDiscordID string `json:"account_discord_id"`
+	GuildDiscordID   string `json:"guild_discord_id"`
+}
+
+func (q *Queries) DeleteUserGuildRel(ctx context.Context, arg DeleteUserGuildRelParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserGuildRel, arg.AccountDiscordID, arg.GuildDiscordID)
+	return err
+}
+
+const deleteUserGuildRels = `-- name: DeleteUserGuildRels :exec
+DELETE
+FROM user_guild
+WHERE account_discord_id = $1
+`
+
+func (q *Queries) DeleteUserGuildRels(ctx context.Context, accountDiscordID string) error {
+	_, err := q.db.ExecContext(ctx, deleteUserGuildRels, accountDiscordID)
+	return err
+}
+
 const getUserGuildRel = `-- name: GetUserGuildRel :one
 SELECT account_discord_id, guild_discord_id, permissions
 FROM user_guild
@@ -0,0 +1,25 @@
+package forms
+
+type AppealURI struct {
+	DiscordID string `uri:"discord_id" binding:"required"`
+	AppealID  int64  `uri:"appeal_id" binding:"required,min=1"`
+}
+
+type GetAppealsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending accepted denied"`
+	Before int64  `form:"before" binding:"min=0"`
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type SubmitAppealJSON struct {
+	Answers map[string]string `json:"answers" binding:"required"`
+}
+
+type AppealCommentJSON struct {
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
+type DecideAppealJSON struct {
+	Decision string `json:"decision" binding:"required,oneof=accept deny"`
+	Reason   string `json:"reason" binding:"max=1024"`
+}
@@ -0,0 +1,27 @@
+package forms
+
+import (
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"time"
+)
+
+type BotEvaluateAutomodJSON struct {
+	ChannelDiscordID string    `json:"channel_discord_id" binding:"required"`
+	AuthorDiscordID  string    `json:"author_discord_id" binding:"required"`
+	RoleDiscordIDs   []string  `json:"role_discord_ids" binding:"max=250"`
+	Content          string    `json:"content" binding:"max=4000"`
+	SentAt           time.Time `json:"sent_at"`
+}
+
+type TestAutomodMessageJSON struct {
+	Content          string   `json:"content" binding:"max=4000"`
+	ChannelDiscordID string   `json:"channel_discord_id"`
+	AuthorDiscordID  string   `json:"author_discord_id"`
+	RoleDiscordIDs   []string `json:"role_discord_ids" binding:"max=250"`
+}
+
+// TestAutomodJSON evaluates sample messages against the draft or the stored config when draft is omitted
+type TestAutomodJSON struct {
+	Messages []TestAutomodMessageJSON `json:"messages" binding:"required,min=1,max=50,dive"`
+	Draft    *objects.AutomodConfig   `json:"draft"`
+}
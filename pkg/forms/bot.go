@@ -0,0 +1,15 @@
+package forms
+
+type BotJoinGuildJSON struct {
+	Name           string `json:"name" binding:"required"`
+	Icon           string `json:"icon"`
+	OwnerDiscordID string `json:"owner_discord_id" binding:"required"`
+}
+
+type BotTransferGuildOwnerJSON struct {
+	OwnerDiscordID string `json:"owner_discord_id" binding:"required"`
+}
+
+type BotAckCommandURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
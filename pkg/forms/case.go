@@ -0,0 +1,34 @@
+package forms
+
+import "time"
+
+type BotCreateCaseJSON struct {
+	Action             string `json:"action" binding:"required,oneof=warn mute kick ban unban"`
+	TargetDiscordID    string `json:"target_discord_id" binding:"required"`
+	ModeratorDiscordID string `json:"moderator_discord_id" binding:"required"`
+	Reason             string `json:"reason" binding:"max=1024"`
+	DurationSeconds    int64  `json:"duration_seconds" binding:"min=0"`
+}
+
+type GetCaseURI struct {
+	DiscordID  string `uri:"discord_id" binding:"required"`
+	CaseNumber int64  `uri:"case_number" binding:"required,min=1"`
+}
+
+type GetCasesQuery struct {
+	Action             string `form:"action" binding:"omitempty,oneof=warn mute kick ban unban"`
+	TargetDiscordID    string `form:"target_discord_id"`
+	ModeratorDiscordID string `form:"moderator_discord_id"`
+	Before             int64  `form:"before" binding:"min=0"`
+	Limit              int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type RescheduleCaseExpiryJSON struct {
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+}
+
+type BotEscalateInfractionJSON struct {
+	Action          string `json:"action" binding:"required,oneof=warn mute kick ban"`
+	TargetDiscordID string `json:"target_discord_id" binding:"required"`
+	DurationSeconds int64  `json:"duration_seconds" binding:"min=0"`
+}
@@ -7,3 +7,7 @@ type GetGuildURI struct {
 type GetUserGuildURI struct {
 	DiscordID string `uri:"discord_id" binding:"required"`
 }
+
+type GetUserGuildsQuery struct {
+	BotPresent *bool `form:"bot_present"`
+}
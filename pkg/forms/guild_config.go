@@ -1,6 +1,7 @@
 package forms
 
 import (
+	"encoding/json"
 	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
 )
 
@@ -14,7 +15,31 @@ type GetGuildConfigPresetURI struct {
 }
 
 type OverwriteGuildConfigJSON struct {
-	Permissions objects.GuildConfigPermissions `json:"permissions" binding:"required"`
-	Data        objects.GuildConfigData        `json:"data" binding:"required"`
-	Preset      string                         `json:"preset" binding:"required,oneof=default custom"`
+	Permissions     objects.GuildConfigPermissions `json:"permissions" binding:"required"`
+	CasePermissions objects.CasePermissions        `json:"case_permissions"`
+	Data            objects.GuildConfigData        `json:"data" binding:"required"`
+	Preset          string                         `json:"preset" binding:"required,oneof=default custom"`
+}
+
+// NewOverwriteGuildConfigJSON keeps default values of sections which may be omitted
+func NewOverwriteGuildConfigJSON() OverwriteGuildConfigJSON {
+	defaultConfig := objects.NewDefaultGuildConfig()
+	return OverwriteGuildConfigJSON{
+		Permissions:     objects.GuildConfigPermissions{Lockdown: defaultConfig.Permissions.Lockdown},
+		CasePermissions: defaultConfig.CasePermissions,
+	}
+}
+
+const (
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+)
+
+// BulkGuildConfigJSON applies either JSON merge patch or preset to configs of the guilds
+type BulkGuildConfigJSON struct {
+	GuildDiscordIDs []string        `json:"guild_discord_ids" binding:"required,min=1,max=100,dive,required"`
+	Patch           json.RawMessage `json:"patch"`
+	Preset          string          `json:"preset" binding:"omitempty,oneof=default"`
+	Mode            string          `json:"mode" binding:"required,oneof=atomic best_effort"`
+	DryRun          bool            `json:"dry_run"`
 }
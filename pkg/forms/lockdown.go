@@ -0,0 +1,11 @@
+package forms
+
+import "time"
+
+type LockdownJSON struct {
+	Scope string `json:"scope" binding:"required,oneof=all channels"`
+	// ChannelDiscordIDs are locked when scope is channels
+	ChannelDiscordIDs []string   `json:"channel_discord_ids" binding:"max=100,dive,required,numeric"`
+	Reason            string     `json:"reason" binding:"required,max=512"`
+	LiftsAt           *time.Time `json:"lifts_at"`
+}
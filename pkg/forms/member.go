@@ -0,0 +1,27 @@
+package forms
+
+import "time"
+
+type MemberURI struct {
+	DiscordID string `uri:"discord_id" binding:"required"`
+	MemberID  string `uri:"member_id" binding:"required"`
+}
+
+type MemberNoteURI struct {
+	DiscordID string `uri:"discord_id" binding:"required"`
+	MemberID  string `uri:"member_id" binding:"required"`
+	NoteID    int64  `uri:"note_id" binding:"required,min=1"`
+}
+
+type MemberNoteJSON struct {
+	Content string `json:"content" binding:"required,max=2000"`
+}
+
+type BotMemberSeenJSON struct {
+	MemberDiscordID string    `json:"member_discord_id" binding:"required"`
+	SeenAt          time.Time `json:"seen_at" binding:"required"`
+}
+
+type BotReportMembersSeenJSON struct {
+	Members []BotMemberSeenJSON `json:"members" binding:"required,min=1,max=100,dive"`
+}
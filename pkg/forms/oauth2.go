@@ -2,7 +2,30 @@ package forms
 
 type GenerateURLForm struct{}
 
+type GetNewOauth2URLQuery struct {
+	RedirectURL string `form:"redirect_url"`
+	Native      bool   `form:"native"`
+}
+
+type PollOauth2FlowRequest struct {
+	State string `uri:"state" binding:"required"`
+}
+
+type PollOauth2FlowQuery struct {
+	Wait int `form:"wait" binding:"min=0,max=60"`
+}
+
 type Oauth2RedirectForm struct {
 	Code  string `form:"code" binding:"required"`
 	State string `form:"state" binding:"required"`
 }
+
+type GetNewInviteBotURLQuery struct {
+	GuildID string `form:"guild_id"`
+}
+
+// InviteBotRedirectForm omits guild_id and permissions sent by Discord, they are taken from the token response
+type InviteBotRedirectForm struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
@@ -0,0 +1,30 @@
+package forms
+
+import "time"
+
+type RaidIncidentURI struct {
+	DiscordID  string `uri:"discord_id" binding:"required"`
+	IncidentID int64  `uri:"incident_id" binding:"required,min=1"`
+}
+
+type GetRaidIncidentsQuery struct {
+	Before int64 `form:"before" binding:"min=0"`
+	Limit  int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type GetRaidTimelineQuery struct {
+	Since         time.Time `form:"since"`
+	Until         time.Time `form:"until"`
+	BucketSeconds int       `form:"bucket_seconds" binding:"omitempty,min=10,max=86400"`
+}
+
+type BotMemberEventJSON struct {
+	Type            string    `json:"type" binding:"required,oneof=join leave"`
+	MemberDiscordID string    `json:"member_discord_id" binding:"required,numeric"`
+	Username        string    `json:"username" binding:"max=100"`
+	OccurredAt      time.Time `json:"occurred_at" binding:"required"`
+}
+
+type BotReportMemberEventsJSON struct {
+	Events []BotMemberEventJSON `json:"events" binding:"required,min=1,max=100,dive"`
+}
@@ -3,3 +3,7 @@ package forms
 type GetUserURI struct {
 	DiscordID string `uri:"discord_id" binding:"required"`
 }
+
+type ExportUserQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
@@ -0,0 +1,7 @@
+package forms
+
+type SubmitVerificationJSON struct {
+	Challenge string `json:"challenge" binding:"required,max=64"`
+	// Solution is appended to the challenge by the client until the hash meets difficulty
+	Solution string `json:"solution" binding:"max=64"`
+}
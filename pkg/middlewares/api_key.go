@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/apikey"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	AuthorizationTypeAPIKey = "apikey"
+	APIKeyPayloadKey        = "api_key_payload"
+)
+
+// NewAPIKeyMiddleware authenticates machine clients, such as Sentinel bot, by their API keys
+func NewAPIKeyMiddleware(store db.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader(AuthorizationHeaderKey)
+		if len(authHeader) == 0 {
+			err := errors.New("authentication header is not provided")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+
+		fields := strings.Fields(authHeader)
+		if len(fields) != 2 {
+			err := errors.New("invalid authentication header format")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+
+		authType := strings.ToLower(fields[0])
+		if authType != AuthorizationTypeAPIKey {
+			err := fmt.Errorf("unsupported authentication type: %s", authType)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+
+		plainKey := fields[1]
+		prefix, err := apikey.Parse(plainKey)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+
+		key, err := store.GetApiKeyByPrefix(c, prefix)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err := errors.New("invalid api key")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		if !apikey.Verify(plainKey, key.HashedKey) {
+			err := errors.New("invalid api key")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+		if key.RevokedAt.Valid {
+			err := errors.New("api key is revoked")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+		if key.ExpiresAt.Valid && time.Now().After(key.ExpiresAt.Time) {
+			err := errors.New("api key has expired")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			return
+		}
+
+		// usage tracking is best effort
+		_ = store.TouchApiKey(c, key.ID)
+
+		c.Set(APIKeyPayloadKey, key)
+		c.Next()
+	}
+}
+
+// NewScopeMiddleware requires authenticated API key to be granted all the scopes and the guild of the route
+func NewScopeMiddleware(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.MustGet(APIKeyPayloadKey).(db.ApiKey)
+		if !apikey.HasScopes(key.Scopes, scopes...) {
+			err := fmt.Errorf("api key requires scopes: %s", strings.Join(scopes, ", "))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		if guildDiscordID := c.Param("discord_id"); guildDiscordID != "" && !apikey.HasGuild(key.GuildDiscordIDs, guildDiscordID) {
+			err := errors.New("api key is not granted access to the guild")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,263 @@
+package middlewares
+
+import (
+	"database/sql"
+	"fmt"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/apikey"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	key, err := apikey.Generate()
+	require.NoError(t, err)
+	apiKey := db.ApiKey{
+		ID:        1,
+		Name:      "bot",
+		Prefix:    key.Prefix,
+		HashedKey: key.Hash,
+		Scopes:    []string{apikey.ScopeConfigsRead},
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, r *http.Request)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, key.Plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().
+					TouchApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, r *http.Request) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "UnsupportedAuthorizationType",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeBearer, key.Plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "InvalidKeyFormat",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, "not_a_key"))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "KeyNotFound",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, key.Plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "WrongSecret",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				other, err := apikey.Generate()
+				require.NoError(t, err)
+				plain := fmt.Sprintf("snt_%s_%s", key.Prefix, other.Plain[len("snt_")+len(other.Prefix)+1:])
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+					Times(1).
+					Return(apiKey, nil)
+				store.EXPECT().TouchApiKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "Revoked",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, key.Plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				revokedKey := apiKey
+				revokedKey.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+					Times(1).
+					Return(revokedKey, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "Expired",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, key.Plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				expiredKey := apiKey
+				expiredKey.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+					Times(1).
+					Return(expiredKey, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetApiKeyByPrefix",
+			setupAuth: func(t *testing.T, r *http.Request) {
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeAPIKey, key.Plain))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetApiKeyByPrefix(gomock.Any(), gomock.Eq(key.Prefix)).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			router := gin.New()
+			router.GET("/bot", NewAPIKeyMiddleware(store), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{})
+			})
+
+			req, err := http.NewRequest(http.MethodGet, "/bot", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, req)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	testCases := []struct {
+		name          string
+		granted       []string
+		guilds        []string
+		required      []string
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			granted:  []string{apikey.ScopeConfigsRead, apikey.ScopeGuildsPresenceWrite},
+			required: []string{apikey.ScopeGuildsPresenceWrite},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:     "OK/GrantedGuild",
+			granted:  []string{apikey.ScopeConfigsRead},
+			guilds:   []string{"2", "1"},
+			required: []string{apikey.ScopeConfigsRead},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:     "Forbidden",
+			granted:  []string{apikey.ScopeConfigsRead},
+			required: []string{apikey.ScopeGuildsPresenceWrite},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:     "Forbidden/OtherGuild",
+			granted:  []string{apikey.ScopeConfigsRead},
+			guilds:   []string{"2"},
+			required: []string{apikey.ScopeConfigsRead},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			setKey := func(c *gin.Context) {
+				c.Set(APIKeyPayloadKey, db.ApiKey{Scopes: tc.granted, GuildDiscordIDs: tc.guilds})
+			}
+			router.GET("/bot/guilds/:discord_id", setKey, NewScopeMiddleware(tc.required...), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{})
+			})
+
+			req, err := http.NewRequest(http.MethodGet, "/bot/guilds/1", nil)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -1,12 +1,16 @@
 package middlewares
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
@@ -15,10 +19,30 @@ const (
 	AuthorizationPayloadKey = "authorization_payload"
 )
 
+// Cookies of the session mode used by the web dashboard
+const (
+	RefreshTokenCookie     = "refresh_token"
+	RefreshTokenCookiePath = "/api/v1/auth"
+	CSRFTokenCookie        = "csrf_token"
+	CSRFTokenHeaderKey     = "X-CSRF-Token"
+)
+
+const (
+	StreamTicketQueryKey = "ticket"
+	StreamTicketDuration = 30 * time.Second
+)
+
+// NewAuthMiddleware authenticates requests by bearer token in Authorization header. If it is absent
+// the refresh token cookie is used, and state-changing requests then must pass CSRF double-submit check
 func NewAuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader(AuthorizationHeaderKey)
 		if len(authHeader) == 0 {
+			if cookieToken, err := c.Cookie(RefreshTokenCookie); err == nil && cookieToken != "" {
+				authenticateCookie(c, tokenMaker, cookieToken)
+				return
+			}
+
 			err := errors.New("authentication header is not provided")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
 			return
@@ -49,3 +73,79 @@ func NewAuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// NewStreamAuthMiddleware authenticates event streams by single-use ticket from the query, since browser
+// WebSocket and EventSource can not send Authorization header. Requests without ticket are authenticated as usual.
+// Ticket is exchanged for guild-scoped payload with capabilities of the user computed when the ticket was issued
+func NewStreamAuthMiddleware(memStore memdb.Store, tokenMaker token.Maker) gin.HandlerFunc {
+	authMiddleware := NewAuthMiddleware(tokenMaker)
+	return func(c *gin.Context) {
+		ticket := c.Query(StreamTicketQueryKey)
+		if ticket == "" {
+			authMiddleware(c)
+			return
+		}
+
+		streamTicket, err := memStore.ConsumeStreamTicket(c, ticket)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				err := errors.New("invalid or already used stream ticket")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+		if streamTicket.GuildDiscordID != c.Param("discord_id") {
+			err := errors.New("stream ticket is issued for another guild")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+
+		payload := token.NewGuildPayload(streamTicket.UserDiscordID, token.GuildClaims{
+			DiscordID:    streamTicket.GuildDiscordID,
+			Capabilities: streamTicket.Capabilities,
+		}, StreamTicketDuration)
+		c.Set(AuthorizationPayloadKey, payload)
+		c.Next()
+	}
+}
+
+// NewUserOnlyMiddleware rejects guild-scoped tokens on account routes, since these tokens are handed out
+// with narrow rights in a single guild and must not act on behalf of the whole account
+func NewUserOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := c.MustGet(AuthorizationPayloadKey).(*token.Payload)
+		if payload.Guild != nil {
+			err := errors.New("guild-scoped token is not accepted by account routes")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+func authenticateCookie(c *gin.Context, tokenMaker token.Maker, cookieToken string) {
+	if !isSafeMethod(c.Request.Method) {
+		csrfCookie, _ := c.Cookie(CSRFTokenCookie)
+		csrfHeader := c.GetHeader(CSRFTokenHeaderKey)
+		if csrfCookie == "" || subtle.ConstantTimeCompare([]byte(csrfCookie), []byte(csrfHeader)) != 1 {
+			err := errors.New("invalid csrf token")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	payload, err := tokenMaker.VerifyToken(cookieToken)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.Set(AuthorizationPayloadKey, payload)
+	c.Next()
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
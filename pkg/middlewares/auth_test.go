@@ -1,10 +1,15 @@
 package middlewares
 
 import (
+	"encoding/json"
 	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
@@ -101,3 +106,309 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddlewareCookie(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	csrfToken := utils.RandomString(32)
+
+	testCases := []struct {
+		name          string
+		method        string
+		setupAuth     func(t *testing.T, r *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK/SafeMethod",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refreshToken})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:   "OK/CSRFTokenMatches",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refreshToken})
+				r.AddCookie(&http.Cookie{Name: CSRFTokenCookie, Value: csrfToken})
+				r.Header.Set(CSRFTokenHeaderKey, csrfToken)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:   "Forbidden/CSRFHeaderMissing",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refreshToken})
+				r.AddCookie(&http.Cookie{Name: CSRFTokenCookie, Value: csrfToken})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:   "Forbidden/CSRFTokenMismatch",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refreshToken})
+				r.AddCookie(&http.Cookie{Name: CSRFTokenCookie, Value: csrfToken})
+				r.Header.Set(CSRFTokenHeaderKey, utils.RandomString(32))
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:   "Forbidden/CSRFCookieMissing",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refreshToken})
+				r.Header.Set(CSRFTokenHeaderKey, "")
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name:   "OK/HeaderTakesPrecedence",
+			method: http.MethodPost,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				accessToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeBearer, accessToken))
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: "invalid"})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name:   "Unauthorized/ExpiredCookie",
+			method: http.MethodGet,
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				refreshToken, _, err := tokenMaker.CreateToken("1234", -time.Minute)
+				require.NoError(t, err)
+				r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: refreshToken})
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+
+			tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+			authMiddleware := NewAuthMiddleware(tokenMaker)
+			router.Handle(tc.method, "/auth", authMiddleware, func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{})
+			})
+
+			req, err := http.NewRequest(tc.method, "/auth", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, req, tokenMaker)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestStreamAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	guildDiscordID := utils.RandomSnowflakeID().String()
+	ticket := utils.RandomString(32)
+	streamTicket := memdb.StreamTicket{
+		UserDiscordID:  "1234",
+		GuildDiscordID: guildDiscordID,
+		Capabilities:   []string{token.CapabilityGuildConfigRead},
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, r *http.Request, tokenMaker token.Maker)
+		buildStubs    func(memStore *mockmemdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/Ticket",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				r.URL.RawQuery = fmt.Sprintf("%s=%s", StreamTicketQueryKey, ticket)
+			},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					ConsumeStreamTicket(gomock.Any(), gomock.Eq(ticket)).
+					Times(1).
+					Return(streamTicket, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+
+				var payload token.Payload
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &payload))
+				require.Equal(t, streamTicket.UserDiscordID, payload.UserDiscordID)
+				require.True(t, payload.HasCapability(guildDiscordID, token.CapabilityGuildConfigRead))
+			},
+		},
+		{
+			name: "OK/AuthorizationHeader",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				accessToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				r.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeBearer, accessToken))
+			},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().ConsumeStreamTicket(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "Unauthorized/UsedTicket",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				r.URL.RawQuery = fmt.Sprintf("%s=%s", StreamTicketQueryKey, ticket)
+			},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					ConsumeStreamTicket(gomock.Any(), gomock.Eq(ticket)).
+					Times(1).
+					Return(memdb.StreamTicket{}, redis.Nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, w.Code)
+			},
+		},
+		{
+			name: "Forbidden/OtherGuildTicket",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				r.URL.RawQuery = fmt.Sprintf("%s=%s", StreamTicketQueryKey, ticket)
+			},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				otherGuildTicket := streamTicket
+				otherGuildTicket.GuildDiscordID = utils.RandomSnowflakeID().String()
+				memStore.EXPECT().
+					ConsumeStreamTicket(gomock.Any(), gomock.Eq(ticket)).
+					Times(1).
+					Return(otherGuildTicket, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/MemDBConsumeStreamTicket",
+			setupAuth: func(t *testing.T, r *http.Request, tokenMaker token.Maker) {
+				r.URL.RawQuery = fmt.Sprintf("%s=%s", StreamTicketQueryKey, ticket)
+			},
+			buildStubs: func(memStore *mockmemdb.MockStore) {
+				memStore.EXPECT().
+					ConsumeStreamTicket(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(memdb.StreamTicket{}, redis.ErrClosed)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(memStore)
+
+			router := gin.New()
+			tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+			router.GET("/guilds/:discord_id/events", NewStreamAuthMiddleware(memStore, tokenMaker), func(c *gin.Context) {
+				c.JSON(http.StatusOK, c.MustGet(AuthorizationPayloadKey))
+			})
+
+			url := fmt.Sprintf("/guilds/%s/events", guildDiscordID)
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, req, tokenMaker)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestUserOnlyMiddleware(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	testCases := []struct {
+		name          string
+		createToken   func(t *testing.T, tokenMaker token.Maker) string
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			createToken: func(t *testing.T, tokenMaker token.Maker) string {
+				accessToken, _, err := tokenMaker.CreateToken("1234", time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "Forbidden/GuildToken",
+			createToken: func(t *testing.T, tokenMaker token.Maker) string {
+				accessToken, _, err := tokenMaker.CreateGuildToken("1234", token.GuildClaims{
+					DiscordID:    utils.RandomSnowflakeID().String(),
+					Capabilities: []string{token.CapabilityGuildConfigRead},
+				}, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+
+			tokenMaker, _ := token.NewPasetoMaker(utils.RandomString(32))
+			router.DELETE("/users/me", NewAuthMiddleware(tokenMaker), NewUserOnlyMiddleware(), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{})
+			})
+
+			req, err := http.NewRequest(http.MethodDelete, "/users/me", nil)
+			require.NoError(t, err)
+			req.Header.Set(AuthorizationHeaderKey, fmt.Sprintf("%s %s", AuthorizationTypeBearer, tc.createToken(t, tokenMaker)))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			tc.checkResponse(t, w)
+		})
+	}
+}
@@ -7,14 +7,25 @@ import (
 type GuildConfig interface {
 	Overwrite() gin.HandlerFunc
 	Get() gin.HandlerFunc
+	Lockdown() gin.HandlerFunc
+}
+
+type Cases interface {
+	Get() gin.HandlerFunc
+	Edit() gin.HandlerFunc
 }
 
 type Permissions struct {
 	GuildConfig
+	Cases
 }
 
 type Middlewares struct {
 	CORS        gin.HandlerFunc
 	Auth        gin.HandlerFunc
+	StreamAuth  gin.HandlerFunc
+	UserOnly    gin.HandlerFunc
+	APIKey      gin.HandlerFunc
+	Scope       func(scopes ...string) gin.HandlerFunc
 	Permissions Permissions
 }
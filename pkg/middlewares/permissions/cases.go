@@ -0,0 +1,25 @@
+package permissions
+
+import (
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/gin-gonic/gin"
+)
+
+type CasePermissions struct {
+	guildPermissions
+}
+
+func NewCasePermissions(store db.Store) *CasePermissions {
+	return &CasePermissions{
+		guildPermissions: guildPermissions{store: store},
+	}
+}
+
+func (p *CasePermissions) Get() gin.HandlerFunc {
+	return p.require(token.CapabilityCasesRead)
+}
+
+func (p *CasePermissions) Edit() gin.HandlerFunc {
+	return p.require(token.CapabilityCasesEdit)
+}
@@ -0,0 +1,125 @@
+package permissions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/forms"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var (
+	ErrNoGuildRelation        = errors.New("no relations with guild")
+	ErrGuildConfigNotFound    = errors.New("guild config not found")
+	ErrInsufficientPermission = errors.New("insufficient permissions")
+	ErrTokenScopedToGuild     = errors.New("token is scoped to another guild")
+)
+
+// guildPermissions checks capabilities of the user in the guild from the request uri
+type guildPermissions struct {
+	store db.Store
+}
+
+// GuildCapabilities computes capabilities of the user in the guild from permission sections of guild config
+func GuildCapabilities(ctx context.Context, store db.Store, userDiscordID string, guildDiscordID string) ([]string, error) {
+	userGuildRel, err := store.GetUserGuildRel(ctx, db.GetUserGuildRelParams{
+		AccountDiscordID: userDiscordID,
+		GuildDiscordID:   guildDiscordID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoGuildRelation
+		}
+		return nil, err
+	}
+
+	guildConfig, err := store.GetGuildConfig(ctx, guildDiscordID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGuildConfigNotFound
+		}
+		return nil, err
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return nil, err
+	}
+
+	member := discordperm.Permissions(userGuildRel.Permissions)
+	capabilities := make([]string, 0, 5)
+	if guildConfigObj.Permissions.CanRead(member) {
+		capabilities = append(capabilities, token.CapabilityGuildConfigRead)
+	}
+	if guildConfigObj.Permissions.CanEdit(member) {
+		capabilities = append(capabilities, token.CapabilityGuildConfigEdit)
+	}
+	if guildConfigObj.CasePermissions.CanRead(member) {
+		capabilities = append(capabilities, token.CapabilityCasesRead)
+	}
+	if guildConfigObj.CasePermissions.CanEdit(member) {
+		capabilities = append(capabilities, token.CapabilityCasesEdit)
+	}
+	if guildConfigObj.Permissions.CanLockdown(member) {
+		capabilities = append(capabilities, token.CapabilityGuildLockdown)
+	}
+	return capabilities, nil
+}
+
+// require trusts claims of guild-scoped tokens and falls back to the database otherwise
+func (p *guildPermissions) require(capability string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req forms.RequireDiscordIDRequest
+		if err := c.ShouldBindUri(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+
+		authPayload := c.MustGet(middlewares.AuthorizationPayloadKey).(*token.Payload)
+
+		if authPayload.Guild != nil {
+			if authPayload.Guild.DiscordID != req.DiscordID {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": ErrTokenScopedToGuild.Error()})
+				return
+			}
+			if !authPayload.HasCapability(req.DiscordID, capability) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": ErrInsufficientPermission.Error()})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		capabilities, err := GuildCapabilities(c, p.store, authPayload.UserDiscordID, req.DiscordID)
+		if err != nil {
+			if errors.Is(err, ErrNoGuildRelation) || errors.Is(err, ErrGuildConfigNotFound) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+
+		if !contains(capabilities, capability) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": ErrInsufficientPermission.Error()})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
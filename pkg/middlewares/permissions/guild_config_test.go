@@ -0,0 +1,271 @@
+package permissions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGuildConfigPermissions_Overwrite(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	tokenMaker, err := token.NewPasetoMaker(utils.RandomString(32))
+	require.NoError(t, err)
+	userDiscordID := utils.RandomSnowflakeID().String()
+	guildDiscordID := utils.RandomSnowflakeID().String()
+	guildConfigJSON, err := json.Marshal(objects.NewDefaultGuildConfig())
+	require.NoError(t, err)
+
+	newGuildToken := func(t *testing.T, guildDiscordID string, capabilities ...string) string {
+		accessToken, _, err := tokenMaker.CreateGuildToken(userDiscordID, token.GuildClaims{
+			DiscordID:    guildDiscordID,
+			Capabilities: capabilities,
+		}, time.Minute)
+		require.NoError(t, err)
+		return accessToken
+	}
+
+	testCases := []struct {
+		name          string
+		buildToken    func(t *testing.T) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK/GuildClaims",
+			buildToken: func(t *testing.T) string {
+				return newGuildToken(t, guildDiscordID, token.CapabilityGuildConfigEdit)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetGuildConfig(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "Forbidden/GuildClaimsMissingCapability",
+			buildToken: func(t *testing.T) string {
+				return newGuildToken(t, guildDiscordID, token.CapabilityGuildConfigRead)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name: "Forbidden/GuildClaimsOtherGuild",
+			buildToken: func(t *testing.T) string {
+				return newGuildToken(t, utils.RandomSnowflakeID().String(), token.CapabilityGuildConfigEdit)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserGuildRel(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name: "OK/Database",
+			buildToken: func(t *testing.T) string {
+				accessToken, _, err := tokenMaker.CreateToken(userDiscordID, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Eq(db.GetUserGuildRelParams{
+						AccountDiscordID: userDiscordID,
+						GuildDiscordID:   guildDiscordID,
+					})).
+					Times(1).
+					Return(db.UserGuild{Permissions: 8}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guildDiscordID)).
+					Times(1).
+					Return(db.GuildConfig{Json: guildConfigJSON}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			name: "Forbidden/DatabaseInsufficientPermissions",
+			buildToken: func(t *testing.T) string {
+				accessToken, _, err := tokenMaker.CreateToken(userDiscordID, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{Permissions: 1}, nil)
+				store.EXPECT().
+					GetGuildConfig(gomock.Any(), gomock.Eq(guildDiscordID)).
+					Times(1).
+					Return(db.GuildConfig{Json: guildConfigJSON}, nil)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, w.Code)
+			},
+		},
+		{
+			name: "InternalServerError/DBGetUserGuildRel",
+			buildToken: func(t *testing.T) string {
+				accessToken, _, err := tokenMaker.CreateToken(userDiscordID, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserGuildRel(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserGuild{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, w *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, w.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			router := gin.New()
+			router.POST(
+				"/guilds/:discord_id/config",
+				middlewares.NewAuthMiddleware(tokenMaker),
+				NewGuildConfigPermissions(store).Overwrite(),
+				func(c *gin.Context) {
+					c.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			url := fmt.Sprintf("/guilds/%s/config", guildDiscordID)
+			req, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+			authHeader := fmt.Sprintf("%s %s", middlewares.AuthorizationTypeBearer, tc.buildToken(t))
+			req.Header.Set(middlewares.AuthorizationHeaderKey, authHeader)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			tc.checkResponse(t, w)
+		})
+	}
+}
+
+func TestGuildCapabilities(t *testing.T) {
+	userDiscordID := utils.RandomSnowflakeID().String()
+	guildDiscordID := utils.RandomSnowflakeID().String()
+
+	restricted := objects.NewDefaultGuildConfig()
+	restricted.Permissions = objects.GuildConfigPermissions{
+		Read: int64(discordperm.ViewAuditLog),
+		Edit: int64(discordperm.ManageGuild),
+	}
+
+	testCases := []struct {
+		name         string
+		config       objects.GuildConfig
+		permissions  discordperm.Permissions
+		capabilities []string
+	}{
+		{
+			name:         "EveryoneCanRead",
+			config:       objects.NewDefaultGuildConfig(),
+			permissions:  discordperm.ViewChannel,
+			capabilities: []string{token.CapabilityGuildConfigRead},
+		},
+		{
+			name:         "Restricted/NoPermissions",
+			config:       restricted,
+			permissions:  discordperm.ViewChannel,
+			capabilities: []string{},
+		},
+		{
+			name:         "Restricted/Read",
+			config:       restricted,
+			permissions:  discordperm.Of(discordperm.ViewChannel, discordperm.ViewAuditLog),
+			capabilities: []string{token.CapabilityGuildConfigRead},
+		},
+		{
+			name:         "Restricted/EditImpliesRead",
+			config:       restricted,
+			permissions:  discordperm.ManageGuild,
+			capabilities: []string{token.CapabilityGuildConfigRead, token.CapabilityGuildConfigEdit},
+		},
+		{
+			name:         "Restricted/Administrator",
+			config:       restricted,
+			permissions:  discordperm.Administrator,
+			capabilities: []string{token.CapabilityGuildConfigRead, token.CapabilityGuildConfigEdit, token.CapabilityCasesRead, token.CapabilityCasesEdit, token.CapabilityGuildLockdown},
+		},
+		{
+			name:         "Lockdown",
+			config:       objects.NewDefaultGuildConfig(),
+			permissions:  discordperm.Of(discordperm.ViewChannel, discordperm.ManageChannels),
+			capabilities: []string{token.CapabilityGuildConfigRead, token.CapabilityGuildLockdown},
+		},
+		{
+			name:         "Restricted/Moderator",
+			config:       restricted,
+			permissions:  discordperm.Of(discordperm.ViewChannel, discordperm.ModerateMembers),
+			capabilities: []string{token.CapabilityCasesRead, token.CapabilityCasesEdit},
+		},
+		{
+			name:         "Restricted/CaseReader",
+			config:       restricted,
+			permissions:  discordperm.Of(discordperm.ViewChannel, discordperm.KickMembers),
+			capabilities: []string{token.CapabilityCasesRead},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			guildConfigJSON, err := json.Marshal(tc.config)
+			require.NoError(t, err)
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserGuildRel(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(db.UserGuild{Permissions: int64(tc.permissions)}, nil)
+			store.EXPECT().
+				GetGuildConfig(gomock.Any(), gomock.Eq(guildDiscordID)).
+				Times(1).
+				Return(db.GuildConfig{Json: guildConfigJSON}, nil)
+
+			capabilities, err := GuildCapabilities(context.Background(), store, userDiscordID, guildDiscordID)
+			require.NoError(t, err)
+			require.Equal(t, tc.capabilities, capabilities)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	ScopeConfigsRead         = "configs:read"
+	ScopeGuildsPresenceWrite = "guilds:presence:write"
+	ScopeCasesWrite          = "cases:write"
+	ScopeCommandsRead        = "commands:read"
+	ScopeAutomodEvaluate     = "automod:evaluate"
+	ScopeMembersRead         = "members:read"
+	ScopeMembersWrite        = "members:write"
+)
+
+// Scopes lists every scope which can be granted to an API key
+var Scopes = []string{
+	ScopeConfigsRead,
+	ScopeGuildsPresenceWrite,
+	ScopeCasesWrite,
+	ScopeCommandsRead,
+	ScopeAutomodEvaluate,
+	ScopeMembersRead,
+	ScopeMembersWrite,
+}
+
+const (
+	keyTag          = "snt"
+	prefixBytesSize = 4
+	secretBytesSize = 32
+)
+
+var ErrInvalidKey = errors.New("invalid api key format")
+
+// Key is a newly generated API key. Plain value is shown only once, only its hash is stored
+type Key struct {
+	Plain  string
+	Prefix string
+	Hash   []byte
+}
+
+// Generate creates a key of format snt_<prefix>_<secret>, where prefix identifies the key
+func Generate() (Key, error) {
+	prefix, err := randomHex(prefixBytesSize)
+	if err != nil {
+		return Key{}, err
+	}
+	secret, err := randomHex(secretBytesSize)
+	if err != nil {
+		return Key{}, err
+	}
+
+	plain := fmt.Sprintf("%s_%s_%s", keyTag, prefix, secret)
+	return Key{
+		Plain:  plain,
+		Prefix: prefix,
+		Hash:   Hash(plain),
+	}, nil
+}
+
+// Parse returns prefix of the key which is used to look up the key
+func Parse(plain string) (string, error) {
+	parts := strings.Split(plain, "_")
+	if len(parts) != 3 || parts[0] != keyTag ||
+		len(parts[1]) != prefixBytesSize*2 || len(parts[2]) != secretBytesSize*2 {
+		return "", ErrInvalidKey
+	}
+	return parts[1], nil
+}
+
+func Hash(plain string) []byte {
+	hash := sha256.Sum256([]byte(plain))
+	return hash[:]
+}
+
+func Verify(plain string, hash []byte) bool {
+	return subtle.ConstantTimeCompare(Hash(plain), hash) == 1
+}
+
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !contains(Scopes, scope) {
+			return fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+	return nil
+}
+
+func HasScopes(granted []string, required ...string) bool {
+	for _, scope := range required {
+		if !contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasGuild reports whether the key may act in the guild, keys without guilds may act in every guild
+func HasGuild(guildDiscordIDs []string, guildDiscordID string) bool {
+	return len(guildDiscordIDs) == 0 || contains(guildDiscordIDs, guildDiscordID)
+}
+
+func contains(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,87 @@
+package apikey
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := Generate()
+	require.NoError(t, err)
+	require.NotEmpty(t, key.Plain)
+	require.NotEmpty(t, key.Hash)
+
+	prefix, err := Parse(key.Plain)
+	require.NoError(t, err)
+	require.Equal(t, key.Prefix, prefix)
+	require.True(t, Verify(key.Plain, key.Hash))
+
+	other, err := Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, key.Plain, other.Plain)
+	require.False(t, Verify(other.Plain, key.Hash))
+}
+
+func TestParse(t *testing.T) {
+	key, err := Generate()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		plain string
+		check func(t *testing.T, prefix string, err error)
+	}{
+		{
+			name:  "OK",
+			plain: key.Plain,
+			check: func(t *testing.T, prefix string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, key.Prefix, prefix)
+			},
+		},
+		{
+			name:  "WrongTag",
+			plain: "abc" + key.Plain[3:],
+			check: func(t *testing.T, prefix string, err error) {
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		},
+		{
+			name:  "Truncated",
+			plain: key.Plain[:len(key.Plain)-1],
+			check: func(t *testing.T, prefix string, err error) {
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		},
+		{
+			name:  "Empty",
+			plain: "",
+			check: func(t *testing.T, prefix string, err error) {
+				require.ErrorIs(t, err, ErrInvalidKey)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix, err := Parse(tc.plain)
+			tc.check(t, prefix, err)
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	require.NoError(t, ValidateScopes([]string{ScopeConfigsRead}))
+	require.Error(t, ValidateScopes(nil))
+	require.Error(t, ValidateScopes([]string{"unknown:scope"}))
+
+	granted := []string{ScopeConfigsRead, ScopeGuildsPresenceWrite}
+	require.True(t, HasScopes(granted, ScopeConfigsRead))
+	require.True(t, HasScopes(granted))
+	require.False(t, HasScopes([]string{ScopeConfigsRead}, ScopeGuildsPresenceWrite))
+}
+
+func TestHasGuild(t *testing.T) {
+	require.True(t, HasGuild(nil, "1"))
+	require.True(t, HasGuild([]string{"1", "2"}, "2"))
+	require.False(t, HasGuild([]string{"1"}, "2"))
+}
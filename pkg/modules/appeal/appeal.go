@@ -0,0 +1,73 @@
+package appeal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidConfig  = errors.New("invalid appeals config")
+	ErrInvalidAnswers = errors.New("invalid appeal answers")
+)
+
+// defaultMaxAnswerLength applies to questions without max_length
+const defaultMaxAnswerLength = 1000
+
+// ValidateConfig checks the config beyond binding tags: question keys are unique
+func ValidateConfig(config objects.AppealsConfig) error {
+	seen := make(map[string]bool, len(config.Questions))
+	for i, question := range config.Questions {
+		if seen[question.Key] {
+			return fmt.Errorf("%w: question %d: duplicate key %q", ErrInvalidConfig, i, question.Key)
+		}
+		seen[question.Key] = true
+	}
+	if config.Enabled && len(config.Questions) == 0 {
+		return fmt.Errorf("%w: enabled appeals need at least one question", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// NormalizeAnswers checks answers against questions of the config and returns them trimmed.
+// Answers to unknown questions are rejected, empty answers to optional questions are dropped.
+func NormalizeAnswers(config objects.AppealsConfig, answers map[string]string) (map[string]string, error) {
+	questions := make(map[string]objects.AppealQuestion, len(config.Questions))
+	for _, question := range config.Questions {
+		questions[question.Key] = question
+	}
+	for key := range answers {
+		if _, ok := questions[key]; !ok {
+			return nil, fmt.Errorf("%w: unknown question %q", ErrInvalidAnswers, key)
+		}
+	}
+
+	normalized := make(map[string]string, len(answers))
+	for _, question := range config.Questions {
+		answer := strings.TrimSpace(answers[question.Key])
+		if answer == "" {
+			if question.Required {
+				return nil, fmt.Errorf("%w: question %q requires an answer", ErrInvalidAnswers, question.Key)
+			}
+			continue
+		}
+
+		maxLength := question.MaxLength
+		if maxLength == 0 {
+			maxLength = defaultMaxAnswerLength
+		}
+		if utf8.RuneCountInString(answer) > maxLength {
+			return nil, fmt.Errorf("%w: answer to %q is longer than %d characters", ErrInvalidAnswers, question.Key, maxLength)
+		}
+		normalized[question.Key] = answer
+	}
+	return normalized, nil
+}
+
+// CanResubmitAt returns when a denied appeal of the same ban may be submitted again
+func CanResubmitAt(config objects.AppealsConfig, deniedAt time.Time) time.Time {
+	return deniedAt.AddDate(0, 0, config.CooldownDays)
+}
@@ -0,0 +1,86 @@
+package appeal
+
+import (
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+var config = objects.AppealsConfig{
+	Enabled: true,
+	Questions: []objects.AppealQuestion{
+		{Key: "reason", Label: "Why should you be unbanned?", Required: true, MaxLength: 10},
+		{Key: "contact", Label: "How can we reach you?"},
+	},
+	CooldownDays: 7,
+}
+
+func TestValidateConfig(t *testing.T) {
+	require.NoError(t, ValidateConfig(config))
+	require.NoError(t, ValidateConfig(objects.AppealsConfig{}))
+
+	duplicate := config
+	duplicate.Questions = []objects.AppealQuestion{config.Questions[0], config.Questions[0]}
+	require.ErrorIs(t, ValidateConfig(duplicate), ErrInvalidConfig)
+
+	empty := config
+	empty.Questions = nil
+	require.ErrorIs(t, ValidateConfig(empty), ErrInvalidConfig)
+}
+
+func TestNormalizeAnswers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		answers    map[string]string
+		normalized map[string]string
+		ok         bool
+	}{
+		{
+			name:       "OK",
+			answers:    map[string]string{"reason": "  sorry  ", "contact": ""},
+			normalized: map[string]string{"reason": "sorry"},
+			ok:         true,
+		},
+		{
+			name:       "OK/RuneLength",
+			answers:    map[string]string{"reason": "ééééééééé", "contact": strings.Repeat("a", 1000)},
+			normalized: map[string]string{"reason": "ééééééééé", "contact": strings.Repeat("a", 1000)},
+			ok:         true,
+		},
+		{
+			name:    "MissingRequired",
+			answers: map[string]string{"reason": "   ", "contact": "mail"},
+		},
+		{
+			name:    "TooLong",
+			answers: map[string]string{"reason": "sorry, really"},
+		},
+		{
+			name:    "DefaultMaxLength",
+			answers: map[string]string{"reason": "sorry", "contact": strings.Repeat("a", 1001)},
+		},
+		{
+			name:    "UnknownQuestion",
+			answers: map[string]string{"reason": "sorry", "bribe": "100$"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := NormalizeAnswers(config, tc.answers)
+			if !tc.ok {
+				require.ErrorIs(t, err, ErrInvalidAnswers)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.normalized, normalized)
+		})
+	}
+}
+
+func TestCanResubmitAt(t *testing.T) {
+	deniedAt := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2022, 10, 8, 12, 0, 0, 0, time.UTC), CanResubmitAt(config, deniedAt))
+}
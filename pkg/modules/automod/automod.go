@@ -0,0 +1,421 @@
+package automod
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInvalidRule = errors.New("invalid automod rule")
+
+// maxSpans limits amount of spans reported for a single violation
+const maxSpans = 20
+
+var (
+	inviteRegexp  = regexp.MustCompile(`(?i:discord\.gg|discord(?:app)?\.com/invite)/([A-Za-z0-9-]+)`)
+	mentionRegexp = regexp.MustCompile(`<@!?\d+>|<@&\d+>|@everyone|@here`)
+)
+
+// Rule is a normalized enabled rule, it is serialized as part of the ruleset downloaded by the bot
+type Rule struct {
+	Name                string   `json:"name"`
+	Type                string   `json:"type"`
+	Actions             []string `json:"actions"`
+	MuteDurationSeconds int64    `json:"mute_duration_seconds,omitempty"`
+	ExemptRoles         []string `json:"exempt_roles,omitempty"`
+	ExemptChannels      []string `json:"exempt_channels,omitempty"`
+	Words               []string `json:"words,omitempty"`
+	Patterns            []string `json:"patterns,omitempty"`
+	AllowedInvites      []string `json:"allowed_invites,omitempty"`
+	MaxMentions         int      `json:"max_mentions,omitempty"`
+	CapsRatio           float64  `json:"caps_ratio,omitempty"`
+	CapsMinLetters      int      `json:"caps_min_letters,omitempty"`
+	RateCount           int      `json:"rate_count,omitempty"`
+	RateWindowSeconds   int      `json:"rate_window_seconds,omitempty"`
+
+	patterns []*regexp.Regexp
+	// phrases are tokenized words indexed by their first token
+	phrases map[string][][]string
+}
+
+// Ruleset is compiled automod config of a guild, version changes whenever the rules change
+type Ruleset struct {
+	Version string `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+type Message struct {
+	GuildDiscordID   string
+	ChannelDiscordID string
+	AuthorDiscordID  string
+	RoleDiscordIDs   []string
+	Content          string
+	SentAt           time.Time
+}
+
+// Span is a half-open range of message content counted in unicode code points
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type Violation struct {
+	Rule                string   `json:"rule"`
+	Type                string   `json:"type"`
+	Actions             []string `json:"actions"`
+	MuteDurationSeconds int64    `json:"mute_duration_seconds,omitempty"`
+	// Match is the first offending part of the message, empty for rules which judge the whole message
+	Match string `json:"match,omitempty"`
+	Spans []Span `json:"spans,omitempty"`
+}
+
+// Compile validates the config and prepares enabled rules for evaluation
+func Compile(config objects.AutomodConfig) (*Ruleset, error) {
+	ruleset := &Ruleset{Rules: make([]Rule, 0, len(config.Rules))}
+
+	cost := 0
+	names := make(map[string]bool, len(config.Rules))
+	for _, ruleConfig := range config.Rules {
+		if names[ruleConfig.Name] {
+			return nil, fmt.Errorf("%w %q: duplicate name", ErrInvalidRule, ruleConfig.Name)
+		}
+		names[ruleConfig.Name] = true
+
+		rule, ruleCost, err := compileRule(ruleConfig)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidRule, ruleConfig.Name, err)
+		}
+		if cost += ruleCost; cost > maxRulesetCost {
+			return nil, fmt.Errorf("%w %q: %v", ErrInvalidRule, ruleConfig.Name, ErrTooComplex)
+		}
+		if config.Enabled && ruleConfig.Enabled {
+			ruleset.Rules = append(ruleset.Rules, rule)
+		}
+	}
+
+	data, err := json.Marshal(ruleset.Rules)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	ruleset.Version = hex.EncodeToString(sum[:8])
+	return ruleset, nil
+}
+
+// compileRule returns the rule with complexity cost of its patterns
+func compileRule(config objects.AutomodRule) (Rule, int, error) {
+	cost := 0
+	rule := Rule{
+		Name:           config.Name,
+		Type:           config.Type,
+		Actions:        unique(config.Actions),
+		ExemptRoles:    unique(config.ExemptRoles),
+		ExemptChannels: unique(config.ExemptChannels),
+	}
+	if len(rule.Actions) == 0 {
+		return Rule{}, 0, errors.New("at least one action is required")
+	}
+	if contains(rule.Actions, objects.AutomodActionMute) {
+		if config.MuteDurationSeconds <= 0 {
+			return Rule{}, 0, errors.New("mute action requires mute_duration_seconds")
+		}
+		rule.MuteDurationSeconds = config.MuteDurationSeconds
+	}
+
+	switch config.Type {
+	case objects.AutomodRuleWords:
+		words := make([]string, 0, len(config.Words))
+		for _, word := range config.Words {
+			if normalized := strings.Join(tokenize(word), " "); normalized != "" {
+				words = append(words, normalized)
+			}
+		}
+		rule.Words = unique(words)
+		if len(rule.Words) == 0 {
+			return Rule{}, 0, errors.New("words are required")
+		}
+		rule.phrases = make(map[string][][]string, len(rule.Words))
+		for _, word := range rule.Words {
+			phrase := strings.Split(word, " ")
+			rule.phrases[phrase[0]] = append(rule.phrases[phrase[0]], phrase)
+		}
+	case objects.AutomodRuleRegex:
+		rule.Patterns = unique(config.Patterns)
+		if len(rule.Patterns) == 0 {
+			return Rule{}, 0, errors.New("patterns are required")
+		}
+		for _, pattern := range rule.Patterns {
+			patternCost, err := PatternCost(pattern)
+			if err != nil {
+				return Rule{}, 0, err
+			}
+			cost += patternCost
+
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return Rule{}, 0, err
+			}
+			rule.patterns = append(rule.patterns, re)
+		}
+	case objects.AutomodRuleInvites:
+		rule.AllowedInvites = unique(config.AllowedInvites)
+	case objects.AutomodRuleMentions:
+		if config.MaxMentions < 1 {
+			return Rule{}, 0, errors.New("max_mentions must be at least 1")
+		}
+		rule.MaxMentions = config.MaxMentions
+	case objects.AutomodRuleCaps:
+		if config.CapsRatio <= 0 || config.CapsRatio > 1 {
+			return Rule{}, 0, errors.New("caps_ratio must be within (0, 1]")
+		}
+		rule.CapsRatio = config.CapsRatio
+		rule.CapsMinLetters = config.CapsMinLetters
+	case objects.AutomodRuleRate:
+		if config.RateCount < 1 || config.RateWindowSeconds < 1 {
+			return Rule{}, 0, errors.New("rate_count and rate_window_seconds must be at least 1")
+		}
+		rule.RateCount = config.RateCount
+		rule.RateWindowSeconds = config.RateWindowSeconds
+	default:
+		return Rule{}, 0, fmt.Errorf("unknown type %q", config.Type)
+	}
+	return rule, cost, nil
+}
+
+// Evaluate returns violations of every matching rule in the order of rules.
+// Rate rules count the message even if it violates other rules.
+func (r *Ruleset) Evaluate(ctx context.Context, counter RateCounter, msg Message) ([]Violation, error) {
+	violations := make([]Violation, 0)
+
+	var tokens []token
+	for _, rule := range r.Rules {
+		if rule.exempts(msg) {
+			continue
+		}
+
+		var matched bool
+		var match string
+		var spans []Span
+		switch rule.Type {
+		case objects.AutomodRuleWords:
+			if tokens == nil {
+				tokens = tokenizeSpans(msg.Content)
+			}
+			match, spans = rule.matchWords(tokens)
+			matched = len(spans) > 0
+		case objects.AutomodRuleRegex:
+			match, spans = rule.matchPatterns(msg.Content)
+			matched = len(spans) > 0
+		case objects.AutomodRuleInvites:
+			match, spans = rule.matchInvites(msg.Content)
+			matched = len(spans) > 0
+		case objects.AutomodRuleMentions:
+			locs := mentionRegexp.FindAllStringIndex(msg.Content, -1)
+			if matched = len(locs) > rule.MaxMentions; matched {
+				if len(locs) > maxSpans {
+					locs = locs[:maxSpans]
+				}
+				spans = runeSpans(msg.Content, locs)
+			}
+		case objects.AutomodRuleCaps:
+			matched = rule.matchCaps(msg.Content)
+		case objects.AutomodRuleRate:
+			key := strings.Join([]string{msg.GuildDiscordID, rule.Name, msg.AuthorDiscordID}, ":")
+			window := time.Duration(rule.RateWindowSeconds) * time.Second
+			hits, err := counter.Hit(ctx, key, msg.SentAt, window)
+			if err != nil {
+				return nil, err
+			}
+			matched = hits > rule.RateCount
+		}
+
+		if matched {
+			violations = append(violations, Violation{
+				Rule:                rule.Name,
+				Type:                rule.Type,
+				Actions:             rule.Actions,
+				MuteDurationSeconds: rule.MuteDurationSeconds,
+				Match:               match,
+				Spans:               spans,
+			})
+		}
+	}
+	return violations, nil
+}
+
+func (r *Rule) exempts(msg Message) bool {
+	if contains(r.ExemptChannels, msg.ChannelDiscordID) {
+		return true
+	}
+	for _, role := range msg.RoleDiscordIDs {
+		if contains(r.ExemptRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchWords(tokens []token) (string, []Span) {
+	var match string
+	var spans []Span
+	for i, tok := range tokens {
+		for _, phrase := range r.phrases[tok.text] {
+			if !hasPhrase(tokens[i:], phrase) {
+				continue
+			}
+			if match == "" {
+				match = strings.Join(phrase, " ")
+			}
+			spans = append(spans, Span{Start: tok.start, End: tokens[i+len(phrase)-1].end})
+			if len(spans) == maxSpans {
+				return match, spans
+			}
+		}
+	}
+	return match, spans
+}
+
+func hasPhrase(tokens []token, phrase []string) bool {
+	if len(tokens) < len(phrase) {
+		return false
+	}
+	for i, word := range phrase {
+		if tokens[i].text != word {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Rule) matchPatterns(content string) (string, []Span) {
+	for _, re := range r.patterns {
+		if locs := re.FindAllStringIndex(content, maxSpans); locs != nil {
+			return content[locs[0][0]:locs[0][1]], runeSpans(content, locs)
+		}
+	}
+	return "", nil
+}
+
+func (r *Rule) matchInvites(content string) (string, []Span) {
+	var match string
+	var locs [][]int
+	for _, loc := range inviteRegexp.FindAllStringSubmatchIndex(content, -1) {
+		if contains(r.AllowedInvites, content[loc[2]:loc[3]]) {
+			continue
+		}
+		if match == "" {
+			match = content[loc[0]:loc[1]]
+		}
+		if locs = append(locs, loc[:2]); len(locs) == maxSpans {
+			break
+		}
+	}
+	return match, runeSpans(content, locs)
+}
+
+func (r *Rule) matchCaps(content string) bool {
+	letters, upper := 0, 0
+	for _, c := range content {
+		if unicode.IsLetter(c) {
+			letters++
+			if unicode.IsUpper(c) {
+				upper++
+			}
+		}
+	}
+	if letters == 0 || letters < r.CapsMinLetters {
+		return false
+	}
+	return float64(upper)/float64(letters) >= r.CapsRatio
+}
+
+// runeSpans converts byte offsets of regexp matches to spans
+func runeSpans(content string, locs [][]int) []Span {
+	if len(locs) == 0 {
+		return nil
+	}
+	spans := make([]Span, 0, len(locs))
+	for _, loc := range locs {
+		start := utf8.RuneCountInString(content[:loc[0]])
+		spans = append(spans, Span{
+			Start: start,
+			End:   start + utf8.RuneCountInString(content[loc[0]:loc[1]]),
+		})
+	}
+	return spans
+}
+
+type token struct {
+	text       string
+	start, end int
+}
+
+// tokenizeSpans splits text into lowercase words with their spans, punctuation and spacing are dropped
+func tokenizeSpans(text string) []token {
+	tokens := make([]token, 0)
+	var word strings.Builder
+	start, i := -1, 0
+	for _, c := range text {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			if start < 0 {
+				start = i
+			}
+			word.WriteRune(unicode.ToLower(c))
+		} else if start >= 0 {
+			tokens = append(tokens, token{text: word.String(), start: start, end: i})
+			word.Reset()
+			start = -1
+		}
+		i++
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{text: word.String(), start: start, end: i})
+	}
+	return tokens
+}
+
+// tokenize splits text into lowercase words
+func tokenize(text string) []string {
+	tokens := tokenizeSpans(text)
+	words := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		words = append(words, tok.text)
+	}
+	return words
+}
+
+// unique returns sorted values without duplicates, so equal configs compile to equal rulesets
+func unique(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	result := append([]string(nil), values...)
+	sort.Strings(result)
+	n := 1
+	for i := 1; i < len(result); i++ {
+		if result[i] != result[n-1] {
+			result[n] = result[i]
+			n++
+		}
+	}
+	return result[:n]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,292 @@
+package automod
+
+import (
+	"context"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func compileRules(t *testing.T, rules ...objects.AutomodRule) *Ruleset {
+	for i := range rules {
+		rules[i].Enabled = true
+		if rules[i].Actions == nil {
+			rules[i].Actions = []string{objects.AutomodActionDelete}
+		}
+	}
+	ruleset, err := Compile(objects.AutomodConfig{Enabled: true, Rules: rules})
+	require.NoError(t, err)
+	return ruleset
+}
+
+func evaluate(t *testing.T, ruleset *Ruleset, content string) []Violation {
+	violations, err := ruleset.Evaluate(context.Background(), NewMemoryRateCounter(), Message{
+		GuildDiscordID:   "1",
+		ChannelDiscordID: "2",
+		AuthorDiscordID:  "3",
+		Content:          content,
+		SentAt:           time.Now(),
+	})
+	require.NoError(t, err)
+	return violations
+}
+
+func TestCompile(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule objects.AutomodRule
+		ok   bool
+	}{
+		{
+			name: "Words",
+			rule: objects.AutomodRule{Name: "words", Type: objects.AutomodRuleWords, Words: []string{"Bad"}},
+			ok:   true,
+		},
+		{
+			name: "Words/Empty",
+			rule: objects.AutomodRule{Name: "words", Type: objects.AutomodRuleWords, Words: []string{" !? "}},
+		},
+		{
+			name: "Regex/Invalid",
+			rule: objects.AutomodRule{Name: "regex", Type: objects.AutomodRuleRegex, Patterns: []string{"("}},
+		},
+		{
+			name: "Mentions/NoLimit",
+			rule: objects.AutomodRule{Name: "mentions", Type: objects.AutomodRuleMentions},
+		},
+		{
+			name: "Caps/NoRatio",
+			rule: objects.AutomodRule{Name: "caps", Type: objects.AutomodRuleCaps},
+		},
+		{
+			name: "Rate/NoWindow",
+			rule: objects.AutomodRule{Name: "rate", Type: objects.AutomodRuleRate, RateCount: 5},
+		},
+		{
+			name: "Mute/NoDuration",
+			rule: objects.AutomodRule{
+				Name:    "invites",
+				Type:    objects.AutomodRuleInvites,
+				Actions: []string{objects.AutomodActionDelete, objects.AutomodActionMute},
+			},
+		},
+		{
+			name: "UnknownType",
+			rule: objects.AutomodRule{Name: "unknown", Type: "unknown"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.rule.Actions == nil {
+				tc.rule.Actions = []string{objects.AutomodActionDelete}
+			}
+			_, err := Compile(objects.AutomodConfig{Enabled: true, Rules: []objects.AutomodRule{tc.rule}})
+			if tc.ok {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidRule)
+			}
+		})
+	}
+}
+
+func TestCompileNormalizesRuleset(t *testing.T) {
+	rule := objects.AutomodRule{
+		Name:    "words",
+		Type:    objects.AutomodRuleWords,
+		Enabled: true,
+		Actions: []string{objects.AutomodActionWarn, objects.AutomodActionDelete, objects.AutomodActionWarn},
+		Words:   []string{"Bad  Phrase", "worse", "bad phrase!"},
+	}
+	disabled := objects.AutomodRule{
+		Name:      "caps",
+		Type:      objects.AutomodRuleCaps,
+		Actions:   []string{objects.AutomodActionDelete},
+		CapsRatio: 0.7,
+	}
+
+	ruleset, err := Compile(objects.AutomodConfig{Enabled: true, Rules: []objects.AutomodRule{rule, disabled}})
+	require.NoError(t, err)
+	require.Len(t, ruleset.Rules, 1)
+	require.Equal(t, []string{"bad phrase", "worse"}, ruleset.Rules[0].Words)
+	require.Equal(t, []string{objects.AutomodActionDelete, objects.AutomodActionWarn}, ruleset.Rules[0].Actions)
+
+	same, err := Compile(objects.AutomodConfig{Enabled: true, Rules: []objects.AutomodRule{rule, disabled}})
+	require.NoError(t, err)
+	require.Equal(t, ruleset.Version, same.Version)
+
+	off, err := Compile(objects.AutomodConfig{Enabled: false, Rules: []objects.AutomodRule{rule}})
+	require.NoError(t, err)
+	require.Empty(t, off.Rules)
+	require.NotEqual(t, ruleset.Version, off.Version)
+
+	_, err = Compile(objects.AutomodConfig{Enabled: true, Rules: []objects.AutomodRule{rule, rule}})
+	require.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rule    objects.AutomodRule
+		content string
+		match   string
+		spans   []Span
+		matched bool
+	}{
+		{
+			name:    "Words/Match",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleWords, Words: []string{"bad phrase"}},
+			content: "what a BAD, phrase! bad phrase",
+			match:   "bad phrase",
+			spans:   []Span{{Start: 7, End: 18}, {Start: 20, End: 30}},
+			matched: true,
+		},
+		{
+			name:    "Words/PartOfWord",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleWords, Words: []string{"ass"}},
+			content: "classic assignment",
+		},
+		{
+			name:    "Regex/Match",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleRegex, Patterns: []string{`(?i)free\s+nitro`}},
+			content: "héllo get FREE  nitro here",
+			match:   "FREE  nitro",
+			spans:   []Span{{Start: 10, End: 21}},
+			matched: true,
+		},
+		{
+			name:    "Invites/Match",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleInvites, AllowedInvites: []string{"sentinel"}},
+			content: "join discord.gg/sentinel and https://discord.com/invite/Raid42",
+			match:   "discord.com/invite/Raid42",
+			spans:   []Span{{Start: 37, End: 62}},
+			matched: true,
+		},
+		{
+			name:    "Invites/Allowed",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleInvites, AllowedInvites: []string{"sentinel"}},
+			content: "join DISCORD.GG/sentinel",
+		},
+		{
+			name:    "Mentions/Match",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleMentions, MaxMentions: 2},
+			content: "<@1> <@!2> <@&3>",
+			spans:   []Span{{Start: 0, End: 4}, {Start: 5, End: 10}, {Start: 11, End: 16}},
+			matched: true,
+		},
+		{
+			name:    "Mentions/WithinLimit",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleMentions, MaxMentions: 2},
+			content: "<@1> @everyone",
+		},
+		{
+			name:    "Caps/Match",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleCaps, CapsRatio: 0.7, CapsMinLetters: 10},
+			content: "WHY IS NOBODY ANSWERING me",
+			matched: true,
+		},
+		{
+			name:    "Caps/TooShort",
+			rule:    objects.AutomodRule{Type: objects.AutomodRuleCaps, CapsRatio: 0.7, CapsMinLetters: 10},
+			content: "OK FINE",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.rule.Name = tc.name
+			violations := evaluate(t, compileRules(t, tc.rule), tc.content)
+			if !tc.matched {
+				require.Empty(t, violations)
+				return
+			}
+			require.Len(t, violations, 1)
+			require.Equal(t, tc.name, violations[0].Rule)
+			require.Equal(t, tc.match, violations[0].Match)
+			require.Equal(t, tc.spans, violations[0].Spans)
+		})
+	}
+}
+
+func TestEvaluateExemptions(t *testing.T) {
+	ruleset := compileRules(t, objects.AutomodRule{
+		Name:                "words",
+		Type:                objects.AutomodRuleWords,
+		Actions:             []string{objects.AutomodActionMute},
+		MuteDurationSeconds: 60,
+		ExemptRoles:         []string{"10"},
+		ExemptChannels:      []string{"20"},
+		Words:               []string{"bad"},
+	})
+	counter := NewMemoryRateCounter()
+	msg := Message{ChannelDiscordID: "2", AuthorDiscordID: "3", Content: "bad", SentAt: time.Now()}
+
+	violations, err := ruleset.Evaluate(context.Background(), counter, msg)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	require.Equal(t, int64(60), violations[0].MuteDurationSeconds)
+
+	exemptRole := msg
+	exemptRole.RoleDiscordIDs = []string{"9", "10"}
+	violations, err = ruleset.Evaluate(context.Background(), counter, exemptRole)
+	require.NoError(t, err)
+	require.Empty(t, violations)
+
+	exemptChannel := msg
+	exemptChannel.ChannelDiscordID = "20"
+	violations, err = ruleset.Evaluate(context.Background(), counter, exemptChannel)
+	require.NoError(t, err)
+	require.Empty(t, violations)
+}
+
+func TestEvaluateRate(t *testing.T) {
+	ruleset := compileRules(t, objects.AutomodRule{
+		Name:              "rate",
+		Type:              objects.AutomodRuleRate,
+		RateCount:         3,
+		RateWindowSeconds: 5,
+	})
+	counter := NewMemoryRateCounter()
+	start := time.Now()
+
+	send := func(authorDiscordID string, at time.Duration) []Violation {
+		violations, err := ruleset.Evaluate(context.Background(), counter, Message{
+			GuildDiscordID:   "1",
+			ChannelDiscordID: "2",
+			AuthorDiscordID:  authorDiscordID,
+			Content:          "hi",
+			SentAt:           start.Add(at),
+		})
+		require.NoError(t, err)
+		return violations
+	}
+
+	require.Empty(t, send("3", 0))
+	require.Empty(t, send("3", time.Second))
+	require.Empty(t, send("3", 2*time.Second))
+	require.Len(t, send("3", 3*time.Second), 1)
+	require.Empty(t, send("4", 3*time.Second))
+
+	// hits at 0s and 1s leave the window
+	require.Empty(t, send("3", 6*time.Second+time.Millisecond))
+}
+
+func TestResolve(t *testing.T) {
+	violations := []Violation{
+		{Actions: []string{objects.AutomodActionWarn}},
+		{Actions: []string{objects.AutomodActionDelete, objects.AutomodActionMute}, MuteDurationSeconds: 600},
+		{Actions: []string{objects.AutomodActionMute}, MuteDurationSeconds: 60},
+	}
+	require.Equal(t, Outcome{}, Resolve(nil))
+	require.Equal(t, Outcome{Punishment: objects.AutomodActionWarn}, Resolve(violations[:1]))
+	require.Equal(t, Outcome{
+		Delete:              true,
+		Punishment:          objects.AutomodActionMute,
+		MuteDurationSeconds: 600,
+	}, Resolve(violations))
+
+	violations = append(violations, Violation{Actions: []string{objects.AutomodActionBan}})
+	require.Equal(t, Outcome{Delete: true, Punishment: objects.AutomodActionBan}, Resolve(violations))
+}
@@ -0,0 +1,60 @@
+package automod
+
+import (
+	"errors"
+	"regexp/syntax"
+)
+
+const (
+	// maxPatternCost limits compiled program size of a single pattern
+	maxPatternCost = 2000
+	// maxRulesetCost limits compiled program size of all patterns of a guild
+	maxRulesetCost = 20000
+)
+
+var (
+	ErrTooComplex       = errors.New("pattern exceeds complexity budget")
+	ErrNestedQuantifier = errors.New("pattern repeats a repetition, which backtracks catastrophically in most regex engines")
+)
+
+// PatternCost parses the pattern and returns size of its compiled program.
+// The backend matches in linear time, but the bot may evaluate downloaded rulesets with a backtracking
+// engine, so nested unbounded repetitions like (a+)+ are rejected regardless of the budget.
+func PatternCost(pattern string) (int, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return 0, err
+	}
+	if starHeight(re) > 1 {
+		return 0, ErrNestedQuantifier
+	}
+
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return 0, err
+	}
+	if len(prog.Inst) > maxPatternCost {
+		return 0, ErrTooComplex
+	}
+	return len(prog.Inst), nil
+}
+
+// starHeight returns the deepest nesting of repetitions matching variable amount of times
+func starHeight(re *syntax.Regexp) int {
+	height := 0
+	for _, sub := range re.Sub {
+		if h := starHeight(sub); h > height {
+			height = h
+		}
+	}
+
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return height + 1
+	case syntax.OpRepeat:
+		if re.Max == -1 || re.Max > re.Min {
+			return height + 1
+		}
+	}
+	return height
+}
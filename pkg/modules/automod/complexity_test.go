@@ -0,0 +1,58 @@
+package automod
+
+import (
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestPatternCost(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+		err     error
+	}{
+		{name: "Literal", pattern: `free nitro`},
+		{name: "Alternation", pattern: `(?i)(free|cheap)\s+nitro`},
+		{name: "BoundedRepetition", pattern: `(ab{2}){3}`},
+		{name: "NestedPlus", pattern: `(a+)+b`, err: ErrNestedQuantifier},
+		{name: "NestedStar", pattern: `(\w*\s?)*$`, err: ErrNestedQuantifier},
+		{name: "NestedRange", pattern: `(x{1,5}y)*`, err: ErrNestedQuantifier},
+		{name: "TooExpensive", pattern: `(abc|def|ghi){300}`, err: ErrTooComplex},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cost, err := PatternCost(tc.pattern)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Positive(t, cost)
+		})
+	}
+
+	_, err := PatternCost(`(`)
+	require.Error(t, err)
+}
+
+func TestCompileRulesetCost(t *testing.T) {
+	// every pattern fits the pattern budget, together they exceed the ruleset budget
+	pattern := `(abc|def){150}`
+	rules := make([]objects.AutomodRule, 0, 20)
+	for i := 0; i < cap(rules); i++ {
+		rules = append(rules, objects.AutomodRule{
+			Name:     strings.Repeat("r", i+1),
+			Type:     objects.AutomodRuleRegex,
+			Actions:  []string{objects.AutomodActionDelete},
+			Patterns: []string{pattern},
+		})
+	}
+
+	_, err := PatternCost(pattern)
+	require.NoError(t, err)
+	_, err = Compile(objects.AutomodConfig{Enabled: true, Rules: rules})
+	require.ErrorIs(t, err, ErrInvalidRule)
+}
@@ -0,0 +1,48 @@
+package automod
+
+import "github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+
+// punishments ordered by severity
+var punishments = []string{
+	objects.AutomodActionWarn,
+	objects.AutomodActionMute,
+	objects.AutomodActionKick,
+	objects.AutomodActionBan,
+}
+
+// Outcome is what the bot does with the message, only the most severe punishment is applied
+type Outcome struct {
+	Delete     bool   `json:"delete"`
+	Punishment string `json:"punishment,omitempty"`
+	// MuteDurationSeconds is the longest duration of violated mute rules
+	MuteDurationSeconds int64 `json:"mute_duration_seconds,omitempty"`
+}
+
+func Resolve(violations []Violation) Outcome {
+	var outcome Outcome
+	severity := -1
+	for _, violation := range violations {
+		for _, action := range violation.Actions {
+			if action == objects.AutomodActionDelete {
+				outcome.Delete = true
+				continue
+			}
+			for i, punishment := range punishments {
+				if action == punishment && i > severity {
+					severity = i
+				}
+			}
+			if action == objects.AutomodActionMute && violation.MuteDurationSeconds > outcome.MuteDurationSeconds {
+				outcome.MuteDurationSeconds = violation.MuteDurationSeconds
+			}
+		}
+	}
+
+	if severity >= 0 {
+		outcome.Punishment = punishments[severity]
+	}
+	if outcome.Punishment != objects.AutomodActionMute {
+		outcome.MuteDurationSeconds = 0
+	}
+	return outcome
+}
@@ -0,0 +1,61 @@
+package automod
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateCounter records a hit of the key and returns amount of its hits within the window ending at the hit
+type RateCounter interface {
+	Hit(ctx context.Context, key string, at time.Time, window time.Duration) (int, error)
+}
+
+// memorySweepThreshold is amount of keys after which expired keys are swept
+const memorySweepThreshold = 10000
+
+// MemoryRateCounter keeps hits in process memory, it suits a single bot process and tests
+type MemoryRateCounter struct {
+	mu      sync.Mutex
+	hits    map[string][]time.Time
+	windows map[string]time.Duration
+}
+
+func NewMemoryRateCounter() *MemoryRateCounter {
+	return &MemoryRateCounter{
+		hits:    make(map[string][]time.Time),
+		windows: make(map[string]time.Duration),
+	}
+}
+
+func (c *MemoryRateCounter) Hit(_ context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.hits) >= memorySweepThreshold {
+		c.sweep(at)
+	}
+
+	hits := append(expire(c.hits[key], at.Add(-window)), at)
+	c.hits[key] = hits
+	c.windows[key] = window
+	return len(hits), nil
+}
+
+func (c *MemoryRateCounter) sweep(now time.Time) {
+	for key, hits := range c.hits {
+		if len(expire(hits, now.Add(-c.windows[key]))) == 0 {
+			delete(c.hits, key)
+			delete(c.windows, key)
+		}
+	}
+}
+
+// expire drops hits at or before the given time, hits are ordered by time
+func expire(hits []time.Time, before time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(before) {
+		i++
+	}
+	return hits[i:]
+}
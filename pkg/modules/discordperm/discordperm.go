@@ -0,0 +1,101 @@
+// Package discordperm models Discord permission flags,
+// see https://discord.com/developers/docs/topics/permissions
+package discordperm
+
+// Permissions is a set of Discord permission flags
+type Permissions int64
+
+const (
+	CreateInstantInvite     Permissions = 1 << 0
+	KickMembers             Permissions = 1 << 1
+	BanMembers              Permissions = 1 << 2
+	Administrator           Permissions = 1 << 3
+	ManageChannels          Permissions = 1 << 4
+	ManageGuild             Permissions = 1 << 5
+	AddReactions            Permissions = 1 << 6
+	ViewAuditLog            Permissions = 1 << 7
+	PrioritySpeaker         Permissions = 1 << 8
+	Stream                  Permissions = 1 << 9
+	ViewChannel             Permissions = 1 << 10
+	SendMessages            Permissions = 1 << 11
+	SendTTSMessages         Permissions = 1 << 12
+	ManageMessages          Permissions = 1 << 13
+	EmbedLinks              Permissions = 1 << 14
+	AttachFiles             Permissions = 1 << 15
+	ReadMessageHistory      Permissions = 1 << 16
+	MentionEveryone         Permissions = 1 << 17
+	UseExternalEmojis       Permissions = 1 << 18
+	ViewGuildInsights       Permissions = 1 << 19
+	Connect                 Permissions = 1 << 20
+	Speak                   Permissions = 1 << 21
+	MuteMembers             Permissions = 1 << 22
+	DeafenMembers           Permissions = 1 << 23
+	MoveMembers             Permissions = 1 << 24
+	UseVAD                  Permissions = 1 << 25
+	ChangeNickname          Permissions = 1 << 26
+	ManageNicknames         Permissions = 1 << 27
+	ManageRoles             Permissions = 1 << 28
+	ManageWebhooks          Permissions = 1 << 29
+	ManageEmojisAndStickers Permissions = 1 << 30
+	UseApplicationCommands  Permissions = 1 << 31
+	RequestToSpeak          Permissions = 1 << 32
+	ManageEvents            Permissions = 1 << 33
+	ManageThreads           Permissions = 1 << 34
+	CreatePublicThreads     Permissions = 1 << 35
+	CreatePrivateThreads    Permissions = 1 << 36
+	UseExternalStickers     Permissions = 1 << 37
+	SendMessagesInThreads   Permissions = 1 << 38
+	UseEmbeddedActivities   Permissions = 1 << 39
+	ModerateMembers         Permissions = 1 << 40
+
+	// all covers every flag defined above
+	all = ModerateMembers<<1 - 1
+)
+
+// All returns set of every known permission
+func All() Permissions {
+	return all
+}
+
+// Of combines flags into a set
+func Of(flags ...Permissions) Permissions {
+	var p Permissions
+	for _, flag := range flags {
+		p |= flag
+	}
+	return p
+}
+
+// Effective resolves permissions of a guild member: the owner and administrators have every permission
+func Effective(p Permissions, isOwner bool) Permissions {
+	if isOwner || p&Administrator != 0 {
+		return All()
+	}
+	return p
+}
+
+// Has reports whether the set contains every one of the flags
+func (p Permissions) Has(flags Permissions) bool {
+	return p&flags == flags
+}
+
+// HasAny reports whether the set contains at least one of the flags
+func (p Permissions) HasAny(flags Permissions) bool {
+	return p&flags != 0
+}
+
+func (p Permissions) IsAdministrator() bool {
+	return p.Has(Administrator)
+}
+
+func (p Permissions) Add(flags Permissions) Permissions {
+	return p | flags
+}
+
+func (p Permissions) Remove(flags Permissions) Permissions {
+	return p &^ flags
+}
+
+func (p Permissions) Intersect(flags Permissions) Permissions {
+	return p & flags
+}
@@ -0,0 +1,35 @@
+package discordperm
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	require.Equal(t, Permissions(0x1ffffffffff), All())
+	require.True(t, All().Has(Of(Administrator, ModerateMembers, CreateInstantInvite)))
+}
+
+func TestEffective(t *testing.T) {
+	member := Of(ViewChannel, SendMessages)
+
+	require.Equal(t, member, Effective(member, false))
+	require.Equal(t, All(), Effective(member, true))
+	require.Equal(t, All(), Effective(member.Add(Administrator), false))
+}
+
+func TestPermissionsSetOperations(t *testing.T) {
+	p := Of(ViewChannel, SendMessages)
+
+	require.True(t, p.Has(ViewChannel))
+	require.True(t, p.Has(Of(ViewChannel, SendMessages)))
+	require.False(t, p.Has(Of(ViewChannel, ManageGuild)))
+	require.True(t, p.HasAny(Of(ViewChannel, ManageGuild)))
+	require.False(t, p.HasAny(Of(ManageGuild, Administrator)))
+	require.False(t, p.HasAny(0))
+	require.False(t, p.IsAdministrator())
+
+	p = p.Add(ManageGuild).Remove(SendMessages)
+	require.Equal(t, Of(ViewChannel, ManageGuild), p)
+	require.Equal(t, ManageGuild, p.Intersect(Of(ManageGuild, Administrator)))
+}
@@ -0,0 +1,129 @@
+package escalation
+
+import (
+	"errors"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"time"
+)
+
+var ErrInvalidPolicy = errors.New("invalid escalation policy")
+
+// severity orders infraction actions, actions missing here (e.g. unban) never escalate
+var severity = map[string]int{
+	objects.AutomodActionWarn: 1,
+	objects.AutomodActionMute: 2,
+	objects.AutomodActionKick: 3,
+	objects.AutomodActionBan:  4,
+}
+
+// Infraction is a past case of the target
+type Infraction struct {
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Decision is the action the bot should take for an infraction
+type Decision struct {
+	Action string `json:"action"`
+	// DurationSeconds of the mute or ban, 0 means permanent ban
+	DurationSeconds int64 `json:"duration_seconds"`
+	Escalated       bool  `json:"escalated"`
+	// Step is the step of the policy which escalated the infraction
+	Step *objects.EscalationStep `json:"step"`
+}
+
+// Validate checks the policy beyond binding tags: thresholds are unique and mutes are temporary
+func Validate(config objects.EscalationConfig) error {
+	seen := make(map[int]bool, len(config.Steps))
+	for i, step := range config.Steps {
+		if seen[step.Points] {
+			return fmt.Errorf("%w: step %d: duplicate threshold of %d points", ErrInvalidPolicy, i, step.Points)
+		}
+		seen[step.Points] = true
+
+		if step.Action == objects.AutomodActionMute && step.DurationSeconds <= 0 {
+			return fmt.Errorf("%w: step %d: mute requires duration_seconds", ErrInvalidPolicy, i)
+		}
+		if step.Action == objects.AutomodActionKick && step.DurationSeconds != 0 {
+			return fmt.Errorf("%w: step %d: kick takes no duration_seconds", ErrInvalidPolicy, i)
+		}
+	}
+	return nil
+}
+
+// Points returns points the policy assigns to the case action
+func Points(config objects.EscalationConfig, action string) int {
+	switch action {
+	case objects.AutomodActionWarn:
+		return config.Points.Warn
+	case objects.AutomodActionMute:
+		return config.Points.Mute
+	case objects.AutomodActionKick:
+		return config.Points.Kick
+	case objects.AutomodActionBan:
+		return config.Points.Ban
+	}
+	return 0
+}
+
+// Horizon returns time at or before which cases no longer count towards standing, zero time if points never decay
+func Horizon(config objects.EscalationConfig, now time.Time) time.Time {
+	if config.DecayDays == 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -config.DecayDays)
+}
+
+// Standing sums points of the infractions which have not decayed at the given time
+func Standing(config objects.EscalationConfig, infractions []Infraction, now time.Time) int {
+	horizon := Horizon(config, now)
+	standing := 0
+	for _, infraction := range infractions {
+		if infraction.CreatedAt.After(horizon) {
+			standing += Points(config, infraction.Action)
+		}
+	}
+	return standing
+}
+
+// Escalate picks the action for an infraction given standing of the target including the infraction.
+// The highest step reached replaces the infraction unless the infraction is already as severe.
+func Escalate(config objects.EscalationConfig, standing int, action string, durationSeconds int64) Decision {
+	decision := Decision{Action: action, DurationSeconds: durationSeconds}
+	if !config.Enabled || severity[action] == 0 {
+		return decision
+	}
+
+	var reached *objects.EscalationStep
+	for i := range config.Steps {
+		step := config.Steps[i]
+		if step.Points <= standing && (reached == nil || step.Points > reached.Points) {
+			reached = &step
+		}
+	}
+	if reached == nil || !stronger(reached.Action, reached.DurationSeconds, action, durationSeconds) {
+		return decision
+	}
+
+	return Decision{
+		Action:          reached.Action,
+		DurationSeconds: reached.DurationSeconds,
+		Escalated:       true,
+		Step:            reached,
+	}
+}
+
+// stronger reports whether action a is more severe than action b, longer punishments of the same action are stronger
+func stronger(a string, aDuration int64, b string, bDuration int64) bool {
+	if severity[a] != severity[b] {
+		return severity[a] > severity[b]
+	}
+	switch a {
+	case objects.AutomodActionMute:
+		return aDuration > bDuration
+	case objects.AutomodActionBan:
+		return bDuration != 0 && (aDuration == 0 || aDuration > bDuration)
+	}
+	return false
+}
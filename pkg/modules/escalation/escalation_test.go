@@ -0,0 +1,146 @@
+package escalation
+
+import (
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// policy escalates three warns to a 1h mute and five to a ban
+var policy = objects.EscalationConfig{
+	Enabled:   true,
+	Points:    objects.EscalationPoints{Warn: 1, Mute: 1, Kick: 2, Ban: 3},
+	DecayDays: 7,
+	Steps: []objects.EscalationStep{
+		{Points: 5, Action: objects.AutomodActionBan},
+		{Points: 3, Action: objects.AutomodActionMute, DurationSeconds: 3600},
+	},
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(policy))
+	require.NoError(t, Validate(objects.EscalationConfig{}))
+
+	testCases := []struct {
+		name  string
+		steps []objects.EscalationStep
+	}{
+		{
+			name: "DuplicateThreshold",
+			steps: []objects.EscalationStep{
+				{Points: 3, Action: objects.AutomodActionKick},
+				{Points: 3, Action: objects.AutomodActionBan},
+			},
+		},
+		{
+			name:  "Mute/NoDuration",
+			steps: []objects.EscalationStep{{Points: 3, Action: objects.AutomodActionMute}},
+		},
+		{
+			name:  "Kick/Duration",
+			steps: []objects.EscalationStep{{Points: 3, Action: objects.AutomodActionKick, DurationSeconds: 60}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(objects.EscalationConfig{Enabled: true, Steps: tc.steps})
+			require.ErrorIs(t, err, ErrInvalidPolicy)
+		})
+	}
+}
+
+func TestStanding(t *testing.T) {
+	now := time.Now()
+	infractions := []Infraction{
+		{Action: objects.AutomodActionBan, CreatedAt: now.AddDate(0, 0, -7)},
+		{Action: objects.AutomodActionKick, CreatedAt: now.AddDate(0, 0, -6)},
+		{Action: objects.AutomodActionWarn, CreatedAt: now},
+		{Action: "unban", CreatedAt: now},
+	}
+
+	require.Equal(t, now.AddDate(0, 0, -7), Horizon(policy, now))
+	require.Equal(t, 3, Standing(policy, infractions, now))
+
+	forever := policy
+	forever.DecayDays = 0
+	require.True(t, Horizon(forever, now).IsZero())
+	require.Equal(t, 6, Standing(forever, infractions, now))
+}
+
+func TestEscalate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		standing int
+		action   string
+		duration int64
+		decision Decision
+	}{
+		{
+			name:     "BelowThreshold",
+			standing: 2,
+			action:   objects.AutomodActionWarn,
+			decision: Decision{Action: objects.AutomodActionWarn},
+		},
+		{
+			name:     "Mute",
+			standing: 3,
+			action:   objects.AutomodActionWarn,
+			decision: Decision{
+				Action:          objects.AutomodActionMute,
+				DurationSeconds: 3600,
+				Escalated:       true,
+				Step:            &policy.Steps[1],
+			},
+		},
+		{
+			name:     "HighestStep",
+			standing: 6,
+			action:   objects.AutomodActionWarn,
+			decision: Decision{Action: objects.AutomodActionBan, Escalated: true, Step: &policy.Steps[0]},
+		},
+		{
+			name:     "LongerMute",
+			standing: 4,
+			action:   objects.AutomodActionMute,
+			duration: 7200,
+			decision: Decision{Action: objects.AutomodActionMute, DurationSeconds: 7200},
+		},
+		{
+			name:     "ShorterMute",
+			standing: 4,
+			action:   objects.AutomodActionMute,
+			duration: 600,
+			decision: Decision{
+				Action:          objects.AutomodActionMute,
+				DurationSeconds: 3600,
+				Escalated:       true,
+				Step:            &policy.Steps[1],
+			},
+		},
+		{
+			name:     "TemporaryBan",
+			standing: 5,
+			action:   objects.AutomodActionBan,
+			duration: 86400,
+			decision: Decision{Action: objects.AutomodActionBan, Escalated: true, Step: &policy.Steps[0]},
+		},
+		{
+			name:     "Unban",
+			standing: 10,
+			action:   "unban",
+			decision: Decision{Action: "unban"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.decision, Escalate(policy, tc.standing, tc.action, tc.duration))
+		})
+	}
+
+	disabled := policy
+	disabled.Enabled = false
+	require.Equal(t, Decision{Action: objects.AutomodActionWarn}, Escalate(disabled, 10, objects.AutomodActionWarn, 0))
+}
@@ -0,0 +1,172 @@
+package raid
+
+import (
+	"errors"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidConfig = errors.New("invalid raid config")
+
+// member events reported by the bot
+const (
+	EventJoin  = "join"
+	EventLeave = "leave"
+)
+
+// triggers of a detection, named after thresholds of the config
+const (
+	TriggerJoinBurst       = "join_burst"
+	TriggerNewAccountBurst = "new_account_burst"
+	TriggerSimilarUsername = "similar_username_burst"
+)
+
+type Event struct {
+	Type             string
+	MemberDiscordID  string
+	Username         string
+	AccountCreatedAt time.Time
+	OccurredAt       time.Time
+}
+
+// Detection describes joins within the window, it is a raid if any threshold was reached
+type Detection struct {
+	Triggers    []string `json:"triggers"`
+	Joins       int      `json:"joins"`
+	NewAccounts int      `json:"new_accounts"`
+	// SimilarUsername is the skeleton shared by most joined usernames, SimilarUsernames counts them
+	SimilarUsername  string `json:"similar_username"`
+	SimilarUsernames int    `json:"similar_usernames"`
+}
+
+func (d Detection) Raid() bool {
+	return len(d.Triggers) > 0
+}
+
+type TimelineBucket struct {
+	Start           time.Time `json:"start"`
+	Joins           int       `json:"joins"`
+	Leaves          int       `json:"leaves"`
+	NewAccountJoins int       `json:"new_account_joins"`
+}
+
+// lookalikes map characters commonly used to disguise generated usernames
+var lookalikes = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+}
+
+// Validate checks the config beyond binding tags: enabled detection needs the window and at least one threshold
+func Validate(config objects.RaidConfig) error {
+	if config.Enabled && config.WindowSeconds == 0 {
+		return fmt.Errorf("%w: enabled raid detection needs window_seconds", ErrInvalidConfig)
+	}
+	if config.Enabled && config.JoinBurst == 0 && config.NewAccountBurst == 0 && config.SimilarUsernameBurst == 0 {
+		return fmt.Errorf("%w: enabled raid detection needs at least one threshold", ErrInvalidConfig)
+	}
+	if config.NewAccountBurst > 0 && config.NewAccountDays == 0 {
+		return fmt.Errorf("%w: new_account_burst needs new_account_days", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Window returns start of the window ending at now
+func Window(config objects.RaidConfig, now time.Time) time.Time {
+	return now.Add(-time.Duration(config.WindowSeconds) * time.Second)
+}
+
+// Skeleton normalizes the username, so "Raider_01", "r4ider77" and "RAIDER" share the skeleton "raider"
+func Skeleton(username string) string {
+	username = strings.TrimRightFunc(strings.ToLower(username), unicode.IsDigit)
+
+	var b strings.Builder
+	for _, r := range username {
+		if l, ok := lookalikes[r]; ok {
+			r = l
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// IsNewAccount reports whether the account was created less than NewAccountDays before the time
+func IsNewAccount(config objects.RaidConfig, accountCreatedAt, at time.Time) bool {
+	return config.NewAccountDays > 0 && accountCreatedAt.After(at.AddDate(0, 0, -config.NewAccountDays))
+}
+
+// Detect checks joins which occurred within the window ending at now against thresholds of the config
+func Detect(config objects.RaidConfig, events []Event, now time.Time) Detection {
+	var detection Detection
+	windowStart := Window(config, now)
+	skeletons := make(map[string]int)
+	for _, event := range events {
+		if event.Type != EventJoin || !event.OccurredAt.After(windowStart) || event.OccurredAt.After(now) {
+			continue
+		}
+		detection.Joins++
+		if IsNewAccount(config, event.AccountCreatedAt, event.OccurredAt) {
+			detection.NewAccounts++
+		}
+		if skeleton := Skeleton(event.Username); skeleton != "" {
+			skeletons[skeleton]++
+			count := skeletons[skeleton]
+			if count > detection.SimilarUsernames || (count == detection.SimilarUsernames && skeleton < detection.SimilarUsername) {
+				detection.SimilarUsername = skeleton
+				detection.SimilarUsernames = count
+			}
+		}
+	}
+
+	detection.Triggers = make([]string, 0)
+	if reached(config.JoinBurst, detection.Joins) {
+		detection.Triggers = append(detection.Triggers, TriggerJoinBurst)
+	}
+	if reached(config.NewAccountBurst, detection.NewAccounts) {
+		detection.Triggers = append(detection.Triggers, TriggerNewAccountBurst)
+	}
+	if reached(config.SimilarUsernameBurst, detection.SimilarUsernames) {
+		detection.Triggers = append(detection.Triggers, TriggerSimilarUsername)
+	}
+	return detection
+}
+
+// Timeline counts events in buckets of the given size starting at from, events outside [from, to) are skipped
+func Timeline(config objects.RaidConfig, events []Event, from, to time.Time, bucket time.Duration) []TimelineBucket {
+	timeline := make([]TimelineBucket, 0, int(to.Sub(from)/bucket)+1)
+	for start := from; start.Before(to); start = start.Add(bucket) {
+		timeline = append(timeline, TimelineBucket{Start: start})
+	}
+
+	for _, event := range events {
+		if event.OccurredAt.Before(from) || !event.OccurredAt.Before(to) {
+			continue
+		}
+		b := &timeline[int(event.OccurredAt.Sub(from)/bucket)]
+		switch event.Type {
+		case EventJoin:
+			b.Joins++
+			if IsNewAccount(config, event.AccountCreatedAt, event.OccurredAt) {
+				b.NewAccountJoins++
+			}
+		case EventLeave:
+			b.Leaves++
+		}
+	}
+	return timeline
+}
+
+func reached(threshold, count int) bool {
+	return threshold > 0 && count >= threshold
+}
@@ -0,0 +1,131 @@
+package raid
+
+import (
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var config = objects.RaidConfig{
+	Enabled:              true,
+	WindowSeconds:        60,
+	JoinBurst:            5,
+	NewAccountDays:       7,
+	NewAccountBurst:      3,
+	SimilarUsernameBurst: 3,
+}
+
+func join(username string, accountAge time.Duration, at time.Time) Event {
+	return Event{
+		Type:             EventJoin,
+		Username:         username,
+		AccountCreatedAt: at.Add(-accountAge),
+		OccurredAt:       at,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate(config))
+	require.NoError(t, Validate(objects.RaidConfig{}))
+	require.ErrorIs(t, Validate(objects.RaidConfig{Enabled: true, WindowSeconds: 60}), ErrInvalidConfig)
+	require.ErrorIs(t, Validate(objects.RaidConfig{NewAccountBurst: 3}), ErrInvalidConfig)
+	require.ErrorIs(t, Validate(objects.RaidConfig{Enabled: true, JoinBurst: 5}), ErrInvalidConfig)
+}
+
+func TestSkeleton(t *testing.T) {
+	require.Equal(t, "raider", Skeleton("Raider_01"))
+	require.Equal(t, "raider", Skeleton("r4ider77"))
+	require.Equal(t, "raider", Skeleton("RAIDER"))
+	require.Equal(t, "spammer", Skeleton("$pamm3r.99"))
+	require.Equal(t, "", Skeleton("12345"))
+	require.NotEqual(t, Skeleton("alice"), Skeleton("bob"))
+}
+
+func TestDetect(t *testing.T) {
+	now := time.Now()
+	old := 365 * 24 * time.Hour
+	young := time.Hour
+
+	testCases := []struct {
+		name      string
+		events    []Event
+		detection Detection
+	}{
+		{
+			name: "Quiet",
+			events: []Event{
+				join("alice", old, now.Add(-10*time.Second)),
+				join("bob", young, now.Add(-20*time.Second)),
+			},
+			detection: Detection{Triggers: []string{}, Joins: 2, NewAccounts: 1, SimilarUsername: "alice", SimilarUsernames: 1},
+		},
+		{
+			name: "JoinBurst",
+			events: []Event{
+				join("alice", old, now),
+				join("bob", old, now),
+				join("carol", old, now),
+				join("dave", old, now),
+				join("erin", old, now),
+			},
+			detection: Detection{Triggers: []string{TriggerJoinBurst}, Joins: 5, SimilarUsername: "alice", SimilarUsernames: 1},
+		},
+		{
+			name: "NewAccountsWithSimilarNames",
+			events: []Event{
+				join("raider1", young, now),
+				join("r4ider2", young, now),
+				join("RAIDER_3", young, now),
+				{Type: EventLeave, Username: "raider4", OccurredAt: now},
+			},
+			detection: Detection{
+				Triggers:         []string{TriggerNewAccountBurst, TriggerSimilarUsername},
+				Joins:            3,
+				NewAccounts:      3,
+				SimilarUsername:  "raider",
+				SimilarUsernames: 3,
+			},
+		},
+		{
+			name: "OutsideWindow",
+			events: []Event{
+				join("raider1", young, now.Add(-60*time.Second)),
+				join("raider2", young, now.Add(-2*time.Minute)),
+				join("raider3", young, now.Add(time.Minute)),
+			},
+			detection: Detection{Triggers: []string{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			detection := Detect(config, tc.events, now)
+			require.Equal(t, tc.detection, detection)
+			require.Equal(t, len(tc.detection.Triggers) > 0, detection.Raid())
+		})
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	from := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Minute)
+
+	var events []Event
+	for i := 0; i < 4; i++ {
+		events = append(events, join(fmt.Sprintf("raider%d", i), time.Hour, from.Add(time.Minute+time.Duration(i)*time.Second)))
+	}
+	events = append(events,
+		join("alice", 365*24*time.Hour, from),
+		Event{Type: EventLeave, OccurredAt: from.Add(2*time.Minute + 59*time.Second)},
+		join("late", time.Hour, to),
+		join("early", time.Hour, from.Add(-time.Second)),
+	)
+
+	require.Equal(t, []TimelineBucket{
+		{Start: from, Joins: 1},
+		{Start: from.Add(time.Minute), Joins: 4, NewAccountJoins: 4},
+		{Start: from.Add(2 * time.Minute), Leaves: 1},
+	}, Timeline(config, events, from, to, time.Minute))
+}
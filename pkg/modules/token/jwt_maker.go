@@ -0,0 +1,181 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"sort"
+	"time"
+)
+
+const minHS256KeySize = 32
+
+// JWTMaker creates JWT for tools which do not support PASETO. HS256 tokens are
+// verified by the backend only, EdDSA tokens can be verified using published JWKS
+type JWTMaker struct {
+	method           jwt.SigningMethod
+	signingKey       interface{}
+	keyID            string
+	verificationKeys map[string]interface{}
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Guild *GuildClaims `json:"guild,omitempty"`
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// JWKSet is implemented by makers which publish their keys as JWKS
+type JWKSet interface {
+	JWKs() []JWK
+}
+
+func NewHS256JWTMaker(symmetricKey string) (Maker, error) {
+	if len(symmetricKey) < minHS256KeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minHS256KeySize)
+	}
+	return &JWTMaker{
+		method:           jwt.SigningMethodHS256,
+		signingKey:       []byte(symmetricKey),
+		verificationKeys: map[string]interface{}{"": []byte(symmetricKey)},
+	}, nil
+}
+
+// NewEdDSAJWTMaker creates maker signing with base64url encoded Ed25519 private key. Tokens
+// signed by previous keys remain valid while their public keys are listed in verificationKeys
+func NewEdDSAJWTMaker(secretKey string, verificationKeys []string) (Maker, error) {
+	sk, err := b64.DecodeString(secretKey)
+	if err != nil || len(sk) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid secret key")
+	}
+	pk := ed25519.PrivateKey(sk).Public().(ed25519.PublicKey)
+
+	maker := &JWTMaker{
+		method:           jwt.SigningMethodEdDSA,
+		signingKey:       ed25519.PrivateKey(sk),
+		keyID:            JWKThumbprint(pk),
+		verificationKeys: map[string]interface{}{JWKThumbprint(pk): pk},
+	}
+	for _, key := range verificationKeys {
+		if key == "" {
+			continue
+		}
+		pk, err := b64.DecodeString(key)
+		if err != nil || len(pk) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key")
+		}
+		maker.verificationKeys[JWKThumbprint(pk)] = ed25519.PublicKey(pk)
+	}
+	return maker, nil
+}
+
+func (m *JWTMaker) CreateToken(userDiscordID string, duration time.Duration) (string, *Payload, error) {
+	return m.createToken(NewPayload(userDiscordID, duration))
+}
+
+func (m *JWTMaker) CreateGuildToken(userDiscordID string, guild GuildClaims, duration time.Duration) (string, *Payload, error) {
+	return m.createToken(NewGuildPayload(userDiscordID, guild, duration))
+}
+
+func (m *JWTMaker) createToken(payload *Payload) (string, *Payload, error) {
+	claims := jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.UserDiscordID,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+		Guild: payload.Guild,
+	}
+
+	jwtToken := jwt.NewWithClaims(m.method, claims)
+	if m.keyID != "" {
+		jwtToken.Header["kid"] = m.keyID
+	}
+	token, err := jwtToken.SignedString(m.signingKey)
+	return token, payload, err
+}
+
+func (m *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		keyID, _ := t.Header["kid"].(string)
+		key, ok := m.verificationKeys[keyID]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return key, nil
+	}
+
+	var claims jwtClaims
+	_, err := jwt.ParseWithClaims(token, &claims, keyFunc, jwt.WithValidMethods([]string{m.method.Alg()}))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	id, err := uuid.Parse(claims.ID)
+	if err != nil || claims.IssuedAt == nil || claims.ExpiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+	payload := &Payload{
+		ID:            id,
+		UserDiscordID: claims.Subject,
+		Guild:         claims.Guild,
+		IssuedAt:      claims.IssuedAt.Time,
+		ExpiredAt:     claims.ExpiresAt.Time,
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// JWKs returns the current signing key first, followed by the previous keys
+func (m *JWTMaker) JWKs() []JWK {
+	if m.method != jwt.SigningMethodEdDSA {
+		return nil
+	}
+
+	keys := make([]JWK, 0, len(m.verificationKeys))
+	for id, key := range m.verificationKeys {
+		keys = append(keys, JWK{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         b64.EncodeToString(key.(ed25519.PublicKey)),
+			KeyID:     id,
+			Use:       "sig",
+			Algorithm: m.method.Alg(),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].KeyID == m.keyID || keys[j].KeyID == m.keyID {
+			return keys[i].KeyID == m.keyID
+		}
+		return keys[i].KeyID < keys[j].KeyID
+	})
+	return keys
+}
+
+// JWKThumbprint returns RFC 7638 thumbprint of Ed25519 public key, used as key ID
+func JWKThumbprint(publicKey ed25519.PublicKey) string {
+	jwk := fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":"%s"}`, b64.EncodeToString(publicKey))
+	hash := sha256.Sum256([]byte(jwk))
+	return b64.EncodeToString(hash[:])
+}
@@ -0,0 +1,140 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewJWTMaker(t *testing.T) {
+	secretKey, publicKey := generateEdDSAKeys(t)
+
+	testCases := []struct {
+		name        string
+		newMaker    func() (Maker, error)
+		checkResult func(t *testing.T, maker Maker, err error)
+	}{
+		{
+			name: "OK/HS256",
+			newMaker: func() (Maker, error) {
+				return NewHS256JWTMaker(utils.RandomString(32))
+			},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, maker)
+				require.Empty(t, maker.(JWKSet).JWKs())
+			},
+		},
+		{
+			name: "ShortKey/HS256",
+			newMaker: func() (Maker, error) {
+				return NewHS256JWTMaker(utils.RandomString(31))
+			},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Empty(t, maker)
+			},
+		},
+		{
+			name: "OK/EdDSA",
+			newMaker: func() (Maker, error) {
+				return NewEdDSAJWTMaker(secretKey, nil)
+			},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, maker)
+				require.Len(t, maker.(JWKSet).JWKs(), 1)
+			},
+		},
+		{
+			name: "PublicKeyAsSecretKey/EdDSA",
+			newMaker: func() (Maker, error) {
+				return NewEdDSAJWTMaker(publicKey, nil)
+			},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Empty(t, maker)
+			},
+		},
+		{
+			name: "InvalidVerificationKey/EdDSA",
+			newMaker: func() (Maker, error) {
+				return NewEdDSAJWTMaker(secretKey, []string{"not a key"})
+			},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Empty(t, maker)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := tc.newMaker()
+			tc.checkResult(t, maker, err)
+		})
+	}
+}
+
+func TestJWTMakerRejectsAlgorithmConfusion(t *testing.T) {
+	secretKey, publicKey := generateEdDSAKeys(t)
+	maker, err := NewEdDSAJWTMaker(secretKey, nil)
+	require.NoError(t, err)
+
+	// HS256 token signed with the public key must not be accepted by EdDSA maker
+	pk, err := base64.RawURLEncoding.DecodeString(publicKey)
+	require.NoError(t, err)
+	claims := jwt.RegisteredClaims{
+		ID:        "00000000-0000-0000-0000-000000000000",
+		Subject:   utils.RandomSnowflakeID().String(),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	jwtToken.Header["kid"] = JWKThumbprint(pk)
+	token, err := jwtToken.SignedString(pk)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Empty(t, payload)
+}
+
+func TestEdDSAJWTMakerKeyRotation(t *testing.T) {
+	oldSecretKey, oldPublicKey := generateEdDSAKeys(t)
+	newSecretKey, _ := generateEdDSAKeys(t)
+	userDiscordID := utils.RandomSnowflakeID().String()
+
+	oldMaker, err := NewEdDSAJWTMaker(oldSecretKey, nil)
+	require.NoError(t, err)
+	oldToken, _, err := oldMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+
+	rotatedMaker, err := NewEdDSAJWTMaker(newSecretKey, []string{oldPublicKey})
+	require.NoError(t, err)
+	payload, err := rotatedMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, userDiscordID, payload.UserDiscordID)
+
+	keys := rotatedMaker.(JWKSet).JWKs()
+	require.Len(t, keys, 2)
+	require.Equal(t, oldPublicKey, keys[1].X)
+	pk, err := base64.RawURLEncoding.DecodeString(keys[0].X)
+	require.NoError(t, err)
+	require.Equal(t, JWKThumbprint(ed25519.PublicKey(pk)), keys[0].KeyID)
+
+	newMaker, err := NewEdDSAJWTMaker(newSecretKey, nil)
+	require.NoError(t, err)
+	_, err = newMaker.VerifyToken(oldToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+// RFC 8037 appendix A.3 thumbprint of the example Ed25519 key
+func TestJWKThumbprint(t *testing.T) {
+	pk, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	require.NoError(t, err)
+	require.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", JWKThumbprint(pk))
+}
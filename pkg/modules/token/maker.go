@@ -2,7 +2,27 @@ package token
 
 import "time"
 
+// Supported makers, selected by TOKEN_MAKER config
+const (
+	MakerPasetoV2Local  = "paseto_v2_local"
+	MakerPasetoV4Public = "paseto_v4_public"
+	MakerJWTHS256       = "jwt_hs256"
+	MakerJWTEdDSA       = "jwt_eddsa"
+)
+
 type Maker interface {
 	CreateToken(userDiscordID string, duration time.Duration) (string, *Payload, error)
+	CreateGuildToken(userDiscordID string, guild GuildClaims, duration time.Duration) (string, *Payload, error)
 	VerifyToken(token string) (*Payload, error)
 }
+
+// PublicKey is a serialized key other services can use to verify tokens offline
+type PublicKey struct {
+	ID  string `json:"kid"`
+	Key string `json:"key"`
+}
+
+// KeySet is implemented by makers using asymmetric keys
+type KeySet interface {
+	PublicKeys() []PublicKey
+}
@@ -0,0 +1,200 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// makerFactories create independent makers with freshly generated keys
+var makerFactories = map[string]func(t *testing.T) Maker{
+	MakerPasetoV2Local: func(t *testing.T) Maker {
+		maker, err := NewPasetoMaker(utils.RandomString(32))
+		require.NoError(t, err)
+		return maker
+	},
+	MakerPasetoV4Public: func(t *testing.T) Maker {
+		secretKey, _ := generatePaserkKeys(t)
+		maker, err := NewPasetoV4PublicMaker(secretKey, nil)
+		require.NoError(t, err)
+		return maker
+	},
+	MakerJWTHS256: func(t *testing.T) Maker {
+		maker, err := NewHS256JWTMaker(utils.RandomString(32))
+		require.NoError(t, err)
+		return maker
+	},
+	MakerJWTEdDSA: func(t *testing.T) Maker {
+		secretKey, _ := generateEdDSAKeys(t)
+		maker, err := NewEdDSAJWTMaker(secretKey, nil)
+		require.NoError(t, err)
+		return maker
+	},
+}
+
+func generateEdDSAKeys(t *testing.T) (string, string) {
+	publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(secretKey), base64.RawURLEncoding.EncodeToString(publicKey)
+}
+
+// tamper replaces a single character in the middle of the token
+func tamper(token string) string {
+	b := []byte(token)
+	i := len(b) / 2
+	for b[i] == '.' {
+		i++
+	}
+	if b[i] == 'A' {
+		b[i] = 'B'
+	} else {
+		b[i] = 'A'
+	}
+	return string(b)
+}
+
+func TestTokenMakers(t *testing.T) {
+	userDiscordID := utils.RandomSnowflakeID().String()
+
+	testCases := []struct {
+		name        string
+		duration    time.Duration
+		buildToken  func(t *testing.T, maker Maker, token string) (Maker, string)
+		checkVerify func(t *testing.T, payload *Payload, err error)
+	}{
+		{
+			name:     "OK",
+			duration: time.Minute,
+			buildToken: func(t *testing.T, maker Maker, token string) (Maker, string) {
+				return maker, token
+			},
+			checkVerify: func(t *testing.T, payload *Payload, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, payload)
+				require.NotZero(t, payload.ID)
+				require.Equal(t, userDiscordID, payload.UserDiscordID)
+				require.WithinDuration(t, time.Now(), payload.IssuedAt, time.Second)
+				require.WithinDuration(t, time.Now().Add(time.Minute), payload.ExpiredAt, time.Second)
+			},
+		},
+		{
+			name:     "TokenExpired",
+			duration: -time.Minute,
+			buildToken: func(t *testing.T, maker Maker, token string) (Maker, string) {
+				return maker, token
+			},
+			checkVerify: func(t *testing.T, payload *Payload, err error) {
+				require.ErrorIs(t, err, ErrExpiredToken)
+				require.Empty(t, payload)
+			},
+		},
+		{
+			name:     "TokenTampered",
+			duration: time.Minute,
+			buildToken: func(t *testing.T, maker Maker, token string) (Maker, string) {
+				return maker, tamper(token)
+			},
+			checkVerify: func(t *testing.T, payload *Payload, err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				require.Empty(t, payload)
+			},
+		},
+		{
+			name:     "TokenTruncated",
+			duration: time.Minute,
+			buildToken: func(t *testing.T, maker Maker, token string) (Maker, string) {
+				return maker, token[:len(token)-8]
+			},
+			checkVerify: func(t *testing.T, payload *Payload, err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				require.Empty(t, payload)
+			},
+		},
+		{
+			name:     "WrongKey",
+			duration: time.Minute,
+			buildToken: func(t *testing.T, maker Maker, token string) (Maker, string) {
+				return nil, token
+			},
+			checkVerify: func(t *testing.T, payload *Payload, err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				require.Empty(t, payload)
+			},
+		},
+	}
+
+	for makerName, newMaker := range makerFactories {
+		newMaker := newMaker
+		t.Run(makerName, func(t *testing.T) {
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					maker := newMaker(t)
+
+					token, _, err := maker.CreateToken(userDiscordID, tc.duration)
+					require.NoError(t, err)
+					require.NotEmpty(t, token)
+
+					verifier, token := tc.buildToken(t, maker, token)
+					if verifier == nil {
+						verifier = newMaker(t)
+					}
+
+					payload, err := verifier.VerifyToken(token)
+					tc.checkVerify(t, payload, err)
+				})
+			}
+		})
+	}
+}
+
+func TestTokenMakersRejectForeignTokens(t *testing.T) {
+	userDiscordID := utils.RandomSnowflakeID().String()
+
+	for signerName, newSigner := range makerFactories {
+		token, _, err := newSigner(t).CreateToken(userDiscordID, time.Minute)
+		require.NoError(t, err)
+
+		for verifierName, newVerifier := range makerFactories {
+			if verifierName == signerName {
+				continue
+			}
+			payload, err := newVerifier(t).VerifyToken(token)
+			require.ErrorIs(t, err, ErrInvalidToken, "%s token verified by %s", signerName, verifierName)
+			require.Empty(t, payload)
+		}
+	}
+}
+
+func TestTokenMakersGuildClaims(t *testing.T) {
+	userDiscordID := utils.RandomSnowflakeID().String()
+	guild := GuildClaims{
+		DiscordID:    utils.RandomSnowflakeID().String(),
+		Capabilities: []string{CapabilityGuildConfigRead},
+	}
+
+	for makerName, newMaker := range makerFactories {
+		t.Run(makerName, func(t *testing.T) {
+			maker := newMaker(t)
+
+			token, _, err := maker.CreateGuildToken(userDiscordID, guild, time.Minute)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(token)
+			require.NoError(t, err)
+			require.Equal(t, &guild, payload.Guild)
+			require.True(t, payload.HasCapability(guild.DiscordID, CapabilityGuildConfigRead))
+			require.False(t, payload.HasCapability(guild.DiscordID, CapabilityGuildConfigEdit))
+			require.False(t, payload.HasCapability(utils.RandomSnowflakeID().String(), CapabilityGuildConfigRead))
+
+			token, _, err = maker.CreateToken(userDiscordID, time.Minute)
+			require.NoError(t, err)
+			payload, err = maker.VerifyToken(token)
+			require.NoError(t, err)
+			require.Nil(t, payload.Guild)
+		})
+	}
+}
@@ -23,7 +23,14 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 }
 
 func (m *PasetoMaker) CreateToken(userDiscordID string, duration time.Duration) (string, *Payload, error) {
-	payload := NewPayload(userDiscordID, duration)
+	return m.createToken(NewPayload(userDiscordID, duration))
+}
+
+func (m *PasetoMaker) CreateGuildToken(userDiscordID string, guild GuildClaims, duration time.Duration) (string, *Payload, error) {
+	return m.createToken(NewGuildPayload(userDiscordID, guild, duration))
+}
+
+func (m *PasetoMaker) createToken(payload *Payload) (string, *Payload, error) {
 	token, err := m.paseto.Encrypt(m.symmetricKey, payload, nil)
 	return token, payload, err
 }
@@ -32,7 +39,7 @@ func (m *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	var payload = new(Payload)
 	err := m.paseto.Decrypt(token, m.symmetricKey, payload, nil)
 	if err != nil {
-		return nil, err
+		return nil, ErrInvalidToken
 	}
 
 	err = payload.Valid()
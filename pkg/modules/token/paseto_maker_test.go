@@ -4,7 +4,6 @@ import (
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/stretchr/testify/require"
 	"testing"
-	"time"
 )
 
 func TestNewPasetoMaker(t *testing.T) {
@@ -37,52 +36,3 @@ func TestNewPasetoMaker(t *testing.T) {
 		})
 	}
 }
-
-func TestPasetoTokenMaker(t *testing.T) {
-	userDiscordID := utils.RandomSnowflakeID().String()
-	issuedAt := time.Now()
-
-	testCases := []struct {
-		name          string
-		scope         string
-		userDiscordID string
-		duration      time.Duration
-		checkVerify   func(t *testing.T, payload *Payload, err error)
-	}{
-		{
-			name:          "OK",
-			userDiscordID: userDiscordID,
-			duration:      time.Minute,
-			checkVerify: func(t *testing.T, payload *Payload, err error) {
-				require.NoError(t, err)
-				require.NotEmpty(t, payload)
-				require.NotZero(t, payload.ID)
-				require.Equal(t, userDiscordID, payload.UserDiscordID)
-				require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
-				require.WithinDuration(t, issuedAt.Add(time.Minute), payload.ExpiredAt, time.Second)
-			},
-		},
-		{
-			name:          "TokenExpired",
-			userDiscordID: userDiscordID,
-			duration:      -time.Minute,
-			checkVerify: func(t *testing.T, payload *Payload, err error) {
-				require.ErrorIs(t, err, ErrExpiredToken)
-				require.Empty(t, payload)
-			},
-		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			maker, err := NewPasetoMaker(utils.RandomString(32))
-			require.NoError(t, err)
-
-			accessToken, _, err := maker.CreateToken(userDiscordID, tc.duration)
-			require.NoError(t, err)
-			require.NotEmpty(t, accessToken)
-
-			payload, err := maker.VerifyToken(accessToken)
-			tc.checkVerify(t, payload, err)
-		})
-	}
-}
@@ -0,0 +1,128 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/blake2b"
+	"strings"
+)
+
+// Minimal implementation of PASETO v4.public and PASERK key serialization,
+// see https://github.com/paseto-standard/paseto-spec and https://github.com/paseto-standard/paserk
+
+const (
+	v4PublicHeader    = "v4.public."
+	paserkPublicTag   = "k4.public."
+	paserkSecretTag   = "k4.secret."
+	paserkPublicIDTag = "k4.pid."
+)
+
+var ErrInvalidToken = errors.New("token is invalid")
+
+var b64 = base64.RawURLEncoding
+
+// pae is Pre-Authentication Encoding of PASETO
+func pae(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+	le64 := func(n int) {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(n)&(1<<63-1))
+		buf.Write(b[:])
+	}
+	le64(len(pieces))
+	for _, p := range pieces {
+		le64(len(p))
+		buf.Write(p)
+	}
+	return buf.Bytes()
+}
+
+func v4Sign(secretKey ed25519.PrivateKey, message []byte, footer []byte) string {
+	sig := ed25519.Sign(secretKey, pae([]byte(v4PublicHeader), message, footer, nil))
+	token := v4PublicHeader + b64.EncodeToString(append(message, sig...))
+	if len(footer) > 0 {
+		token += "." + b64.EncodeToString(footer)
+	}
+	return token
+}
+
+// v4Footer returns unverified footer of the token, it is used to pick verification key
+func v4Footer(token string) ([]byte, error) {
+	if !strings.HasPrefix(token, v4PublicHeader) {
+		return nil, ErrInvalidToken
+	}
+	parts := strings.Split(token[len(v4PublicHeader):], ".")
+	switch len(parts) {
+	case 1:
+		return nil, nil
+	case 2:
+		footer, err := b64.DecodeString(parts[1])
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		return footer, nil
+	default:
+		return nil, ErrInvalidToken
+	}
+}
+
+func v4Verify(publicKey ed25519.PublicKey, token string) ([]byte, error) {
+	footer, err := v4Footer(token)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.Split(token[len(v4PublicHeader):], ".")[0]
+	raw, err := b64.DecodeString(body)
+	if err != nil || len(raw) < ed25519.SignatureSize {
+		return nil, ErrInvalidToken
+	}
+	message, sig := raw[:len(raw)-ed25519.SignatureSize], raw[len(raw)-ed25519.SignatureSize:]
+
+	if !ed25519.Verify(publicKey, pae([]byte(v4PublicHeader), message, footer, nil), sig) {
+		return nil, ErrInvalidToken
+	}
+	return message, nil
+}
+
+func EncodePaserkPublic(publicKey ed25519.PublicKey) string {
+	return paserkPublicTag + b64.EncodeToString(publicKey)
+}
+
+func EncodePaserkSecret(secretKey ed25519.PrivateKey) string {
+	return paserkSecretTag + b64.EncodeToString(secretKey)
+}
+
+func DecodePaserkPublic(paserk string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(paserk, paserkPublicTag) {
+		return nil, fmt.Errorf("invalid public key: must start with %s", paserkPublicTag)
+	}
+	key, err := b64.DecodeString(paserk[len(paserkPublicTag):])
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key")
+	}
+	return key, nil
+}
+
+func DecodePaserkSecret(paserk string) (ed25519.PrivateKey, error) {
+	if !strings.HasPrefix(paserk, paserkSecretTag) {
+		return nil, fmt.Errorf("invalid secret key: must start with %s", paserkSecretTag)
+	}
+	key, err := b64.DecodeString(paserk[len(paserkSecretTag):])
+	if err != nil || len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid secret key")
+	}
+	return key, nil
+}
+
+// PaserkPublicID returns k4.pid identifier of the public key
+func PaserkPublicID(publicKey ed25519.PublicKey) string {
+	h, _ := blake2b.New(33, nil)
+	h.Write([]byte(paserkPublicIDTag))
+	h.Write([]byte(EncodePaserkPublic(publicKey)))
+	return paserkPublicIDTag + b64.EncodeToString(h.Sum(nil))
+}
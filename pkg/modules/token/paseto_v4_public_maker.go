@@ -0,0 +1,114 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// PasetoV4PublicMaker signs tokens with Ed25519 key, so they can be verified
+// by other services using only published public keys
+type PasetoV4PublicMaker struct {
+	secretKey        ed25519.PrivateKey
+	keyID            string
+	verificationKeys map[string]ed25519.PublicKey
+}
+
+type pasetoFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// NewPasetoV4PublicMaker creates maker signing with PASERK k4.secret key. Tokens signed by
+// previous keys remain valid while their k4.public keys are listed in verificationKeys
+func NewPasetoV4PublicMaker(secretKey string, verificationKeys []string) (Maker, error) {
+	sk, err := DecodePaserkSecret(secretKey)
+	if err != nil {
+		return nil, err
+	}
+	pk := sk.Public().(ed25519.PublicKey)
+
+	maker := &PasetoV4PublicMaker{
+		secretKey:        sk,
+		keyID:            PaserkPublicID(pk),
+		verificationKeys: map[string]ed25519.PublicKey{PaserkPublicID(pk): pk},
+	}
+	for _, key := range verificationKeys {
+		if key == "" {
+			continue
+		}
+		pk, err := DecodePaserkPublic(key)
+		if err != nil {
+			return nil, err
+		}
+		maker.verificationKeys[PaserkPublicID(pk)] = pk
+	}
+	return maker, nil
+}
+
+func (m *PasetoV4PublicMaker) CreateToken(userDiscordID string, duration time.Duration) (string, *Payload, error) {
+	return m.createToken(NewPayload(userDiscordID, duration))
+}
+
+func (m *PasetoV4PublicMaker) CreateGuildToken(userDiscordID string, guild GuildClaims, duration time.Duration) (string, *Payload, error) {
+	return m.createToken(NewGuildPayload(userDiscordID, guild, duration))
+}
+
+func (m *PasetoV4PublicMaker) createToken(payload *Payload) (string, *Payload, error) {
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+	footer, err := json.Marshal(pasetoFooter{KeyID: m.keyID})
+	if err != nil {
+		return "", nil, err
+	}
+	return v4Sign(m.secretKey, message, footer), payload, nil
+}
+
+func (m *PasetoV4PublicMaker) VerifyToken(token string) (*Payload, error) {
+	rawFooter, err := v4Footer(token)
+	if err != nil {
+		return nil, err
+	}
+	var footer pasetoFooter
+	if err := json.Unmarshal(rawFooter, &footer); err != nil {
+		return nil, ErrInvalidToken
+	}
+	publicKey, ok := m.verificationKeys[footer.KeyID]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	message, err := v4Verify(publicKey, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload = new(Payload)
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the current signing key first, followed by the previous keys
+func (m *PasetoV4PublicMaker) PublicKeys() []PublicKey {
+	keys := make([]PublicKey, 0, len(m.verificationKeys))
+	for id, pk := range m.verificationKeys {
+		keys = append(keys, PublicKey{ID: id, Key: EncodePaserkPublic(pk)})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ID == m.keyID || keys[j].ID == m.keyID {
+			return keys[i].ID == m.keyID
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
@@ -0,0 +1,121 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func generatePaserkKeys(t *testing.T) (string, string) {
+	publicKey, secretKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return EncodePaserkSecret(secretKey), EncodePaserkPublic(publicKey)
+}
+
+// official test vector 4-S-1 of PASETO specification
+func TestV4PublicSign(t *testing.T) {
+	secretKey, err := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774" +
+		"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	require.NoError(t, err)
+	message := []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+	expected := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9" +
+		"bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+
+	token := v4Sign(secretKey, message, nil)
+	require.Equal(t, expected, token)
+
+	verified, err := v4Verify(ed25519.PrivateKey(secretKey).Public().(ed25519.PublicKey), token)
+	require.NoError(t, err)
+	require.Equal(t, message, verified)
+}
+
+func TestNewPasetoV4PublicMaker(t *testing.T) {
+	secretKey, publicKey := generatePaserkKeys(t)
+
+	testCases := []struct {
+		name             string
+		secretKey        string
+		verificationKeys []string
+		checkResult      func(t *testing.T, maker Maker, err error)
+	}{
+		{
+			name:             "OK",
+			secretKey:        secretKey,
+			verificationKeys: []string{publicKey},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.NoError(t, err)
+				require.NotEmpty(t, maker)
+			},
+		},
+		{
+			name:      "InvalidSecretKey",
+			secretKey: "k4.secret." + utils.RandomString(32),
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Empty(t, maker)
+			},
+		},
+		{
+			name:      "PublicKeyAsSecretKey",
+			secretKey: publicKey,
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Empty(t, maker)
+			},
+		},
+		{
+			name:             "InvalidVerificationKey",
+			secretKey:        secretKey,
+			verificationKeys: []string{secretKey},
+			checkResult: func(t *testing.T, maker Maker, err error) {
+				require.Error(t, err)
+				require.Empty(t, maker)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := NewPasetoV4PublicMaker(tc.secretKey, tc.verificationKeys)
+			tc.checkResult(t, maker, err)
+		})
+	}
+}
+
+func TestPasetoV4PublicMakerKeyRotation(t *testing.T) {
+	oldSecretKey, oldPublicKey := generatePaserkKeys(t)
+	newSecretKey, _ := generatePaserkKeys(t)
+	userDiscordID := utils.RandomSnowflakeID().String()
+
+	oldMaker, err := NewPasetoV4PublicMaker(oldSecretKey, nil)
+	require.NoError(t, err)
+	oldToken, _, err := oldMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+
+	// old tokens are accepted while previous public key is configured
+	rotatedMaker, err := NewPasetoV4PublicMaker(newSecretKey, []string{oldPublicKey})
+	require.NoError(t, err)
+	payload, err := rotatedMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, userDiscordID, payload.UserDiscordID)
+
+	keys := rotatedMaker.(KeySet).PublicKeys()
+	require.Len(t, keys, 2)
+	require.Equal(t, oldPublicKey, keys[1].Key)
+	require.True(t, strings.HasPrefix(keys[0].ID, "k4.pid."))
+
+	newToken, _, err := rotatedMaker.CreateToken(userDiscordID, time.Minute)
+	require.NoError(t, err)
+	_, err = oldMaker.VerifyToken(newToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// and rejected once it is removed
+	newMaker, err := NewPasetoV4PublicMaker(newSecretKey, nil)
+	require.NoError(t, err)
+	_, err = newMaker.VerifyToken(oldToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -8,11 +8,27 @@ import (
 
 var ErrExpiredToken = errors.New("token has expired")
 
+// Capabilities which can be granted by guild-scoped tokens
+const (
+	CapabilityGuildConfigRead = "guild_config:read"
+	CapabilityGuildConfigEdit = "guild_config:edit"
+	CapabilityCasesRead       = "cases:read"
+	CapabilityCasesEdit       = "cases:edit"
+	CapabilityGuildLockdown   = "guild:lockdown"
+)
+
+// GuildClaims are capabilities of the user computed for a single guild when the token was issued
+type GuildClaims struct {
+	DiscordID    string   `json:"discord_id"`
+	Capabilities []string `json:"capabilities"`
+}
+
 type Payload struct {
-	ID            uuid.UUID `json:"id"`
-	UserDiscordID string    `json:"user_discord_id"`
-	IssuedAt      time.Time `json:"issued_at"`
-	ExpiredAt     time.Time `json:"expired_at"`
+	ID            uuid.UUID    `json:"id"`
+	UserDiscordID string       `json:"user_discord_id"`
+	Guild         *GuildClaims `json:"guild,omitempty"`
+	IssuedAt      time.Time    `json:"issued_at"`
+	ExpiredAt     time.Time    `json:"expired_at"`
 }
 
 func NewPayload(userDiscordID string, duration time.Duration) *Payload {
@@ -25,6 +41,25 @@ func NewPayload(userDiscordID string, duration time.Duration) *Payload {
 	}
 }
 
+func NewGuildPayload(userDiscordID string, guild GuildClaims, duration time.Duration) *Payload {
+	payload := NewPayload(userDiscordID, duration)
+	payload.Guild = &guild
+	return payload
+}
+
+// HasCapability reports whether the token is scoped to the guild and grants the capability
+func (p *Payload) HasCapability(guildDiscordID string, capability string) bool {
+	if p.Guild == nil || p.Guild.DiscordID != guildDiscordID {
+		return false
+	}
+	for _, c := range p.Guild.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Payload) Valid() error {
 	if time.Now().After(p.ExpiredAt) {
 		return ErrExpiredToken
@@ -0,0 +1,104 @@
+package verification
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"math/bits"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidConfig = errors.New("invalid verification config")
+
+// discordEpoch is the first millisecond of 2015, Discord snowflakes count time from it
+const discordEpoch = 1420070400000
+
+// requirements which an applicant may not meet
+const (
+	RequirementAccountAge    = "account_age"
+	RequirementVerifiedEmail = "verified_email"
+	RequirementAvatar        = "avatar"
+)
+
+// Applicant is the Discord account asking to be verified
+type Applicant struct {
+	DiscordID string
+	Verified  bool
+	Avatar    string
+}
+
+// ValidateConfig checks the config beyond binding tags: enabled verification needs a role to grant
+func ValidateConfig(config objects.VerificationConfig) error {
+	if config.Enabled && config.RoleID == "" {
+		return fmt.Errorf("%w: enabled verification needs role_id", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// AccountCreatedAt extracts creation time of the Discord account from its snowflake
+func AccountCreatedAt(discordID string) (time.Time, error) {
+	id, err := strconv.ParseUint(discordID, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(id>>22) + discordEpoch), nil
+}
+
+// UnmetRequirements returns requirements of the config the applicant does not meet, empty if verification may proceed
+func UnmetRequirements(config objects.VerificationConfig, applicant Applicant, now time.Time) ([]string, error) {
+	unmet := make([]string, 0)
+	if config.MinAccountAgeDays > 0 {
+		createdAt, err := AccountCreatedAt(applicant.DiscordID)
+		if err != nil {
+			return nil, err
+		}
+		if createdAt.After(now.AddDate(0, 0, -config.MinAccountAgeDays)) {
+			unmet = append(unmet, RequirementAccountAge)
+		}
+	}
+	if config.RequireVerifiedEmail && !applicant.Verified {
+		unmet = append(unmet, RequirementVerifiedEmail)
+	}
+	if config.RequireAvatar && applicant.Avatar == "" {
+		unmet = append(unmet, RequirementAvatar)
+	}
+	return unmet, nil
+}
+
+// NewChallenge returns random proof-of-work challenge
+func NewChallenge() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Verify reports whether sha256 of challenge followed by solution starts with difficulty zero bits
+func Verify(challenge string, difficulty int, solution string) bool {
+	hash := sha256.Sum256([]byte(challenge + solution))
+	return leadingZeroBits(hash[:]) >= difficulty
+}
+
+// Solve searches for a solution of the challenge the way clients are expected to
+func Solve(challenge string, difficulty int) string {
+	for nonce := uint64(0); ; nonce++ {
+		solution := strconv.FormatUint(nonce, 10)
+		if Verify(challenge, difficulty, solution) {
+			return solution
+		}
+	}
+}
+
+func leadingZeroBits(b []byte) int {
+	zeros := 0
+	for _, v := range b {
+		if v != 0 {
+			return zeros + bits.LeadingZeros8(v)
+		}
+		zeros += 8
+	}
+	return zeros
+}
@@ -0,0 +1,87 @@
+package verification
+
+import (
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// snowflakeAt returns Discord ID of an account created at the time
+func snowflakeAt(t time.Time) string {
+	return strconv.FormatUint(uint64(t.UnixMilli()-discordEpoch)<<22, 10)
+}
+
+func TestValidateConfig(t *testing.T) {
+	require.NoError(t, ValidateConfig(objects.VerificationConfig{}))
+	require.NoError(t, ValidateConfig(objects.VerificationConfig{Enabled: true, RoleID: "1"}))
+	require.ErrorIs(t, ValidateConfig(objects.VerificationConfig{Enabled: true}), ErrInvalidConfig)
+}
+
+func TestAccountCreatedAt(t *testing.T) {
+	createdAt, err := AccountCreatedAt("175928847299117063")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC), createdAt.UTC())
+
+	_, err = AccountCreatedAt("me")
+	require.Error(t, err)
+}
+
+func TestUnmetRequirements(t *testing.T) {
+	now := time.Now()
+	config := objects.VerificationConfig{
+		Enabled:              true,
+		RoleID:               "1",
+		MinAccountAgeDays:    7,
+		RequireVerifiedEmail: true,
+		RequireAvatar:        true,
+	}
+
+	testCases := []struct {
+		name      string
+		applicant Applicant
+		unmet     []string
+	}{
+		{
+			name:      "OK",
+			applicant: Applicant{DiscordID: snowflakeAt(now.AddDate(0, 0, -8)), Verified: true, Avatar: "a"},
+			unmet:     []string{},
+		},
+		{
+			name:      "YoungAccount",
+			applicant: Applicant{DiscordID: snowflakeAt(now.AddDate(0, 0, -6)), Verified: true, Avatar: "a"},
+			unmet:     []string{RequirementAccountAge},
+		},
+		{
+			name:      "Everything",
+			applicant: Applicant{DiscordID: snowflakeAt(now)},
+			unmet:     []string{RequirementAccountAge, RequirementVerifiedEmail, RequirementAvatar},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unmet, err := UnmetRequirements(config, tc.applicant, now)
+			require.NoError(t, err)
+			require.Equal(t, tc.unmet, unmet)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	challenge := NewChallenge()
+	require.Len(t, challenge, 32)
+	require.NotEqual(t, challenge, NewChallenge())
+
+	solution := Solve(challenge, 12)
+	require.True(t, Verify(challenge, 12, solution))
+	require.False(t, Verify(NewChallenge(), 20, solution))
+	require.True(t, Verify(challenge, 0, ""))
+}
+
+func TestLeadingZeroBits(t *testing.T) {
+	require.Equal(t, 0, leadingZeroBits([]byte{0x80}))
+	require.Equal(t, 11, leadingZeroBits([]byte{0x00, 0x10, 0xff}))
+	require.Equal(t, 16, leadingZeroBits([]byte{0x00, 0x00}))
+}
@@ -3,6 +3,7 @@ package pkg
 import (
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/controllers"
 	"github.com/BoggerByte/Sentinel-backend.git/pkg/middlewares"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/apikey"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -20,7 +21,10 @@ func NewServer(controllers controllers.Controllers, middlewares middlewares.Midd
 	router.Static("/pub", "./pub")
 
 	router.Use(middlewares.CORS)
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	router.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPathsRegexs([]string{
+		"^/api/v1/guilds/[^/]+/events$", // event streams must be flushed immediately
+		"^/api/v1/bot/commands$",
+	})))
 
 	perms := middlewares.Permissions
 
@@ -30,6 +34,9 @@ func NewServer(controllers controllers.Controllers, middlewares middlewares.Midd
 		c.HTML(http.StatusOK, "index.html", gin.H{})
 	})
 
+	router.GET("/.well-known/paserk", controllers.GetPaserk)
+	router.GET("/.well-known/jwks.json", controllers.GetJWKS)
+
 	router.NoRoute(func(c *gin.Context) {
 		c.HTML(http.StatusNotFound, "404.html", gin.H{})
 	})
@@ -37,18 +44,69 @@ func NewServer(controllers controllers.Controllers, middlewares middlewares.Midd
 	api := router.Group("/api/v1")
 	{
 		api.GET("/oauth2/new_url", controllers.GetNewOauth2URL)
-		api.GET("/oauth2/new_invite_bot_url", controllers.GetNewInviteBotURL)
+		api.GET("/oauth2/new_invite_bot_url", middlewares.Auth, middlewares.UserOnly, controllers.GetNewInviteBotURL)
 		api.GET("/oauth2/discord_callback", controllers.HandleDiscordCallback)
+		api.GET("/oauth2/flows/:state", controllers.PollOauth2Flow)
+		api.GET("/oauth2/invite_bot_callback", controllers.HandleInviteBotCallback)
 
 		api.POST("/auth/paseto/refresh", middlewares.Auth, controllers.RefreshToken)
+		api.POST("/auth/guild-token/:discord_id", middlewares.Auth, middlewares.UserOnly, controllers.CreateGuildToken)
 
-		api.GET("/users/me", middlewares.Auth, controllers.GetUser)
-		api.GET("/users/me/guilds", middlewares.Auth, controllers.GetUserGuilds)
-		api.GET("/users/me/guilds/:discord_id", middlewares.Auth, controllers.GetUserGuild)
+		api.GET("/users/me", middlewares.Auth, middlewares.UserOnly, controllers.GetUser)
+		api.DELETE("/users/me", middlewares.Auth, middlewares.UserOnly, controllers.DeleteUser)
+		api.GET("/users/me/export", middlewares.Auth, middlewares.UserOnly, controllers.ExportUser)
+		api.GET("/users/me/guilds", middlewares.Auth, middlewares.UserOnly, controllers.GetUserGuilds)
+		api.GET("/users/me/guilds/:discord_id", middlewares.Auth, middlewares.UserOnly, controllers.GetUserGuild)
+		api.GET("/users/me/bans", middlewares.Auth, middlewares.UserOnly, controllers.GetUserBans)
+		api.POST("/users/me/bans/:discord_id/appeals", middlewares.Auth, middlewares.UserOnly, controllers.SubmitAppeal)
+		api.GET("/users/me/appeals", middlewares.Auth, middlewares.UserOnly, controllers.GetUserAppeals)
+		api.GET("/users/me/verifications/:discord_id", middlewares.Auth, middlewares.UserOnly, controllers.GetVerification)
+		api.POST("/users/me/verifications/:discord_id", middlewares.Auth, middlewares.UserOnly, controllers.SubmitVerification)
 
 		api.GET("/guilds/configs/presets/:preset", controllers.GetGuildConfigPreset)
+		api.POST("/guilds/configs/bulk", middlewares.Auth, middlewares.UserOnly, controllers.BulkGuildConfig)
 		api.GET("/guilds/:discord_id/config", middlewares.Auth, perms.GuildConfig.Get(), controllers.GetGuildConfig)
 		api.POST("/guilds/:discord_id/config", middlewares.Auth, perms.GuildConfig.Overwrite(), controllers.OverwriteGuildConfig)
+		api.POST("/guilds/:discord_id/events/ticket", middlewares.Auth, perms.GuildConfig.Get(), controllers.CreateEventsTicket)
+		api.GET("/guilds/:discord_id/events", middlewares.StreamAuth, perms.GuildConfig.Get(), controllers.GetGuildEvents)
+		api.POST("/guilds/:discord_id/automod/test", middlewares.Auth, perms.GuildConfig.Get(), controllers.TestAutomod)
+		api.GET("/guilds/:discord_id/cases", middlewares.Auth, perms.Cases.Get(), controllers.GetCases)
+		api.GET("/guilds/:discord_id/cases/:case_number", middlewares.Auth, perms.Cases.Get(), controllers.GetCase)
+		api.PUT("/guilds/:discord_id/cases/:case_number/expiry", middlewares.Auth, perms.Cases.Edit(), controllers.RescheduleCaseExpiry)
+		api.DELETE("/guilds/:discord_id/cases/:case_number/expiry", middlewares.Auth, perms.Cases.Edit(), controllers.CancelCaseExpiry)
+		api.GET("/guilds/:discord_id/members/:member_id", middlewares.Auth, perms.Cases.Get(), controllers.GetMemberProfile)
+		api.POST("/guilds/:discord_id/members/:member_id/notes", middlewares.Auth, perms.Cases.Edit(), controllers.CreateMemberNote)
+		api.PUT("/guilds/:discord_id/members/:member_id/notes/:note_id", middlewares.Auth, perms.Cases.Edit(), controllers.UpdateMemberNote)
+		api.DELETE("/guilds/:discord_id/members/:member_id/notes/:note_id", middlewares.Auth, perms.Cases.Edit(), controllers.DeleteMemberNote)
+		api.GET("/guilds/:discord_id/members/:member_id/notes/:note_id/revisions", middlewares.Auth, perms.Cases.Get(), controllers.GetMemberNoteRevisions)
+		api.GET("/guilds/:discord_id/appeals", middlewares.Auth, perms.Cases.Get(), controllers.GetAppeals)
+		api.GET("/guilds/:discord_id/appeals/:appeal_id", middlewares.Auth, perms.Cases.Get(), controllers.GetAppeal)
+		api.POST("/guilds/:discord_id/appeals/:appeal_id/comments", middlewares.Auth, perms.Cases.Edit(), controllers.CreateAppealComment)
+		api.POST("/guilds/:discord_id/appeals/:appeal_id/decision", middlewares.Auth, perms.Cases.Edit(), controllers.DecideAppeal)
+		api.GET("/guilds/:discord_id/raids", middlewares.Auth, perms.Cases.Get(), controllers.GetRaidIncidents)
+		api.GET("/guilds/:discord_id/raids/timeline", middlewares.Auth, perms.Cases.Get(), controllers.GetRaidTimeline)
+		api.POST("/guilds/:discord_id/raids/:incident_id/resolve", middlewares.Auth, perms.Cases.Edit(), controllers.ResolveRaidIncident)
+		api.GET("/guilds/:discord_id/lockdown", middlewares.Auth, perms.GuildConfig.Get(), controllers.GetLockdown)
+		api.POST("/guilds/:discord_id/lockdown", middlewares.Auth, perms.GuildConfig.Lockdown(), controllers.LockdownGuild)
+		api.DELETE("/guilds/:discord_id/lockdown", middlewares.Auth, perms.GuildConfig.Lockdown(), controllers.LiftLockdown)
+
+		bot := api.Group("/bot", middlewares.APIKey)
+		{
+			bot.POST("/guilds/:discord_id/join", middlewares.Scope(apikey.ScopeGuildsPresenceWrite), controllers.JoinGuild)
+			bot.POST("/guilds/:discord_id/leave", middlewares.Scope(apikey.ScopeGuildsPresenceWrite), controllers.LeaveGuild)
+			bot.POST("/guilds/:discord_id/owner", middlewares.Scope(apikey.ScopeGuildsPresenceWrite), controllers.TransferGuildOwner)
+			bot.GET("/guilds/:discord_id/config", middlewares.Scope(apikey.ScopeConfigsRead), controllers.GetGuildConfig)
+			bot.POST("/guilds/:discord_id/cases", middlewares.Scope(apikey.ScopeCasesWrite), controllers.CreateCase)
+			bot.POST("/guilds/:discord_id/members/seen", middlewares.Scope(apikey.ScopeMembersWrite), controllers.ReportMembersSeen)
+			bot.POST("/guilds/:discord_id/members/events", middlewares.Scope(apikey.ScopeMembersWrite), controllers.ReportMemberEvents)
+			bot.GET("/guilds/:discord_id/members/:member_id/verification", middlewares.Scope(apikey.ScopeMembersRead), controllers.GetMemberVerification)
+			bot.POST("/guilds/:discord_id/infractions/escalate", middlewares.Scope(apikey.ScopeCasesWrite), controllers.EscalateInfraction)
+			bot.GET("/guilds/:discord_id/automod", middlewares.Scope(apikey.ScopeConfigsRead), controllers.GetAutomodRuleset)
+			bot.GET("/guilds/:discord_id/lockdown", middlewares.Scope(apikey.ScopeConfigsRead), controllers.GetLockdown)
+			bot.POST("/guilds/:discord_id/automod/evaluate", middlewares.Scope(apikey.ScopeAutomodEvaluate), controllers.EvaluateAutomod)
+			bot.GET("/commands", middlewares.Scope(apikey.ScopeCommandsRead), controllers.GetCommands)
+			bot.POST("/commands/:id/ack", middlewares.Scope(apikey.ScopeCommandsRead), controllers.AckCommand)
+		}
 	}
 
 	return &Server{router: router}
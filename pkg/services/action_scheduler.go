@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"time"
+)
+
+const (
+	// dispatchBatchSize limits amount of actions locked by a single replica at once
+	dispatchBatchSize = 100
+	// dispatchRetryDelay postpones actions which could not be delivered because no bot was listening
+	dispatchRetryDelay = time.Minute
+	// dispatchAckTimeout is how long the bot has to acknowledge a command before it is delivered again
+	dispatchAckTimeout = 2 * time.Minute
+)
+
+type ActionScheduler struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewActionScheduler(store db.Store, memStore memdb.Store) *ActionScheduler {
+	return &ActionScheduler{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+type ScheduledCommandData struct {
+	CaseID          int64     `json:"case_id"`
+	TargetDiscordID string    `json:"target_discord_id"`
+	RunAt           time.Time `json:"run_at"`
+}
+
+// Dispatch pushes actions due at the given time to the bot and returns amount of dispatched actions.
+// Claimed actions stay dispatched until the bot acknowledges them, unacknowledged actions are delivered
+// again after dispatchAckTimeout, bot commands are idempotent.
+func (s *ActionScheduler) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	actions, err := s.store.ClaimDueScheduledActions(ctx, db.ClaimDueScheduledActionsParams{
+		Now:         now,
+		AckDeadline: now.Add(-dispatchAckTimeout),
+		MaxResults:  dispatchBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	dispatched := 0
+	var publishErr error
+	for _, action := range actions {
+		data, _ := json.Marshal(ScheduledCommandData{
+			CaseID:          action.CaseID,
+			TargetDiscordID: action.TargetDiscordID,
+			RunAt:           action.RunAt,
+		})
+		receivers, err := s.memStore.PublishBotCommand(ctx, memdb.BotCommand{
+			ID:             strconv.FormatInt(action.ID, 10),
+			Type:           action.Action,
+			GuildDiscordID: action.GuildDiscordID,
+			Data:           data,
+			RequiresAck:    true,
+			CreatedAt:      now,
+		})
+		if err != nil || receivers == 0 {
+			postponeErr := s.store.PostponeScheduledAction(ctx, db.PostponeScheduledActionParams{
+				ID:    action.ID,
+				RunAt: now.Add(dispatchRetryDelay),
+			})
+			if postponeErr != nil {
+				return dispatched, postponeErr
+			}
+			if err != nil {
+				publishErr = err
+			}
+			continue
+		}
+		dispatched++
+	}
+	return dispatched, publishErr
+}
+
+// Run periodically dispatches due actions until ctx is done
+func (s *ActionScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		dispatched, err := s.Dispatch(ctx, time.Now())
+		if err != nil {
+			logrus.Warnf("Failed to dispatch scheduled actions: %v", err.Error())
+		} else if dispatched > 0 {
+			logrus.Infof("Dispatched %d scheduled actions", dispatched)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func generateRandomScheduledAction(runAt time.Time) db.ScheduledAction {
+	return db.ScheduledAction{
+		ID:              int64(utils.RandomInt(1, 1000)),
+		GuildDiscordID:  utils.RandomSnowflakeID().String(),
+		CaseID:          int64(utils.RandomInt(1, 1000)),
+		Action:          db.ScheduledActionUnmute,
+		TargetDiscordID: utils.RandomSnowflakeID().String(),
+		RunAt:           runAt,
+		Status:          db.ScheduledActionStatusDispatched,
+		CreatedAt:       runAt.Add(-time.Hour),
+	}
+}
+
+func TestActionScheduler_Dispatch(t *testing.T) {
+	now := time.Now()
+	action := generateRandomScheduledAction(now.Add(-time.Second))
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		check      func(t *testing.T, dispatched int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledActions(gomock.Any(), gomock.Eq(db.ClaimDueScheduledActionsParams{
+						Now:         now,
+						AckDeadline: now.Add(-dispatchAckTimeout),
+						MaxResults:  dispatchBatchSize,
+					})).
+					Times(1).
+					Return([]db.ScheduledAction{action}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, command memdb.BotCommand) (int64, error) {
+						require.Equal(t, strconv.FormatInt(action.ID, 10), command.ID)
+						require.Equal(t, action.Action, command.Type)
+						require.Equal(t, action.GuildDiscordID, command.GuildDiscordID)
+						require.True(t, command.RequiresAck)
+						return 1, nil
+					})
+				store.EXPECT().
+					PostponeScheduledAction(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, dispatched int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, dispatched)
+			},
+		},
+		{
+			name: "OK/NoReceivers",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledActions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ScheduledAction{action}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					PostponeScheduledAction(gomock.Any(), gomock.Eq(db.PostponeScheduledActionParams{
+						ID:    action.ID,
+						RunAt: now.Add(dispatchRetryDelay),
+					})).
+					Times(1).
+					Return(nil)
+			},
+			check: func(t *testing.T, dispatched int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, dispatched)
+			},
+		},
+		{
+			name: "Error/MemDBPublishBotCommand",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledActions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ScheduledAction{action, generateRandomScheduledAction(now)}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int64(0), redis.ErrClosed)
+				store.EXPECT().
+					PostponeScheduledAction(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil)
+			},
+			check: func(t *testing.T, dispatched int, err error) {
+				require.ErrorIs(t, err, redis.ErrClosed)
+				require.Zero(t, dispatched)
+			},
+		},
+		{
+			name: "Error/DBPostponeScheduledAction",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledActions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ScheduledAction{action}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					PostponeScheduledAction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			check: func(t *testing.T, dispatched int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, dispatched)
+			},
+		},
+		{
+			name: "Error/DBClaimDueScheduledActions",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					ClaimDueScheduledActions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, dispatched int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, dispatched)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			scheduler := NewActionScheduler(store, memStore)
+			dispatched, err := scheduler.Dispatch(context.Background(), now)
+			tc.check(t, dispatched, err)
+		})
+	}
+}
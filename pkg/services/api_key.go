@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/apikey"
+	"time"
+)
+
+type APIKeyService struct {
+	store db.Store
+}
+
+func NewAPIKeyService(store db.Store) *APIKeyService {
+	return &APIKeyService{store: store}
+}
+
+// Create generates new API key, zero duration means the key never expires and no guilds mean every guild
+func (s *APIKeyService) Create(ctx context.Context, name string, scopes []string, guildDiscordIDs []string, duration time.Duration) (db.ApiKey, string, error) {
+	if err := apikey.ValidateScopes(scopes); err != nil {
+		return db.ApiKey{}, "", err
+	}
+
+	key, err := apikey.Generate()
+	if err != nil {
+		return db.ApiKey{}, "", err
+	}
+
+	// nil array is stored as NULL
+	if guildDiscordIDs == nil {
+		guildDiscordIDs = []string{}
+	}
+
+	var expiresAt sql.NullTime
+	if duration > 0 {
+		expiresAt = sql.NullTime{Time: time.Now().Add(duration), Valid: true}
+	}
+
+	apiKey, err := s.store.CreateApiKey(ctx, db.CreateApiKeyParams{
+		Name:            name,
+		Prefix:          key.Prefix,
+		HashedKey:       key.Hash,
+		Scopes:          scopes,
+		GuildDiscordIDs: guildDiscordIDs,
+		ExpiresAt:       expiresAt,
+	})
+	return apiKey, key.Plain, err
+}
+
+// Rotate replaces secret of the key keeping its name, scopes, guilds and expiration
+func (s *APIKeyService) Rotate(ctx context.Context, prefix string) (db.ApiKey, string, error) {
+	key, err := apikey.Generate()
+	if err != nil {
+		return db.ApiKey{}, "", err
+	}
+
+	apiKey, err := s.store.RotateApiKey(ctx, db.RotateApiKeyParams{
+		Prefix:    prefix,
+		Prefix_2:  key.Prefix,
+		HashedKey: key.Hash,
+	})
+	return apiKey, key.Plain, err
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, prefix string) (db.ApiKey, error) {
+	return s.store.RevokeApiKey(ctx, prefix)
+}
+
+func (s *APIKeyService) List(ctx context.Context) ([]db.ApiKey, error) {
+	return s.store.GetApiKeys(ctx)
+}
@@ -3,33 +3,100 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
 	"github.com/ravener/discord-oauth2"
 	"golang.org/x/oauth2"
 	"net/url"
+	"strconv"
 )
 
 type DiscordOauth2Service struct {
-	config *oauth2.Config
+	config               *oauth2.Config
+	inviteBotRedirectURL string
 }
 
-func NewDiscordOauth2Service(config *oauth2.Config) *DiscordOauth2Service {
-	return &DiscordOauth2Service{config: config}
+func NewDiscordOauth2Service(config *oauth2.Config, inviteBotRedirectURL string) *DiscordOauth2Service {
+	return &DiscordOauth2Service{
+		config:               config,
+		inviteBotRedirectURL: inviteBotRedirectURL,
+	}
 }
 
-func (s *DiscordOauth2Service) NewURL(state string) string {
-	return s.config.AuthCodeURL(state)
+// NewURL creates authorization URL protected with PKCE using S256 challenge method
+func (s *DiscordOauth2Service) NewURL(state string, codeVerifier string, redirectURL string) string {
+	return s.config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("redirect_uri", redirectURL),
+		oauth2.SetAuthURLParam("code_challenge", utils.CodeChallengeS256(codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
 }
 
-func (s *DiscordOauth2Service) NewInviteBotURL() string {
+// NewInviteBotURL creates bot authorization URL, guild selection is disabled if guildID is provided
+func (s *DiscordOauth2Service) NewInviteBotURL(state string, guildID string, permissions int64) string {
 	v := url.Values{}
 	v.Set("client_id", s.config.ClientID)
-	v.Set("permissions", "8") // administrator
+	v.Set("permissions", strconv.FormatInt(permissions, 10))
 	v.Set("scope", discord.ScopeBot)
+	v.Set("response_type", "code")
+	v.Set("redirect_uri", s.inviteBotRedirectURL)
+	v.Set("state", state)
+	if guildID != "" {
+		v.Set("guild_id", guildID)
+		v.Set("disable_guild_select", "true")
+	}
 	return s.config.Endpoint.AuthURL + "?" + v.Encode()
 }
 
-func (s *DiscordOauth2Service) Exchange(code string) (*oauth2.Token, error) {
-	return s.config.Exchange(context.Background(), code)
+// Exchange requires the same redirect URL which was used to create authorization URL
+func (s *DiscordOauth2Service) Exchange(code string, codeVerifier string, redirectURL string) (*oauth2.Token, error) {
+	return s.config.Exchange(context.Background(), code,
+		oauth2.SetAuthURLParam("redirect_uri", redirectURL),
+		oauth2.SetAuthURLParam("code_verifier", codeVerifier),
+	)
+}
+
+// DiscordBotGuild is the guild the bot was authorized in, as reported by the token response
+type DiscordBotGuild struct {
+	ID string
+	// Permissions granted to the bot, zero if Discord did not report them
+	Permissions int64
+}
+
+// ExchangeInviteBot exchanges the code and returns the guild from the token response, query parameters
+// of the callback are not trusted since the user may alter them
+func (s *DiscordOauth2Service) ExchangeInviteBot(code string) (DiscordBotGuild, error) {
+	token, err := s.config.Exchange(context.Background(), code, oauth2.SetAuthURLParam("redirect_uri", s.inviteBotRedirectURL))
+	if err != nil {
+		return DiscordBotGuild{}, err
+	}
+	return ParseBotGuild(token)
+}
+
+// ParseBotGuild reads guild extra of the bot authorization token response
+func ParseBotGuild(token *oauth2.Token) (DiscordBotGuild, error) {
+	guild, ok := token.Extra("guild").(map[string]interface{})
+	if !ok {
+		return DiscordBotGuild{}, errors.New("token response has no guild")
+	}
+	id, ok := guild["id"].(string)
+	if !ok || id == "" {
+		return DiscordBotGuild{}, errors.New("token response has no guild id")
+	}
+
+	res := DiscordBotGuild{ID: id}
+	// Discord serializes permissions as strings, since they do not fit into JSON numbers
+	switch permissions := guild["permissions"].(type) {
+	case string:
+		parsed, err := strconv.ParseInt(permissions, 10, 64)
+		if err != nil {
+			return DiscordBotGuild{}, err
+		}
+		res.Permissions = parsed
+	case float64:
+		res.Permissions = int64(permissions)
+	}
+	return res, nil
 }
 
 type DiscordUser struct {
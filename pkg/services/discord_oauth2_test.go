@@ -0,0 +1,70 @@
+package services
+
+import (
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"testing"
+)
+
+func TestParseBotGuild(t *testing.T) {
+	testCases := []struct {
+		name   string
+		extra  map[string]interface{}
+		guild  DiscordBotGuild
+		hasErr bool
+	}{
+		{
+			name: "StringPermissions",
+			extra: map[string]interface{}{
+				"guild": map[string]interface{}{"id": "1234", "permissions": "268435456"},
+			},
+			guild: DiscordBotGuild{ID: "1234", Permissions: 268435456},
+		},
+		{
+			name: "NumberPermissions",
+			extra: map[string]interface{}{
+				"guild": map[string]interface{}{"id": "1234", "permissions": float64(8)},
+			},
+			guild: DiscordBotGuild{ID: "1234", Permissions: 8},
+		},
+		{
+			name: "NoPermissions",
+			extra: map[string]interface{}{
+				"guild": map[string]interface{}{"id": "1234"},
+			},
+			guild: DiscordBotGuild{ID: "1234"},
+		},
+		{
+			name:   "NoGuild",
+			extra:  map[string]interface{}{},
+			hasErr: true,
+		},
+		{
+			name: "NoGuildID",
+			extra: map[string]interface{}{
+				"guild": map[string]interface{}{"name": "guild"},
+			},
+			hasErr: true,
+		},
+		{
+			name: "InvalidPermissions",
+			extra: map[string]interface{}{
+				"guild": map[string]interface{}{"id": "1234", "permissions": "all"},
+			},
+			hasErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token := (&oauth2.Token{AccessToken: "token"}).WithExtra(tc.extra)
+			guild, err := ParseBotGuild(token)
+			if tc.hasErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.guild, guild)
+		})
+	}
+}
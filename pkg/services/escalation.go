@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/escalation"
+	"github.com/BoggerByte/Sentinel-backend.git/pub/objects"
+	"time"
+)
+
+type EscalationService struct {
+	store db.Store
+}
+
+func NewEscalationService(store db.Store) *EscalationService {
+	return &EscalationService{store: store}
+}
+
+type MemberStanding struct {
+	// Standing is sum of points of past cases which have not decayed
+	Standing int `json:"standing"`
+	// Points are added by the infraction being issued
+	Points int `json:"points"`
+	// ProjectedStanding is standing once the infraction is recorded
+	ProjectedStanding int                 `json:"projected_standing"`
+	Decision          escalation.Decision `json:"decision"`
+}
+
+// Policy returns escalation policy from config of the guild
+func (s *EscalationService) Policy(ctx context.Context, guildDiscordID string) (objects.EscalationConfig, error) {
+	guildConfig, err := s.store.GetGuildConfig(ctx, guildDiscordID)
+	if err != nil {
+		return objects.EscalationConfig{}, err
+	}
+
+	guildConfigObj, err := objects.DecodeGuildConfig(guildConfig.Json)
+	if err != nil {
+		return objects.EscalationConfig{}, err
+	}
+	return guildConfigObj.Data.Escalation, nil
+}
+
+// Standing computes current standing of the target from moderation cases of the guild
+func (s *EscalationService) Standing(ctx context.Context, policy objects.EscalationConfig, guildDiscordID, targetDiscordID string, now time.Time) (int, error) {
+	cases, err := s.store.GetTargetModerationCases(ctx, db.GetTargetModerationCasesParams{
+		GuildDiscordID:  guildDiscordID,
+		TargetDiscordID: targetDiscordID,
+		CreatedAt:       escalation.Horizon(policy, now),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	infractions := make([]escalation.Infraction, 0, len(cases))
+	for _, moderationCase := range cases {
+		infractions = append(infractions, escalation.Infraction{
+			Action:    moderationCase.Action,
+			CreatedAt: moderationCase.CreatedAt,
+		})
+	}
+	return escalation.Standing(policy, infractions, now), nil
+}
+
+// Escalate decides which action the bot should take for an infraction under policy of the guild.
+// The infraction itself is not recorded, the bot records the decided action as a case.
+func (s *EscalationService) Escalate(ctx context.Context, guildDiscordID, targetDiscordID, action string, durationSeconds int64, now time.Time) (MemberStanding, error) {
+	policy, err := s.Policy(ctx, guildDiscordID)
+	if err != nil {
+		return MemberStanding{}, err
+	}
+	if !policy.Enabled {
+		return MemberStanding{
+			Decision: escalation.Escalate(policy, 0, action, durationSeconds),
+		}, nil
+	}
+
+	standing, err := s.Standing(ctx, policy, guildDiscordID, targetDiscordID, now)
+	if err != nil {
+		return MemberStanding{}, err
+	}
+
+	points := escalation.Points(policy, action)
+	projected := standing + points
+	return MemberStanding{
+		Standing:          standing,
+		Points:            points,
+		ProjectedStanding: projected,
+		Decision:          escalation.Escalate(policy, projected, action, durationSeconds),
+	}, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type GuildArchiveService struct {
+	store db.Store
+}
+
+func NewGuildArchiveService(store db.Store) *GuildArchiveService {
+	return &GuildArchiveService{store: store}
+}
+
+type GuildSweepResult struct {
+	Flagged  int64
+	Archived int64
+	Deleted  int64
+}
+
+// Sweep flags guilds without owner and user relations, archives guilds flagged before orphanedBefore
+// and deletes guilds archived before archivedBefore. Flags are cleared when a member logs in or the bot joins.
+func (s *GuildArchiveService) Sweep(ctx context.Context, orphanedBefore, archivedBefore time.Time) (GuildSweepResult, error) {
+	var result GuildSweepResult
+	err := s.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		result.Flagged, err = q.FlagOrphanedGuilds(ctx)
+		if err != nil {
+			return err
+		}
+
+		result.Archived, err = q.ArchiveOrphanedGuilds(ctx, sql.NullTime{Time: orphanedBefore, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		result.Deleted, err = q.DeleteArchivedGuilds(ctx, sql.NullTime{Time: archivedBefore, Valid: true})
+		return err
+	})
+	return result, err
+}
+
+// RunSweep periodically sweeps orphaned guilds until ctx is done
+func (s *GuildArchiveService) RunSweep(ctx context.Context, interval, archiveAfter, deleteAfter time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		result, err := s.Sweep(ctx, now.Add(-archiveAfter), now.Add(-deleteAfter))
+		if err != nil {
+			logrus.Warnf("Failed to sweep orphaned guilds: %v", err.Error())
+		} else if result != (GuildSweepResult{}) {
+			logrus.Infof("Orphaned guilds: %d flagged, %d archived, %d deleted", result.Flagged, result.Archived, result.Deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
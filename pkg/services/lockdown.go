@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// liftBatchSize limits amount of expired lockdowns lifted by a single replica at once
+const liftBatchSize = 100
+
+type LockdownService struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewLockdownService(store db.Store, memStore memdb.Store) *LockdownService {
+	return &LockdownService{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+// Lockdown describes the lockdown to the bot and to the events stream
+type Lockdown struct {
+	GuildDiscordID    string   `json:"guild_discord_id"`
+	Scope             string   `json:"scope"`
+	ChannelDiscordIDs []string `json:"channel_discord_ids"`
+	Reason            string   `json:"reason"`
+	// ActorDiscordID is null when the lockdown was raised by raid detection or the actor deleted the account
+	ActorDiscordID *string    `json:"actor_discord_id"`
+	IncidentID     *int64     `json:"incident_id"`
+	LiftsAt        *time.Time `json:"lifts_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func NewLockdown(lockdown db.GuildLockdown) Lockdown {
+	res := Lockdown{
+		GuildDiscordID:    lockdown.GuildDiscordID,
+		Scope:             lockdown.Scope,
+		ChannelDiscordIDs: lockdown.ChannelDiscordIDs,
+		Reason:            lockdown.Reason,
+		CreatedAt:         lockdown.CreatedAt,
+	}
+	if res.ChannelDiscordIDs == nil {
+		res.ChannelDiscordIDs = []string{}
+	}
+	if lockdown.ActorDiscordID.Valid {
+		res.ActorDiscordID = &lockdown.ActorDiscordID.String
+	}
+	if lockdown.IncidentID.Valid {
+		res.IncidentID = &lockdown.IncidentID.Int64
+	}
+	if lockdown.LiftsAt.Valid {
+		res.LiftsAt = &lockdown.LiftsAt.Time
+	}
+	return res
+}
+
+// Publish tells the bot to lock the guild and shows the lockdown in the events stream
+func (s *LockdownService) Publish(ctx context.Context, lockdown db.GuildLockdown) {
+	s.publish(ctx, memdb.BotCommandLockdown, memdb.GuildEventLockdown, lockdown)
+}
+
+// PublishLift tells the bot to unlock the guild. Commands are not retried when no bot listens,
+// the bot restores the state from the lockdown endpoint once it reconnects.
+func (s *LockdownService) PublishLift(ctx context.Context, lockdown db.GuildLockdown) {
+	s.publish(ctx, memdb.BotCommandLiftLockdown, memdb.GuildEventLockdownLifted, lockdown)
+}
+
+// LiftExpired lifts lockdowns whose auto-lift time has come and returns amount of lifted lockdowns
+func (s *LockdownService) LiftExpired(ctx context.Context, now time.Time) (int, error) {
+	lifted, err := s.store.DeleteExpiredGuildLockdowns(ctx, db.DeleteExpiredGuildLockdownsParams{
+		Now:        now,
+		MaxResults: liftBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, lockdown := range lifted {
+		s.PublishLift(ctx, lockdown)
+	}
+	return len(lifted), nil
+}
+
+// RunLift periodically lifts expired lockdowns until ctx is done
+func (s *LockdownService) RunLift(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		lifted, err := s.LiftExpired(ctx, time.Now())
+		if err != nil {
+			logrus.Warnf("Failed to lift expired lockdowns: %v", err.Error())
+		} else if lifted > 0 {
+			logrus.Infof("Lifted %d expired lockdowns", lifted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish shows the lockdown only to subscribers who can read cases, since it carries the reason, actor and incident
+func (s *LockdownService) publish(ctx context.Context, commandType, eventType string, lockdown db.GuildLockdown) {
+	data, _ := json.Marshal(NewLockdown(lockdown))
+	receivers, err := s.memStore.PublishBotCommand(ctx, memdb.BotCommand{
+		ID:             fmt.Sprintf("%s_%s_%d", commandType, lockdown.GuildDiscordID, lockdown.CreatedAt.UnixMilli()),
+		Type:           commandType,
+		GuildDiscordID: lockdown.GuildDiscordID,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish lockdown command: %v", err.Error())
+	} else if receivers == 0 {
+		logrus.Warnf("No bot received lockdown command of guild %s", lockdown.GuildDiscordID)
+	}
+
+	err = s.memStore.PublishGuildEvent(ctx, memdb.GuildEvent{
+		Type:           eventType,
+		GuildDiscordID: lockdown.GuildDiscordID,
+		Capability:     token.CapabilityCasesRead,
+		Data:           data,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("Failed to publish lockdown event: %v", err.Error())
+	}
+}
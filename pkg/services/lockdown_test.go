@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	mockmemdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory_mock"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/token"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/utils"
+	"github.com/go-redis/redis/v9"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func generateRandomLockdown(liftsAt time.Time) db.GuildLockdown {
+	return db.GuildLockdown{
+		GuildDiscordID:    utils.RandomSnowflakeID().String(),
+		Scope:             db.LockdownScopeAll,
+		ChannelDiscordIDs: []string{},
+		Reason:            utils.RandomString(20),
+		ActorDiscordID:    sql.NullString{String: utils.RandomSnowflakeID().String(), Valid: true},
+		LiftsAt:           sql.NullTime{Time: liftsAt, Valid: true},
+		CreatedAt:         liftsAt.Add(-time.Hour),
+	}
+}
+
+func TestLockdownService_LiftExpired(t *testing.T) {
+	now := time.Now()
+	lockdown := generateRandomLockdown(now.Add(-time.Second))
+	otherLockdown := generateRandomLockdown(now)
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore, memStore *mockmemdb.MockStore)
+		check      func(t *testing.T, lifted int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					DeleteExpiredGuildLockdowns(gomock.Any(), gomock.Eq(db.DeleteExpiredGuildLockdownsParams{
+						Now:        now,
+						MaxResults: liftBatchSize,
+					})).
+					Times(1).
+					Return([]db.GuildLockdown{lockdown, otherLockdown}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(_ context.Context, command memdb.BotCommand) (int64, error) {
+						require.Equal(t, memdb.BotCommandLiftLockdown, command.Type)
+						var data Lockdown
+						require.NoError(t, json.Unmarshal(command.Data, &data))
+						require.Equal(t, command.GuildDiscordID, data.GuildDiscordID)
+						return 1, nil
+					})
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(_ context.Context, event memdb.GuildEvent) error {
+						require.Equal(t, memdb.GuildEventLockdownLifted, event.Type)
+						require.Equal(t, token.CapabilityCasesRead, event.Capability)
+						return nil
+					})
+			},
+			check: func(t *testing.T, lifted int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, lifted)
+			},
+		},
+		{
+			name: "OK/NoReceivers",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					DeleteExpiredGuildLockdowns(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.GuildLockdown{lockdown}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			check: func(t *testing.T, lifted int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, lifted)
+			},
+		},
+		{
+			name: "OK/MemDBPublishError",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					DeleteExpiredGuildLockdowns(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.GuildLockdown{lockdown}, nil)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), redis.ErrClosed)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(redis.ErrClosed)
+			},
+			check: func(t *testing.T, lifted int, err error) {
+				// lockdowns are lifted in the DB already, the bot restores the state once it reconnects
+				require.NoError(t, err)
+				require.Equal(t, 1, lifted)
+			},
+		},
+		{
+			name: "Error/DBDeleteExpiredGuildLockdowns",
+			buildStubs: func(store *mockdb.MockStore, memStore *mockmemdb.MockStore) {
+				store.EXPECT().
+					DeleteExpiredGuildLockdowns(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				memStore.EXPECT().
+					PublishBotCommand(gomock.Any(), gomock.Any()).
+					Times(0)
+				memStore.EXPECT().
+					PublishGuildEvent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, lifted int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, lifted)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			memStore := mockmemdb.NewMockStore(ctrl)
+			tc.buildStubs(store, memStore)
+
+			lockdownService := NewLockdownService(store, memStore)
+			lifted, err := lockdownService.LiftExpired(context.Background(), now)
+			tc.check(t, lifted, err)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// MemberEventRetention is how long joins and leaves are kept, raid timelines never reach further back
+const MemberEventRetention = 7 * 24 * time.Hour
+
+type MemberEventService struct {
+	store db.Store
+}
+
+func NewMemberEventService(store db.Store) *MemberEventService {
+	return &MemberEventService{store: store}
+}
+
+// PurgeExpired deletes member events older than the retention and returns amount of deleted events
+func (s *MemberEventService) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	return s.store.DeleteMemberEventsBefore(ctx, now.Add(-MemberEventRetention))
+}
+
+// RunPurge periodically purges expired member events until ctx is done
+func (s *MemberEventService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(ctx, time.Now())
+		if err != nil {
+			logrus.Warnf("Failed to purge expired member events: %v", err.Error())
+		} else if purged > 0 {
+			logrus.Infof("Purged %d expired member events", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	mockdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMemberEventService_PurgeExpired(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, purged int64, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteMemberEventsBefore(gomock.Any(), gomock.Eq(now.Add(-MemberEventRetention))).
+					Times(1).
+					Return(int64(3), nil)
+			},
+			check: func(t *testing.T, purged int64, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(3), purged)
+			},
+		},
+		{
+			name: "Error/DBDeleteMemberEventsBefore",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteMemberEventsBefore(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), sql.ErrConnDone)
+			},
+			check: func(t *testing.T, purged int64, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, purged)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			memberEventService := NewMemberEventService(store)
+			purged, err := memberEventService.PurgeExpired(context.Background(), now)
+			tc.check(t, purged, err)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	memdb "github.com/BoggerByte/Sentinel-backend.git/pkg/db/memory"
+	db "github.com/BoggerByte/Sentinel-backend.git/pkg/db/sqlc"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// purgeBatchSize limits amount of users deleted by a single purge query
+const purgeBatchSize = 100
+
+type UserDataService struct {
+	store    db.Store
+	memStore memdb.Store
+}
+
+func NewUserDataService(store db.Store, memStore memdb.Store) *UserDataService {
+	return &UserDataService{
+		store:    store,
+		memStore: memStore,
+	}
+}
+
+type UserSessionExport struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIp  string    `json:"client_ip"`
+	IsBlocked bool      `json:"is_blocked"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserDataExport struct {
+	User     db.User               `json:"user"`
+	Guilds   []db.GetUserGuildsRow `json:"guilds"`
+	Sessions []UserSessionExport   `json:"sessions"`
+	AuditLog []db.AuditLog         `json:"audit_log"`
+	Appeals  []db.Appeal           `json:"appeals"`
+	// AppealComments and MemberNotes are the ones authored by the user as a moderator
+	AppealComments []db.AppealComment `json:"appeal_comments"`
+	MemberNotes    []db.MemberNote    `json:"member_notes"`
+	// MemberEvents are joins and leaves of the user reported by the bot for raid detection
+	MemberEvents []db.MemberEvent `json:"member_events"`
+	// VerifiedMembers are guilds where the user passed verification
+	VerifiedMembers []db.VerifiedMember `json:"verified_members"`
+}
+
+// Export collects everything stored about the user, refresh tokens are never exported
+func (s *UserDataService) Export(ctx context.Context, discordID string) (UserDataExport, error) {
+	user, err := s.store.GetUser(ctx, discordID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	guilds, err := s.store.GetUserGuilds(ctx, discordID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	sessions, err := s.memStore.GetUserSessions(ctx, discordID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	author := sql.NullString{String: discordID, Valid: true}
+	auditLog, err := s.store.GetUserAuditLogs(ctx, author)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	appeals, err := s.store.GetUserAppeals(ctx, discordID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	comments, err := s.store.GetUserAppealComments(ctx, author)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	notes, err := s.store.GetAuthoredMemberNotes(ctx, author)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	memberEvents, err := s.store.GetUserMemberEvents(ctx, discordID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	verifiedMembers, err := s.store.GetUserVerifiedMembers(ctx, discordID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	export := UserDataExport{
+		User:            user,
+		Guilds:          make([]db.GetUserGuildsRow, 0, len(guilds)),
+		Sessions:        make([]UserSessionExport, 0, len(sessions)),
+		AuditLog:        make([]db.AuditLog, 0, len(auditLog)),
+		Appeals:         make([]db.Appeal, 0, len(appeals)),
+		AppealComments:  make([]db.AppealComment, 0, len(comments)),
+		MemberNotes:     make([]db.MemberNote, 0, len(notes)),
+		MemberEvents:    make([]db.MemberEvent, 0, len(memberEvents)),
+		VerifiedMembers: make([]db.VerifiedMember, 0, len(verifiedMembers)),
+	}
+	export.Guilds = append(export.Guilds, guilds...)
+	export.AuditLog = append(export.AuditLog, auditLog...)
+	export.Appeals = append(export.Appeals, appeals...)
+	export.AppealComments = append(export.AppealComments, comments...)
+	export.MemberNotes = append(export.MemberNotes, notes...)
+	export.MemberEvents = append(export.MemberEvents, memberEvents...)
+	export.VerifiedMembers = append(export.VerifiedMembers, verifiedMembers...)
+	for _, session := range sessions {
+		export.Sessions = append(export.Sessions, UserSessionExport{
+			ID:        session.ID.String(),
+			UserAgent: session.UserAgent,
+			ClientIp:  session.ClientIp,
+			IsBlocked: session.IsBlocked,
+			CreatedAt: session.CreatedAt,
+		})
+	}
+	return export, nil
+}
+
+// Delete removes the user with guild relations, appeals, member events and verifications in one transaction.
+// Audit records, appeal decisions, comments, member notes, raid incident resolutions and lockdowns of the user are anonymised.
+// Sessions are revoked last, so a failed revocation rolls the deletion back and can be retried.
+func (s *UserDataService) Delete(ctx context.Context, discordID string) error {
+	return s.store.ExecTx(ctx, func(q *db.Queries) error {
+		author := sql.NullString{String: discordID, Valid: true}
+		if err := q.AnonymizeAuditLogs(ctx, author); err != nil {
+			return err
+		}
+		if err := q.AnonymizeAppealReviewers(ctx, author); err != nil {
+			return err
+		}
+		if err := q.AnonymizeAppealComments(ctx, author); err != nil {
+			return err
+		}
+		if err := q.AnonymizeMemberNotes(ctx, author); err != nil {
+			return err
+		}
+		if err := q.AnonymizeMemberNoteRevisions(ctx, author); err != nil {
+			return err
+		}
+		if err := q.AnonymizeRaidIncidentResolvers(ctx, author); err != nil {
+			return err
+		}
+		if err := q.AnonymizeGuildLockdownActors(ctx, author); err != nil {
+			return err
+		}
+
+		if err := q.DeleteUserAppeals(ctx, discordID); err != nil {
+			return err
+		}
+		if err := q.DeleteUserMemberEvents(ctx, discordID); err != nil {
+			return err
+		}
+		if err := q.DeleteUserVerifiedMembers(ctx, discordID); err != nil {
+			return err
+		}
+
+		if err := q.DeleteUserGuildRels(ctx, discordID); err != nil {
+			return err
+		}
+
+		deleted, err := q.DeleteUser(ctx, discordID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+
+		return s.memStore.DeleteUserSessions(ctx, discordID)
+	})
+}
+
+// PurgeInactive deletes users who have not logged in since the given time and returns amount of deleted users
+func (s *UserDataService) PurgeInactive(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for {
+		discordIDs, err := s.store.GetInactiveUsers(ctx, db.GetInactiveUsersParams{
+			LastLoginAt: before,
+			Limit:       purgeBatchSize,
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		for _, discordID := range discordIDs {
+			err := s.Delete(ctx, discordID)
+			if errors.Is(err, sql.ErrNoRows) {
+				continue // deleted concurrently
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+
+		if len(discordIDs) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// RunPurge periodically purges users inactive for longer than the given duration until ctx is done
+func (s *UserDataService) RunPurge(ctx context.Context, interval, inactiveFor time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeInactive(ctx, time.Now().Add(-inactiveFor))
+		if err != nil {
+			logrus.Warnf("Failed to purge inactive users: %v", err.Error())
+		} else if purged > 0 {
+			logrus.Infof("Purged %d inactive users", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,28 +2,45 @@ package utils
 
 import (
 	"github.com/spf13/viper"
+	"net/url"
 	"time"
 )
 
 type Config struct {
-	ServerHTTPAddress       string        `mapstructure:"SERVER_HTTP_ADDRESS"`
-	DBDriver                string        `mapstructure:"DB_DRIVER"`
-	DBProtocol              string        `mapstructure:"DB_PROTOCOL"`
-	DBHost                  string        `mapstructure:"DB_HOST"`
-	DBPort                  string        `mapstructure:"DB_PORT"`
-	DBUsername              string        `mapstructure:"DB_USERNAME"`
-	DBPassword              string        `mapstructure:"DB_PASSWORD"`
-	DBName                  string        `mapstructure:"DB_NAME"`
-	DBSSLMode               string        `mapstructure:"DB_SSL_MODE"`
-	RedisHost               string        `mapstructure:"REDIS_HOST"`
-	RedisPort               string        `mapstructure:"REDIS_PORT"`
-	RedisPassword           string        `mapstructure:"REDIS_PASSWORD"`
-	PasetoSymmetricKey      string        `mapstructure:"PASETO_SYMMETRIC_KEY"`
-	AccessTokenDuration     time.Duration `mapstructure:"TOKEN_ACCESS_DURATION"`
-	RefreshTokenDuration    time.Duration `mapstructure:"TOKEN_REFRESH_DURATION"`
-	Oauth2FlowStateDuration time.Duration `mapstructure:"OAUTH2_FLOW_STATE_DURATION"`
-	DiscordClientID         string        `mapstructure:"DISCORD_CLIENT_ID"`
-	DiscordClientSecret     string        `mapstructure:"DISCORD_CLIENT_SECRET"`
+	ServerHTTPAddress           string        `mapstructure:"SERVER_HTTP_ADDRESS"`
+	DBDriver                    string        `mapstructure:"DB_DRIVER"`
+	DBProtocol                  string        `mapstructure:"DB_PROTOCOL"`
+	DBHost                      string        `mapstructure:"DB_HOST"`
+	DBPort                      string        `mapstructure:"DB_PORT"`
+	DBUsername                  string        `mapstructure:"DB_USERNAME"`
+	DBPassword                  string        `mapstructure:"DB_PASSWORD"`
+	DBName                      string        `mapstructure:"DB_NAME"`
+	DBSSLMode                   string        `mapstructure:"DB_SSL_MODE"`
+	RedisHost                   string        `mapstructure:"REDIS_HOST"`
+	RedisPort                   string        `mapstructure:"REDIS_PORT"`
+	RedisPassword               string        `mapstructure:"REDIS_PASSWORD"`
+	TokenMaker                  string        `mapstructure:"TOKEN_MAKER"`
+	PasetoSymmetricKey          string        `mapstructure:"PASETO_SYMMETRIC_KEY"`
+	PasetoV4SecretKey           string        `mapstructure:"PASETO_V4_SECRET_KEY"`
+	PasetoV4PublicKeys          []string      `mapstructure:"PASETO_V4_PUBLIC_KEYS"`
+	JWTSymmetricKey             string        `mapstructure:"JWT_SYMMETRIC_KEY"`
+	JWTEdDSASecretKey           string        `mapstructure:"JWT_EDDSA_SECRET_KEY"`
+	JWTEdDSAPublicKeys          []string      `mapstructure:"JWT_EDDSA_PUBLIC_KEYS"`
+	AccessTokenDuration         time.Duration `mapstructure:"TOKEN_ACCESS_DURATION"`
+	RefreshTokenDuration        time.Duration `mapstructure:"TOKEN_REFRESH_DURATION"`
+	GuildTokenDuration          time.Duration `mapstructure:"TOKEN_GUILD_DURATION"`
+	AuthCookieMode              bool          `mapstructure:"AUTH_COOKIE_MODE"`
+	Oauth2FlowStateDuration     time.Duration `mapstructure:"OAUTH2_FLOW_STATE_DURATION"`
+	DiscordClientID             string        `mapstructure:"DISCORD_CLIENT_ID"`
+	DiscordClientSecret         string        `mapstructure:"DISCORD_CLIENT_SECRET"`
+	DiscordRedirectURLs         []string      `mapstructure:"DISCORD_REDIRECT_URLS"`
+	DiscordInviteBotRedirectURL string        `mapstructure:"DISCORD_INVITE_BOT_REDIRECT_URL"`
+	UserPurgeAfter              time.Duration `mapstructure:"USER_PURGE_AFTER"`
+	UserPurgeInterval           time.Duration `mapstructure:"USER_PURGE_INTERVAL"`
+	GuildArchiveAfter           time.Duration `mapstructure:"GUILD_ARCHIVE_AFTER"`
+	GuildDeleteAfter            time.Duration `mapstructure:"GUILD_DELETE_AFTER"`
+	GuildSweepInterval          time.Duration `mapstructure:"GUILD_SWEEP_INTERVAL"`
+	SchedulerInterval           time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
 }
 
 func LoadConfig() (Config, error) {
@@ -46,3 +63,38 @@ func LoadConfig() (Config, error) {
 	err := viper.Unmarshal(&config)
 	return config, err
 }
+
+// IsAllowedRedirectURL reports whether Discord may redirect users to the URL after authorization
+func (c Config) IsAllowedRedirectURL(redirectURL string) bool {
+	for _, u := range c.DiscordRedirectURLs {
+		if u == redirectURL {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedOrigins returns origins of frontends which redirect URLs are pointing to
+func (c Config) AllowedOrigins() []string {
+	var origins []string
+	seen := make(map[string]bool)
+	redirectURLs := append([]string{}, c.DiscordRedirectURLs...)
+	for _, redirectURL := range append(redirectURLs, c.DiscordInviteBotRedirectURL) {
+		origin := URLOrigin(redirectURL)
+		if origin == "" || seen[origin] {
+			continue
+		}
+		seen[origin] = true
+		origins = append(origins, origin)
+	}
+	return origins
+}
+
+// URLOrigin returns scheme and host of the URL, or empty string if it is not absolute
+func URLOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestConfig_AllowedOrigins(t *testing.T) {
+	config := Config{
+		DiscordRedirectURLs: []string{
+			"https://sentinel.example/oauth2/discord_callback",
+			"https://sentinel.example/oauth2/other_callback",
+			"https://staging.sentinel.example:8443/oauth2/discord_callback",
+			"not an url",
+		},
+		DiscordInviteBotRedirectURL: "http://localhost:5173/oauth2/invite_bot_callback",
+	}
+
+	require.Equal(t, []string{
+		"https://sentinel.example",
+		"https://staging.sentinel.example:8443",
+		"http://localhost:5173",
+	}, config.AllowedOrigins())
+	require.True(t, config.IsAllowedRedirectURL("https://sentinel.example/oauth2/other_callback"))
+	require.False(t, config.IsAllowedRedirectURL("https://sentinel.example/oauth2/discord_callback?next=evil"))
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	testCases := []struct {
+		name   string
+		doc    string
+		patch  string
+		result string
+	}{
+		{
+			name:   "ReplaceNested",
+			doc:    `{"permissions":{"read":0,"edit":40},"preset":"default"}`,
+			patch:  `{"permissions":{"edit":32}}`,
+			result: `{"permissions":{"edit":32,"read":0},"preset":"default"}`,
+		},
+		{
+			name:   "RemoveKey",
+			doc:    `{"a":1,"b":2}`,
+			patch:  `{"a":null}`,
+			result: `{"b":2}`,
+		},
+		{
+			name:   "ReplaceArray",
+			doc:    `{"a":[1,2]}`,
+			patch:  `{"a":[3]}`,
+			result: `{"a":[3]}`,
+		},
+		{
+			name:   "ReplaceScalarWithObject",
+			doc:    `{"a":1}`,
+			patch:  `{"a":{"b":null,"c":1}}`,
+			result: `{"a":{"c":1}}`,
+		},
+		{
+			name:   "KeepLargeIntegers",
+			doc:    `{"read":17592186044415}`,
+			patch:  `{}`,
+			result: `{"read":17592186044415}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := MergePatch([]byte(tc.doc), []byte(tc.patch))
+			require.NoError(t, err)
+			require.JSONEq(t, tc.result, string(result))
+		})
+	}
+
+	_, err := MergePatch([]byte(`{}`), []byte(`not json`))
+	require.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// NewCodeVerifier creates PKCE code verifier as described in RFC 7636
+func NewCodeVerifier() string {
+	return RandomString(64)
+}
+
+// CodeChallengeS256 derives PKCE code challenge from the verifier using S256 method
+func CodeChallengeS256(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// example of RFC 7636 appendix B
+func TestCodeChallengeS256(t *testing.T) {
+	codeVerifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallengeS256(codeVerifier))
+}
+
+func TestNewCodeVerifier(t *testing.T) {
+	codeVerifier := NewCodeVerifier()
+	require.GreaterOrEqual(t, len(codeVerifier), 43)
+	require.LessOrEqual(t, len(codeVerifier), 128)
+	require.NotEqual(t, codeVerifier, NewCodeVerifier())
+}
@@ -1,23 +1,30 @@
 package utils
 
 import (
+	"crypto/rand"
 	"github.com/bwmarrin/snowflake"
-	"math/rand"
-	"time"
+	"math/big"
 )
 
+// RandomInt returns uniformly distributed integer in [min, max] read from crypto/rand
 func RandomInt(min int, max int) int {
-	rand.Seed(time.Now().UnixNano())
-	return rand.Intn(max-min+1) + min
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min+1)))
+	if err != nil {
+		panic(err)
+	}
+	return int(n.Int64()) + min
 }
 
+var snowflakeNode, _ = snowflake.NewNode(1)
+
+// RandomSnowflakeID generates unique IDs, node is shared so IDs of the same millisecond differ
 func RandomSnowflakeID() snowflake.ID {
-	node, _ := snowflake.NewNode(1)
-	return node.Generate()
+	return snowflakeNode.Generate()
 }
 
 const charset = "abcdefghijklmnopqrstuvwxyz" + "ABCDEFGHIJKLMNOPQRSTUVWXYZ" + "0123456789"
 
+// RandomString is safe to use for secrets like oauth2 states
 func RandomString(length int) string {
 	b := make([]byte, length)
 	for i := range b {
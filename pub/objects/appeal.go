@@ -0,0 +1,17 @@
+package objects
+
+// AppealQuestion is a field of the ban appeal form, answers are stored under the key
+type AppealQuestion struct {
+	Key      string `json:"key" binding:"required,max=32"`
+	Label    string `json:"label" binding:"required,max=200"`
+	Required bool   `json:"required"`
+	// MaxLength limits answer length in characters, 0 means the default of 1000
+	MaxLength int `json:"max_length,omitempty" binding:"min=0,max=4000"`
+}
+
+type AppealsConfig struct {
+	Enabled   bool             `json:"enabled"`
+	Questions []AppealQuestion `json:"questions" binding:"max=10,dive"`
+	// CooldownDays is how long a user waits after a denied appeal before appealing the same ban again
+	CooldownDays int `json:"cooldown_days" binding:"min=0,max=365"`
+}
@@ -0,0 +1,52 @@
+package objects
+
+// automod rule types
+const (
+	AutomodRuleWords    = "words"
+	AutomodRuleRegex    = "regex"
+	AutomodRuleInvites  = "invites"
+	AutomodRuleMentions = "mentions"
+	AutomodRuleCaps     = "caps"
+	AutomodRuleRate     = "rate"
+)
+
+// automod actions taken by the bot when a rule matches
+const (
+	AutomodActionDelete = "delete"
+	AutomodActionWarn   = "warn"
+	AutomodActionMute   = "mute"
+	AutomodActionKick   = "kick"
+	AutomodActionBan    = "ban"
+)
+
+// AutomodRule configures a single filter, only fields of its type are used
+type AutomodRule struct {
+	Name    string   `json:"name" binding:"required,max=64"`
+	Type    string   `json:"type" binding:"required,oneof=words regex invites mentions caps rate"`
+	Enabled bool     `json:"enabled"`
+	Actions []string `json:"actions" binding:"required,min=1,dive,oneof=delete warn mute kick ban"`
+	// MuteDurationSeconds is required by the mute action
+	MuteDurationSeconds int64    `json:"mute_duration_seconds,omitempty" binding:"min=0"`
+	ExemptRoles         []string `json:"exempt_roles,omitempty" binding:"max=100"`
+	ExemptChannels      []string `json:"exempt_channels,omitempty" binding:"max=100"`
+
+	// words: case-insensitive whole words or phrases
+	Words []string `json:"words,omitempty" binding:"max=1000,dive,max=100"`
+	// regex: RE2 patterns, a message matches if any of them matches
+	Patterns []string `json:"patterns,omitempty" binding:"max=50,dive,max=256"`
+	// invites: invite codes which may be posted
+	AllowedInvites []string `json:"allowed_invites,omitempty" binding:"max=100"`
+	// mentions: user, role and everyone mentions allowed in one message
+	MaxMentions int `json:"max_mentions,omitempty" binding:"min=0"`
+	// caps: share of uppercase letters in messages having at least caps_min_letters letters
+	CapsRatio      float64 `json:"caps_ratio,omitempty" binding:"min=0,max=1"`
+	CapsMinLetters int     `json:"caps_min_letters,omitempty" binding:"min=0"`
+	// rate: messages of one author allowed within the window
+	RateCount         int `json:"rate_count,omitempty" binding:"min=0"`
+	RateWindowSeconds int `json:"rate_window_seconds,omitempty" binding:"min=0,max=3600"`
+}
+
+type AutomodConfig struct {
+	Enabled bool          `json:"enabled"`
+	Rules   []AutomodRule `json:"rules" binding:"max=50,dive"`
+}
@@ -0,0 +1,48 @@
+package objects
+
+import "github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+
+// BaseBotPermissions are required by the bot regardless of enabled modules
+const BaseBotPermissions = discordperm.ViewChannel |
+	discordperm.SendMessages |
+	discordperm.EmbedLinks |
+	discordperm.ReadMessageHistory
+
+// LockdownBotPermissions are required by the bot to overwrite channel permissions during lockdown
+const LockdownBotPermissions = discordperm.ManageChannels | discordperm.ManageRoles
+
+// automodActionPermissions are required by the bot to take automod and escalated actions
+var automodActionPermissions = map[string]discordperm.Permissions{
+	AutomodActionDelete: discordperm.ManageMessages,
+	AutomodActionWarn:   0,
+	AutomodActionMute:   discordperm.ModerateMembers,
+	AutomodActionKick:   discordperm.KickMembers,
+	AutomodActionBan:    discordperm.BanMembers,
+}
+
+// RequiredBotPermissions returns minimal permissions bot needs to run enabled config modules
+func (c GuildConfig) RequiredBotPermissions() int64 {
+	required := BaseBotPermissions
+	if c.Data.Automod.Enabled {
+		for _, rule := range c.Data.Automod.Rules {
+			if !rule.Enabled {
+				continue
+			}
+			for _, action := range rule.Actions {
+				required = required.Add(automodActionPermissions[action])
+			}
+		}
+	}
+	if c.Data.Escalation.Enabled {
+		for _, step := range c.Data.Escalation.Steps {
+			required = required.Add(automodActionPermissions[step.Action])
+		}
+	}
+	if c.Data.Verification.Enabled {
+		required = required.Add(discordperm.ManageRoles)
+	}
+	if c.Data.Raid.Enabled && c.Data.Raid.Lockdown {
+		required = required.Add(LockdownBotPermissions)
+	}
+	return int64(required)
+}
@@ -0,0 +1,25 @@
+package objects
+
+// EscalationPoints are added to standing of the target by each case action
+type EscalationPoints struct {
+	Warn int `json:"warn" binding:"min=0,max=1000"`
+	Mute int `json:"mute" binding:"min=0,max=1000"`
+	Kick int `json:"kick" binding:"min=0,max=1000"`
+	Ban  int `json:"ban" binding:"min=0,max=1000"`
+}
+
+// EscalationStep replaces an infraction with a stronger action once standing reaches the points
+type EscalationStep struct {
+	Points int    `json:"points" binding:"min=1"`
+	Action string `json:"action" binding:"required,oneof=mute kick ban"`
+	// DurationSeconds is required by mute, a ban without duration is permanent
+	DurationSeconds int64 `json:"duration_seconds,omitempty" binding:"min=0"`
+}
+
+type EscalationConfig struct {
+	Enabled bool             `json:"enabled"`
+	Points  EscalationPoints `json:"points"`
+	// DecayDays is how long points of a case count towards standing, 0 keeps them forever
+	DecayDays int              `json:"decay_days" binding:"min=0,max=3650"`
+	Steps     []EscalationStep `json:"steps" binding:"max=20,dive"`
+}
@@ -1,27 +1,139 @@
 package objects
 
+import (
+	"encoding/json"
+	"github.com/BoggerByte/Sentinel-backend.git/pkg/modules/discordperm"
+)
+
+// GuildConfigPermissions grants access to the config to members having any of the permissions
 type GuildConfigPermissions struct {
+	Edit            int64 `json:"edit"`
+	Read            int64 `json:"read"`
+	EveryoneCanRead bool  `json:"everyone_can_read"`
+	// Lockdown allows locking the guild without access to the config
+	Lockdown int64 `json:"lockdown"`
+}
+
+// CanRead reports whether member with effective permissions may read the config, editors always may
+func (p GuildConfigPermissions) CanRead(member discordperm.Permissions) bool {
+	return p.EveryoneCanRead || p.CanEdit(member) || member.HasAny(discordperm.Permissions(p.Read))
+}
+
+// CanEdit reports whether member with effective permissions may edit the config, administrators always may
+func (p GuildConfigPermissions) CanEdit(member discordperm.Permissions) bool {
+	return member.IsAdministrator() || member.HasAny(discordperm.Permissions(p.Edit))
+}
+
+// CanLockdown reports whether member with effective permissions may lock and unlock the guild, administrators always may
+func (p GuildConfigPermissions) CanLockdown(member discordperm.Permissions) bool {
+	return member.IsAdministrator() || member.HasAny(discordperm.Permissions(p.Lockdown))
+}
+
+// CasePermissions grants access to moderation cases to members having any of the permissions
+type CasePermissions struct {
 	Edit int64 `json:"edit"`
 	Read int64 `json:"read"`
 }
 
+// CanRead reports whether member with effective permissions may read cases, editors always may
+func (p CasePermissions) CanRead(member discordperm.Permissions) bool {
+	return p.CanEdit(member) || member.HasAny(discordperm.Permissions(p.Read))
+}
+
+// CanEdit reports whether member with effective permissions may change cases, administrators always may
+func (p CasePermissions) CanEdit(member discordperm.Permissions) bool {
+	return member.IsAdministrator() || member.HasAny(discordperm.Permissions(p.Edit))
+}
+
 type GuildConfigData struct {
-	UseConfig bool `json:"use_config"`
+	UseConfig    bool               `json:"use_config"`
+	Automod      AutomodConfig      `json:"automod"`
+	Escalation   EscalationConfig   `json:"escalation"`
+	Appeals      AppealsConfig      `json:"appeals"`
+	Verification VerificationConfig `json:"verification"`
+	Raid         RaidConfig         `json:"raid"`
 }
 
 type GuildConfig struct {
-	Permissions GuildConfigPermissions `json:"permissions"`
-	Data        GuildConfigData        `json:"data"`
-	Preset      string                 `json:"preset"`
-}
-
-var DefaultGuildConfig = GuildConfig{
-	Permissions: GuildConfigPermissions{
-		Edit: 40,
-		Read: 0xfffffffffff, // @everyone permissions
-	},
-	Data: GuildConfigData{
-		UseConfig: false,
-	},
-	Preset: "default",
+	Permissions     GuildConfigPermissions `json:"permissions"`
+	CasePermissions CasePermissions        `json:"case_permissions"`
+	Data            GuildConfigData        `json:"data"`
+	Preset          string                 `json:"preset"`
+}
+
+// NewDefaultGuildConfig returns the default config, every call builds new lists so callers may modify them
+func NewDefaultGuildConfig() GuildConfig {
+	return GuildConfig{
+		Permissions: GuildConfigPermissions{
+			Edit:            int64(discordperm.Administrator | discordperm.ManageGuild),
+			Read:            0,
+			EveryoneCanRead: true,
+			Lockdown:        int64(discordperm.ManageGuild | discordperm.ManageChannels),
+		},
+		CasePermissions: CasePermissions{
+			Edit: int64(discordperm.BanMembers | discordperm.ModerateMembers),
+			Read: int64(discordperm.KickMembers | discordperm.BanMembers | discordperm.ModerateMembers),
+		},
+		Data: GuildConfigData{
+			UseConfig: false,
+			Automod: AutomodConfig{
+				Enabled: false,
+				Rules:   []AutomodRule{},
+			},
+			Escalation: EscalationConfig{
+				Enabled:   false,
+				Points:    EscalationPoints{Warn: 1, Mute: 1, Kick: 2, Ban: 3},
+				DecayDays: 30,
+				Steps: []EscalationStep{
+					{Points: 3, Action: AutomodActionMute, DurationSeconds: 3600},
+					{Points: 5, Action: AutomodActionBan},
+				},
+			},
+			Appeals: AppealsConfig{
+				Enabled: false,
+				Questions: []AppealQuestion{
+					{Key: "reason", Label: "Why should you be unbanned?", Required: true, MaxLength: 2000},
+				},
+				CooldownDays: 30,
+			},
+			Verification: VerificationConfig{
+				Enabled:              false,
+				MinAccountAgeDays:    7,
+				RequireVerifiedEmail: true,
+				Difficulty:           16,
+			},
+			Raid: RaidConfig{
+				Enabled:              false,
+				WindowSeconds:        60,
+				JoinBurst:            15,
+				NewAccountDays:       7,
+				NewAccountBurst:      8,
+				SimilarUsernameBurst: 5,
+				Lockdown:             false,
+			},
+		},
+		Preset: "default",
+	}
+}
+
+// DecodeGuildConfig decodes the stored config over the defaults.
+// Stored escalation steps and appeal questions replace the default ones as a whole,
+// so they never inherit fields of default items.
+func DecodeGuildConfig(data []byte) (GuildConfig, error) {
+	config := NewDefaultGuildConfig()
+	defaultSteps := config.Data.Escalation.Steps
+	defaultQuestions := config.Data.Appeals.Questions
+	config.Data.Escalation.Steps = nil
+	config.Data.Appeals.Questions = nil
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return GuildConfig{}, err
+	}
+	if config.Data.Escalation.Steps == nil {
+		config.Data.Escalation.Steps = defaultSteps
+	}
+	if config.Data.Appeals.Questions == nil {
+		config.Data.Appeals.Questions = defaultQuestions
+	}
+	return config, nil
 }
@@ -0,0 +1,45 @@
+package objects
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDecodeGuildConfig(t *testing.T) {
+	defaultConfig := NewDefaultGuildConfig()
+
+	custom, err := DecodeGuildConfig([]byte(`{"data":{"escalation":{"steps":[{"points":10,"action":"kick"}]}}}`))
+	require.NoError(t, err)
+	require.Equal(t, []EscalationStep{{Points: 10, Action: AutomodActionKick}}, custom.Data.Escalation.Steps)
+
+	// a config decoded next does not see steps of the previous one
+	other, err := DecodeGuildConfig([]byte(`{"data":{"escalation":{"enabled":true}}}`))
+	require.NoError(t, err)
+	require.True(t, other.Data.Escalation.Enabled)
+	require.Equal(t, defaultConfig.Data.Escalation.Steps, other.Data.Escalation.Steps)
+
+	require.Equal(t, defaultConfig, NewDefaultGuildConfig())
+
+	appealsConfig, err := DecodeGuildConfig([]byte(`{"data":{"appeals":{"questions":[{"key":"story","label":"What happened?"}]}}}`))
+	require.NoError(t, err)
+	require.Equal(t, []AppealQuestion{{Key: "story", Label: "What happened?"}}, appealsConfig.Data.Appeals.Questions)
+
+	other, err = DecodeGuildConfig([]byte(`{"data":{"appeals":{"enabled":true}}}`))
+	require.NoError(t, err)
+	require.Equal(t, defaultConfig.Data.Appeals.Questions, other.Data.Appeals.Questions)
+
+	require.Equal(t, defaultConfig, NewDefaultGuildConfig())
+
+	_, err = DecodeGuildConfig([]byte(`{"data":`))
+	require.Error(t, err)
+}
+
+func TestNewDefaultGuildConfig(t *testing.T) {
+	config := NewDefaultGuildConfig()
+	config.Data.Escalation.Steps[0].Points = 100
+	config.Data.Appeals.Questions[0].Key = "changed"
+
+	defaultConfig := NewDefaultGuildConfig()
+	require.NotEqual(t, 100, defaultConfig.Data.Escalation.Steps[0].Points)
+	require.NotEqual(t, "changed", defaultConfig.Data.Appeals.Questions[0].Key)
+}
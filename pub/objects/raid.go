@@ -0,0 +1,18 @@
+package objects
+
+// RaidConfig sets thresholds of raid detection over joins reported by the bot, a threshold of 0 disables its check
+type RaidConfig struct {
+	Enabled bool `json:"enabled"`
+	// WindowSeconds is the sliding window over which joins are counted
+	WindowSeconds int `json:"window_seconds" binding:"omitempty,min=10,max=3600"`
+	// JoinBurst is amount of joins within the window
+	JoinBurst int `json:"join_burst" binding:"min=0,max=10000"`
+	// NewAccountDays marks accounts created less than the days ago as new
+	NewAccountDays int `json:"new_account_days" binding:"min=0,max=365"`
+	// NewAccountBurst is amount of joins of new accounts within the window
+	NewAccountBurst int `json:"new_account_burst" binding:"min=0,max=10000"`
+	// SimilarUsernameBurst is amount of joins within the window whose usernames differ only in digits, case and look-alike characters
+	SimilarUsernameBurst int `json:"similar_username_burst" binding:"min=0,max=10000"`
+	// Lockdown asks the bot to lock the guild once a raid is detected
+	Lockdown bool `json:"lockdown"`
+}
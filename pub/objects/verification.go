@@ -0,0 +1,14 @@
+package objects
+
+// VerificationConfig gates new members until they pass a challenge on the web, then the bot grants the role
+type VerificationConfig struct {
+	Enabled bool `json:"enabled"`
+	// RoleID is granted to verified members, required when verification is enabled
+	RoleID string `json:"role_id" binding:"omitempty,numeric"`
+	// MinAccountAgeDays rejects Discord accounts created less than the days ago
+	MinAccountAgeDays    int  `json:"min_account_age_days" binding:"min=0,max=3650"`
+	RequireVerifiedEmail bool `json:"require_verified_email"`
+	RequireAvatar        bool `json:"require_avatar"`
+	// Difficulty is amount of leading zero bits of the proof-of-work hash, each bit doubles solving time
+	Difficulty int `json:"difficulty" binding:"min=0,max=24"`
+}